@@ -0,0 +1,242 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+)
+
+// curveFlag is a flag.Value selecting one of gnark.Curves()
+type curveFlag struct{ ecc.ID }
+
+func (f *curveFlag) String() string { return f.ID.String() }
+
+func (f *curveFlag) Set(s string) error {
+	var names []string
+	for _, curve := range gnark.Curves() {
+		if strings.EqualFold(s, curve.String()) {
+			f.ID = curve
+			return nil
+		}
+		names = append(names, curve.String())
+	}
+	return fmt.Errorf("unsupported curve, expected one of %s", strings.Join(names, ", "))
+}
+
+// backendFlag is a flag.Value selecting one of backend.Implemented()
+type backendFlag struct{ backend.ID }
+
+func (f *backendFlag) String() string { return f.ID.String() }
+
+func (f *backendFlag) Set(s string) error {
+	var names []string
+	for _, b := range backend.Implemented() {
+		if strings.EqualFold(s, b.String()) {
+			f.ID = b
+			return nil
+		}
+		names = append(names, b.String())
+	}
+	return fmt.Errorf("unsupported backend, expected one of %s", strings.Join(names, ", "))
+}
+
+// newFlagSet returns a flag set for the command name. The usage shows synopsis, the argument
+// line and the flags.
+func newFlagSet(name, arguments, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: gnark %s %s\n\n%s\n\nflags:\n", name, arguments, synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// addCCSFlags registers the -curve and -backend flags, needed to read a serialized
+// constraint system
+func addCCSFlags(fs *flag.FlagSet) (*curveFlag, *backendFlag) {
+	curve := &curveFlag{ecc.BN254}
+	b := &backendFlag{backend.GROTH16}
+	fs.Var(curve, "curve", "curve of the constraint system")
+	fs.Var(b, "backend", "backend of the constraint system (groth16 or plonk)")
+	return curve, b
+}
+
+// parse parses args and checks that the required flags are set
+func parse(fs *flag.FlagSet, args []string, required ...string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for _, name := range required {
+		if !set[name] {
+			fmt.Fprintf(fs.Output(), "missing required flag -%s\n", name)
+			fs.Usage()
+			return errUsage
+		}
+	}
+	return nil
+}
+
+// openFile opens the file at path, or stdin if path is "-"
+func openFile(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return ioutil.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// readFile decodes the file at path (or stdin if path is "-") into v
+func readFile(path string, v io.ReaderFrom) error {
+	f, err := openFile(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := v.ReadFrom(bufio.NewReader(f)); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// writeFile encodes v into the file at path (or stdout if path is "-")
+func writeFile(path string, stdout io.Writer, v io.WriterTo) error {
+	return writeFileFunc(path, stdout, func(w io.Writer) error {
+		_, err := v.WriteTo(w)
+		return err
+	})
+}
+
+// writeFileFunc calls write with a writer on the file at path (or stdout if path is "-")
+func writeFileFunc(path string, stdout io.Writer, write func(w io.Writer) error) error {
+	if path == "-" {
+		return write(stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := write(w); err != nil {
+		f.Close()
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readCCS reads a constraint system serialized with CompiledConstraintSystem.WriteTo
+func readCCS(path string, curve ecc.ID, b backend.ID) (frontend.CompiledConstraintSystem, error) {
+	var ccs frontend.CompiledConstraintSystem
+	switch b {
+	case backend.GROTH16:
+		ccs = groth16.NewCS(curve)
+	case backend.PLONK:
+		ccs = plonk.NewCS(curve)
+	default:
+		return nil, fmt.Errorf("unsupported backend %s", b)
+	}
+	if err := readFile(path, ccs); err != nil {
+		return nil, err
+	}
+	if ccs.CurveID() != curve {
+		return nil, fmt.Errorf("%s: constraint system is defined on %s, not %s", path, ccs.CurveID(), curve)
+	}
+	if ccs.GetSchema() == nil {
+		return nil, fmt.Errorf("%s: constraint system has no schema", path)
+	}
+	return ccs, nil
+}
+
+// readSRS reads a KZG SRS serialized with kzg.SRS.WriteTo
+func readSRS(path string, curve ecc.ID) (kzg.SRS, error) {
+	srs := kzg.NewSRS(curve)
+	if err := readFile(path, srs); err != nil {
+		return nil, err
+	}
+	return srs, nil
+}
+
+// readWitnessJSON reads a JSON witness matching the schema of ccs. If public is set, the
+// returned witness only holds the public variables, otherwise all the variables must be set.
+func readWitnessJSON(path string, ccs frontend.CompiledConstraintSystem, public bool) (*witness.Witness, error) {
+	return readWitness(path, ccs, public, (*witness.Witness).UnmarshalJSON)
+}
+
+// readWitness reads a witness matching the schema of ccs, decoded with unmarshal. See
+// readWitnessJSON for public.
+func readWitness(path string, ccs frontend.CompiledConstraintSystem, public bool, unmarshal func(w *witness.Witness, data []byte) error) (*witness.Witness, error) {
+	f, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := witness.New(ccs.CurveID(), ccs.GetSchema())
+	if err != nil {
+		return nil, err
+	}
+	if err := unmarshal(w, data); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return fitWitness(path, w, public)
+}
+
+// fitWitness checks the number of values of w, and drops the secret ones if public is set
+func fitWitness(path string, w *witness.Witness, public bool) (*witness.Witness, error) {
+	nbPublic, nbFull := w.Schema.NbPublic, w.Schema.NbPublic+w.Schema.NbSecret
+	switch w.Vector.Len() {
+	case nbFull:
+		if public {
+			return w.Public()
+		}
+		return w, nil
+	case nbPublic:
+		if public {
+			return w, nil
+		}
+		return nil, fmt.Errorf("%s: %w: secret variables are missing", path, witness.ErrInvalidWitness)
+	default:
+		return nil, fmt.Errorf("%s: %w: got %d values, expected %d", path, witness.ErrInvalidWitness, w.Vector.Len(), nbFull)
+	}
+}
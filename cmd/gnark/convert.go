@@ -0,0 +1,173 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/circom"
+)
+
+func runConvert(args []string, stdout io.Writer) error {
+	fs := newFlagSet("convert", "[flags] -from <format> -to <format> <input>",
+		"Converts a constraint system. The formats are\n\n"+
+			"  gnark   CompiledConstraintSystem.WriteTo (input and output)\n"+
+			"  circom  circom .r1cs, groth16 only (input and output)\n"+
+			"  json    see compiled.JSONConstraintSystem (output)\n"+
+			"  smt     SMT-LIB2 uniqueness query for the wire named by -wire (output)\n\n"+
+			"The curve of a circom input is deduced from its prime.")
+	curve, b := addCCSFlags(fs)
+	from := fs.String("from", "gnark", "input format: gnark or circom")
+	to := fs.String("to", "", "output format: gnark, circom, json or smt")
+	wire := fs.String("wire", "", "label of the secret or internal wire checked for uniqueness (smt only)")
+	out := fs.String("o", "-", "output file")
+	if err := parse(fs, args, "to"); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	var ccs frontend.CompiledConstraintSystem
+	var err error
+	switch *from {
+	case "gnark":
+		ccs, err = readCCS(fs.Arg(0), curve.ID, b.ID)
+	case "circom":
+		if b.ID != backend.GROTH16 {
+			return errors.New("circom constraint systems are only supported by groth16")
+		}
+		ccs, err = readCircomR1CS(fs.Arg(0))
+	default:
+		return fmt.Errorf("unknown input format %q", *from)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch *to {
+	case "gnark":
+		return writeFile(*out, stdout, ccs)
+	case "circom":
+		return writeFileFunc(*out, stdout, ccs.ExportR1CS)
+	case "json":
+		return writeFileFunc(*out, stdout, ccs.ExportJSON)
+	case "smt":
+		if *wire == "" {
+			return errors.New("smt output needs a wire (-wire)")
+		}
+		return writeFileFunc(*out, stdout, func(w io.Writer) error {
+			return ccs.ExportSMT(w, *wire)
+		})
+	default:
+		return fmt.Errorf("unknown output format %q", *to)
+	}
+}
+
+func runConvertWitness(args []string, stdout io.Writer) error {
+	fs := newFlagSet("convert-witness", "[flags] -ccs <file> -from <format> -to <format> <input>",
+		"Converts a witness of a constraint system (in gnark format). The formats are\n\n"+
+			"  json    JSON object matching the circuit structure (input and output)\n"+
+			"  binary  witness.MarshalBinary (input and output)\n"+
+			"  wtns    circom .wtns, for circuits imported from circom (input)")
+	curve, b := addCCSFlags(fs)
+	ccsPath := fs.String("ccs", "", "constraint system (gnark format)")
+	from := fs.String("from", "", "input format: json, binary or wtns")
+	to := fs.String("to", "", "output format: json or binary")
+	public := fs.Bool("public", false, "only keep the public variables")
+	out := fs.String("o", "-", "output file")
+	if err := parse(fs, args, "ccs", "from", "to"); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	ccs, err := readCCS(*ccsPath, curve.ID, b.ID)
+	if err != nil {
+		return err
+	}
+
+	var w *witness.Witness
+	switch *from {
+	case "json":
+		w, err = readWitnessJSON(fs.Arg(0), ccs, *public)
+	case "binary":
+		w, err = readWitness(fs.Arg(0), ccs, *public, (*witness.Witness).UnmarshalBinary)
+	case "wtns":
+		w, err = readCircomWitness(fs.Arg(0), ccs, *public)
+	default:
+		return fmt.Errorf("unknown input format %q", *from)
+	}
+	if err != nil {
+		return err
+	}
+
+	var marshal func() ([]byte, error)
+	switch *to {
+	case "json":
+		marshal = w.MarshalJSON
+	case "binary":
+		marshal = w.MarshalBinary
+	default:
+		return fmt.Errorf("unknown output format %q", *to)
+	}
+	data, err := marshal()
+	if err != nil {
+		return err
+	}
+	return writeFileFunc(*out, stdout, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// readCircomR1CS reads a circom .r1cs file
+func readCircomR1CS(path string) (frontend.CompiledConstraintSystem, error) {
+	f, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	ccs, err := circom.ReadR1CS(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ccs, nil
+}
+
+// readCircomWitness reads a circom .wtns file. See readWitnessJSON for public.
+func readCircomWitness(path string, ccs frontend.CompiledConstraintSystem, public bool) (*witness.Witness, error) {
+	f, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	w, err := circom.ReadWitness(bufio.NewReader(f), ccs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return fitWitness(path, w, public)
+}
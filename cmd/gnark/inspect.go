@@ -0,0 +1,106 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// ccsInfo is the output of the inspect command
+type ccsInfo struct {
+	Curve          string   `json:"curve"`
+	Backend        string   `json:"backend"`
+	NbConstraints  int      `json:"nbConstraints"`
+	NbCoefficients int      `json:"nbCoefficients"`
+	NbInternal     int      `json:"nbInternal"`
+	NbSecret       int      `json:"nbSecret"`
+	NbPublic       int      `json:"nbPublic"`
+	Public         []string `json:"public"`
+	Secret         []string `json:"secret"`
+}
+
+func runInspect(args []string, stdout io.Writer) error {
+	fs := newFlagSet("inspect", "[flags] <ccs>", "Prints the curve, backend, number of constraints and variables and the schema of a constraint system.")
+	curve, b := addCCSFlags(fs)
+	asJSON := fs.Bool("json", false, "print as JSON")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	ccs, err := readCCS(fs.Arg(0), curve.ID, b.ID)
+	if err != nil {
+		return err
+	}
+
+	info := ccsInfo{
+		Curve:          ccs.CurveID().String(),
+		Backend:        b.String(),
+		NbConstraints:  ccs.GetNbConstraints(),
+		NbCoefficients: ccs.GetNbCoefficients(),
+	}
+	info.NbInternal, info.NbSecret, info.NbPublic = ccs.GetNbVariables()
+	if info.Public, info.Secret, err = inputNames(ccs.GetSchema()); err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+
+	fmt.Fprintf(stdout, "curve:          %s\n", info.Curve)
+	fmt.Fprintf(stdout, "backend:        %s\n", info.Backend)
+	fmt.Fprintf(stdout, "constraints:    %d\n", info.NbConstraints)
+	fmt.Fprintf(stdout, "coefficients:   %d\n", info.NbCoefficients)
+	fmt.Fprintf(stdout, "variables:      %d public, %d secret, %d internal\n", info.NbPublic, info.NbSecret, info.NbInternal)
+	fmt.Fprintln(stdout, "public inputs:")
+	for _, name := range info.Public {
+		fmt.Fprintln(stdout, "  "+name)
+	}
+	fmt.Fprintln(stdout, "secret inputs:")
+	for _, name := range info.Secret {
+		fmt.Fprintln(stdout, "  "+name)
+	}
+	return nil
+}
+
+// inputNames returns the fully qualified names of the public and secret inputs of s, in
+// witness order
+func inputNames(s *schema.Schema) (public, secret []string, err error) {
+	var a int
+	instance := s.Instantiate(reflect.TypeOf(a), false)
+	_, err = schema.Parse(instance, reflect.TypeOf(a), func(visibility schema.Visibility, name string, _ reflect.Value) error {
+		switch visibility {
+		case schema.Public:
+			public = append(public, name)
+		case schema.Secret:
+			secret = append(secret, name)
+		}
+		return nil
+	})
+	return
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command gnark works on serialized gnark artifacts: compiled constraint systems (as written
+// by CompiledConstraintSystem.WriteTo), proving and verifying keys, proofs and witnesses.
+//
+// Usage:
+//
+//	gnark <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	inspect          print the curve, backend, size and schema of a constraint system
+//	setup            run the groth16 or plonk setup of a constraint system
+//	prove            create a proof from a JSON witness
+//	verify           verify a proof against a verifying key and a JSON public witness
+//	solidity         export a groth16 verifying key as a Solidity verifier contract
+//	convert          convert a constraint system between gnark, circom and JSON formats
+//	convert-witness  convert a witness between JSON, binary and circom formats
+//
+// Serialized constraint systems don't record their curve nor their backend: commands reading
+// one take -curve (default bn254) and -backend (default groth16) flags.
+//
+// The CLI only knows the built-in hints (see backend/hint); circuits using custom hints can't
+// be proven with it.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is a gnark CLI sub command
+type command struct {
+	name     string
+	synopsis string
+	run      func(args []string, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"inspect", "print the curve, backend, size and schema of a constraint system", runInspect},
+	{"setup", "run the groth16 or plonk setup of a constraint system", runSetup},
+	{"prove", "create a proof from a JSON witness", runProve},
+	{"verify", "verify a proof against a verifying key and a JSON public witness", runVerify},
+	{"solidity", "export a groth16 verifying key as a Solidity verifier contract", runSolidity},
+	{"convert", "convert a constraint system between gnark, circom and JSON formats", runConvert},
+	{"convert-witness", "convert a witness between JSON, binary and circom formats", runConvertWitness},
+}
+
+// errUsage is returned when the command line is invalid; the usage has already been printed
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	err := run(os.Args[1:], os.Stdout)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		fmt.Fprintln(os.Stderr, "gnark:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command named by args[0]
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return errUsage
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:], stdout)
+		}
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		usage(stdout)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "gnark: unknown command %q\n", args[0])
+	usage(os.Stderr)
+	return errUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: gnark <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-16s %s\n", c.name, c.synopsis)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "run 'gnark <command> -h' for the flags of a command")
+}
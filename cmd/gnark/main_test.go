@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable `gnark:"x"`
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(circuit.X, circuit.X, circuit.X)
+	api.AssertIsEqual(circuit.Y, api.Add(x3, circuit.X, 5))
+	return nil
+}
+
+// setupDir compiles cubicCircuit for b in a temporary directory, with a valid witness, a
+// public witness and, for plonk, a KZG SRS
+func setupDir(t *testing.T, b backend.ID) string {
+	assert := require.New(t)
+	dir := t.TempDir()
+
+	newBuilder := r1cs.NewBuilder
+	if b == backend.PLONK {
+		newBuilder = scs.NewBuilder
+	}
+	ccs, err := frontend.Compile(ecc.BN254, newBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	assert.NoError(writeFile(filepath.Join(dir, "cubic.ccs"), nil, ccs))
+
+	if b == backend.PLONK {
+		srs, err := test.NewKZGSRS(ccs)
+		assert.NoError(err)
+		assert.NoError(writeFile(filepath.Join(dir, "srs"), nil, srs))
+	}
+
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "witness.json"), []byte(`{"x": 3, "Y": "35"}`), 0600))
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "public.json"), []byte(`{"Y": 35}`), 0600))
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{"Y": 36}`), 0600))
+
+	return dir
+}
+
+func runCmd(args ...string) (string, error) {
+	var stdout bytes.Buffer
+	err := run(args, &stdout)
+	return stdout.String(), err
+}
+
+func TestProveVerify(t *testing.T) {
+	for _, b := range backend.Implemented() {
+		t.Run(b.String(), func(t *testing.T) {
+			assert := require.New(t)
+			dir := setupDir(t, b)
+			path := func(name string) string { return filepath.Join(dir, name) }
+
+			var srs []string
+			if b == backend.PLONK {
+				srs = []string{"-srs", path("srs")}
+			}
+			ccsFlags := []string{"-curve", "bn254", "-backend", b.String()}
+
+			out, err := runCmd(append([]string{"inspect"}, append(ccsFlags, path("cubic.ccs"))...)...)
+			assert.NoError(err)
+			assert.Contains(out, "backend:        "+b.String())
+			assert.Contains(out, "public inputs:\n  Y\nsecret inputs:\n  x\n")
+
+			args := append([]string{"setup"}, ccsFlags...)
+			args = append(args, srs...)
+			_, err = runCmd(append(args, "-pk", path("pk"), "-vk", path("vk"), path("cubic.ccs"))...)
+			assert.NoError(err)
+
+			args = append([]string{"prove"}, ccsFlags...)
+			args = append(args, srs...)
+			_, err = runCmd(append(args, "-pk", path("pk"), "-witness", path("witness.json"), "-o", path("proof"), path("cubic.ccs"))...)
+			assert.NoError(err)
+
+			// a public witness can't be used to prove
+			_, err = runCmd(append(args, "-pk", path("pk"), "-witness", path("public.json"), "-o", path("proof2"), path("cubic.ccs"))...)
+			assert.Error(err)
+
+			args = append([]string{"verify"}, ccsFlags...)
+			args = append(args, srs...)
+			out, err = runCmd(append(args, "-vk", path("vk"), "-proof", path("proof"), "-public", path("public.json"), path("cubic.ccs"))...)
+			assert.NoError(err)
+			assert.Equal("proof is valid\n", out)
+
+			// secret values are ignored by verify
+			_, err = runCmd(append(args, "-vk", path("vk"), "-proof", path("proof"), "-public", path("witness.json"), path("cubic.ccs"))...)
+			assert.NoError(err)
+
+			_, err = runCmd(append(args, "-vk", path("vk"), "-proof", path("proof"), "-public", path("bad.json"), path("cubic.ccs"))...)
+			assert.Error(err)
+
+			if b == backend.GROTH16 {
+				out, err = runCmd("solidity", path("vk"))
+				assert.NoError(err)
+				assert.Contains(out, "contract Verifier")
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	assert := require.New(t)
+	dir := setupDir(t, backend.GROTH16)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	// gnark -> circom -> gnark
+	_, err := runCmd("convert", "-to", "circom", "-o", path("cubic.r1cs"), path("cubic.ccs"))
+	assert.NoError(err)
+	_, err = runCmd("convert", "-from", "circom", "-to", "gnark", "-o", path("imported.ccs"), path("cubic.r1cs"))
+	assert.NoError(err)
+	out, err := runCmd("inspect", "-json", path("imported.ccs"))
+	assert.NoError(err)
+	var info ccsInfo
+	assert.NoError(json.Unmarshal([]byte(out), &info))
+	assert.Equal(ecc.BN254.String(), info.Curve)
+	assert.Equal(0, info.NbInternal)
+
+	// json and smt
+	out, err = runCmd("convert", "-to", "json", path("cubic.ccs"))
+	assert.NoError(err)
+	assert.True(json.Valid([]byte(out)))
+	out, err = runCmd("convert", "-to", "smt", "-wire", "x", path("cubic.ccs"))
+	assert.NoError(err)
+	assert.True(strings.HasSuffix(out, "(check-sat)\n"))
+	_, err = runCmd("convert", "-to", "smt", path("cubic.ccs"))
+	assert.Error(err)
+
+	// plonk constraint systems can't be exported to circom
+	_, err = runCmd("convert", "-from", "circom", "-backend", "plonk", "-to", "gnark", path("cubic.r1cs"))
+	assert.Error(err)
+
+	// witness json -> binary -> json
+	_, err = runCmd("convert-witness", "-ccs", path("cubic.ccs"), "-from", "json", "-to", "binary", "-o", path("witness.bin"), path("witness.json"))
+	assert.NoError(err)
+	out, err = runCmd("convert-witness", "-ccs", path("cubic.ccs"), "-from", "binary", "-to", "json", "-public", path("witness.bin"))
+	assert.NoError(err)
+	assert.JSONEq(`{"Y": 35}`, out)
+}
+
+func TestUsage(t *testing.T) {
+	assert := require.New(t)
+
+	out, err := runCmd("help")
+	assert.NoError(err)
+	for _, c := range commands {
+		assert.Contains(out, c.name)
+	}
+
+	devNull, err := os.Open(os.DevNull)
+	assert.NoError(err)
+	defer devNull.Close()
+	stderr := os.Stderr
+	os.Stderr = devNull
+	defer func() { os.Stderr = stderr }()
+
+	_, err = runCmd("unknown")
+	assert.ErrorIs(err, errUsage)
+	_, err = runCmd("setup", "cubic.ccs")
+	assert.ErrorIs(err, errUsage)
+	_, err = runCmd("inspect", "-curve", "secp256k1", "cubic.ccs")
+	assert.ErrorIs(err, errUsage)
+}
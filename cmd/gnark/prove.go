@@ -0,0 +1,95 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+)
+
+func runProve(args []string, stdout io.Writer) error {
+	fs := newFlagSet("prove", "[flags] -pk <file> -witness <file> <ccs>",
+		"Solves the constraint system with a JSON witness and writes the proof.\n\n"+
+			"The witness is a JSON object matching the circuit structure, for example\n"+
+			"{\"X\": 3, \"Y\": \"35\"}. plonk also needs the KZG SRS used at setup (-srs).")
+	curve, b := addCCSFlags(fs)
+	pkPath := fs.String("pk", "", "proving key")
+	witnessPath := fs.String("witness", "", "full witness (JSON)")
+	srsPath := fs.String("srs", "", "KZG SRS (plonk only)")
+	proofPath := fs.String("o", "-", "output proof")
+	if err := parse(fs, args, "pk", "witness"); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	ccs, err := readCCS(fs.Arg(0), curve.ID, b.ID)
+	if err != nil {
+		return err
+	}
+	fullWitness, err := readWitnessJSON(*witnessPath, ccs, false)
+	if err != nil {
+		return err
+	}
+
+	var proof io.WriterTo
+	switch b.ID {
+	case backend.GROTH16:
+		proof, err = proveGroth16(ccs, *pkPath, fullWitness)
+	case backend.PLONK:
+		proof, err = provePlonk(ccs, *pkPath, *srsPath, fullWitness)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeFile(*proofPath, stdout, proof)
+}
+
+func proveGroth16(ccs frontend.CompiledConstraintSystem, pkPath string, fullWitness *witness.Witness) (groth16.Proof, error) {
+	pk := groth16.NewProvingKey(ccs.CurveID())
+	if err := readFile(pkPath, pk); err != nil {
+		return nil, err
+	}
+	return groth16.Prove(ccs, pk, fullWitness)
+}
+
+func provePlonk(ccs frontend.CompiledConstraintSystem, pkPath, srsPath string, fullWitness *witness.Witness) (plonk.Proof, error) {
+	if srsPath == "" {
+		return nil, errors.New("plonk needs the KZG SRS used at setup (-srs)")
+	}
+	pk := plonk.NewProvingKey(ccs.CurveID())
+	if err := readFile(pkPath, pk); err != nil {
+		return nil, err
+	}
+	srs, err := readSRS(srsPath, ccs.CurveID())
+	if err != nil {
+		return nil, err
+	}
+	if err := pk.InitKZG(srs); err != nil {
+		return nil, err
+	}
+	return plonk.Prove(ccs, pk, fullWitness)
+}
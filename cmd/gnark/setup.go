@@ -0,0 +1,78 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+)
+
+func runSetup(args []string, stdout io.Writer) error {
+	fs := newFlagSet("setup", "[flags] -pk <file> -vk <file> <ccs>",
+		"Runs the setup of a constraint system and writes the proving and verifying keys.\n\n"+
+			"plonk needs a KZG SRS (-srs), as written by kzg.SRS.WriteTo. groth16 samples the toxic\n"+
+			"waste locally: the keys are not suitable for production.")
+	curve, b := addCCSFlags(fs)
+	srsPath := fs.String("srs", "", "KZG SRS (plonk only)")
+	pkPath := fs.String("pk", "", "output proving key")
+	vkPath := fs.String("vk", "", "output verifying key")
+	if err := parse(fs, args, "pk", "vk"); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	ccs, err := readCCS(fs.Arg(0), curve.ID, b.ID)
+	if err != nil {
+		return err
+	}
+
+	var pk, vk io.WriterTo
+	switch b.ID {
+	case backend.GROTH16:
+		if *srsPath != "" {
+			return errors.New("-srs is only used by plonk")
+		}
+		fmt.Fprintln(os.Stderr, "warning: groth16 setup samples its toxic waste locally, do not use the keys in production")
+		pk, vk, err = groth16.Setup(ccs)
+	case backend.PLONK:
+		if *srsPath == "" {
+			return errors.New("plonk setup needs a KZG SRS (-srs)")
+		}
+		srs, errSRS := readSRS(*srsPath, curve.ID)
+		if errSRS != nil {
+			return errSRS
+		}
+		pk, vk, err = plonk.Setup(ccs, srs)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := writeFile(*pkPath, stdout, pk); err != nil {
+		return err
+	}
+	return writeFile(*vkPath, stdout, vk)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+func runSolidity(args []string, stdout io.Writer) error {
+	fs := newFlagSet("solidity", "[flags] <vk>",
+		"Writes a Solidity verifier contract for a groth16 verifying key. Only bn254 is supported.")
+	curve := &curveFlag{ecc.BN254}
+	fs.Var(curve, "curve", "curve of the verifying key")
+	out := fs.String("o", "-", "output contract")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	vk := groth16.NewVerifyingKey(curve.ID)
+	if err := readFile(fs.Arg(0), vk); err != nil {
+		return err
+	}
+	return writeFileFunc(*out, stdout, vk.ExportSolidity)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+func runVerify(args []string, stdout io.Writer) error {
+	fs := newFlagSet("verify", "[flags] -vk <file> -proof <file> -public <file> <ccs>",
+		"Verifies a proof against a verifying key and a JSON public witness. The constraint\n"+
+			"system provides the schema of the witness; secret values in the witness are ignored.\n"+
+			"plonk also needs the KZG SRS used at setup (-srs).")
+	curve, b := addCCSFlags(fs)
+	vkPath := fs.String("vk", "", "verifying key")
+	proofPath := fs.String("proof", "", "proof")
+	publicPath := fs.String("public", "", "public witness (JSON)")
+	srsPath := fs.String("srs", "", "KZG SRS (plonk only)")
+	if err := parse(fs, args, "vk", "proof", "public"); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	ccs, err := readCCS(fs.Arg(0), curve.ID, b.ID)
+	if err != nil {
+		return err
+	}
+	publicWitness, err := readWitnessJSON(*publicPath, ccs, true)
+	if err != nil {
+		return err
+	}
+
+	switch b.ID {
+	case backend.GROTH16:
+		err = verifyGroth16(curve.ID, *vkPath, *proofPath, publicWitness)
+	case backend.PLONK:
+		err = verifyPlonk(curve.ID, *vkPath, *proofPath, *srsPath, publicWitness)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, "proof is valid")
+	return nil
+}
+
+func verifyGroth16(curve ecc.ID, vkPath, proofPath string, publicWitness *witness.Witness) error {
+	vk := groth16.NewVerifyingKey(curve)
+	if err := readFile(vkPath, vk); err != nil {
+		return err
+	}
+	proof := groth16.NewProof(curve)
+	if err := readFile(proofPath, proof); err != nil {
+		return err
+	}
+	return groth16.Verify(proof, vk, publicWitness)
+}
+
+func verifyPlonk(curve ecc.ID, vkPath, proofPath, srsPath string, publicWitness *witness.Witness) error {
+	if srsPath == "" {
+		return errors.New("plonk needs the KZG SRS used at setup (-srs)")
+	}
+	vk := plonk.NewVerifyingKey(curve)
+	if err := readFile(vkPath, vk); err != nil {
+		return err
+	}
+	srs, err := readSRS(srsPath, curve)
+	if err != nil {
+		return err
+	}
+	if err := vk.InitKZG(srs); err != nil {
+		return err
+	}
+	proof := plonk.NewProof(curve)
+	if err := readFile(proofPath, proof); err != nil {
+		return err
+	}
+	return plonk.Verify(proof, vk, publicWitness)
+}
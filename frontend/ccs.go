@@ -48,4 +48,16 @@ type CompiledConstraintSystem interface {
 
 	// GetConstraints return a human readable representation of the constraints
 	GetConstraints() [][]string
+
+	// ExportR1CS writes the constraint system in the circom binary .r1cs format
+	// this will return an error if the constraint system is not a R1CS
+	ExportR1CS(w io.Writer) error
+
+	// ExportJSON writes the constraint system in the JSON format described by
+	// compiled.JSONConstraintSystem
+	ExportJSON(w io.Writer) error
+
+	// ExportSMT writes a SMT-LIB2 query that is unsatisfiable if and only if the value of
+	// the secret or internal wire labelled output is uniquely determined by the public inputs
+	ExportSMT(w io.Writer, output string) error
 }
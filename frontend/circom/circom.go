@@ -0,0 +1,244 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package circom converts circuits compiled with circom (.r1cs files) and their witnesses
+// (.wtns files) into gnark constraint systems and witnesses.
+//
+// A gnark R1CS can be exported back to the .r1cs format with CompiledConstraintSystem.ExportR1CS.
+//
+// circom orders wires as [one | public outputs | public inputs | private inputs | internal],
+// gnark as [one | public | secret | internal]. Unlike gnark, the values of the internal wires
+// of a circom circuit are computed by the circom witness generator and not by solving the
+// constraints. Hence, an imported circuit has
+//
+//	public variables = circom public outputs ∥ circom public inputs
+//	secret variables = circom private inputs ∥ circom internal wires
+//	no internal variables
+//
+// such that the constraint system solver only checks the constraints. The witness read from a
+// .wtns file is then [publicVariables | secretVariables], and its public part is the one
+// expected by groth16.Verify.
+//
+// The schema of an imported circuit has two fields, Public and Secret, of size the number of
+// public and secret variables respectively.
+package circom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/compiled"
+	"github.com/consensys/gnark/frontend/cs"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/circom"
+
+	bls12377r1cs "github.com/consensys/gnark/internal/backend/bls12-377/cs"
+	bls12381r1cs "github.com/consensys/gnark/internal/backend/bls12-381/cs"
+	bls24315r1cs "github.com/consensys/gnark/internal/backend/bls24-315/cs"
+	bn254r1cs "github.com/consensys/gnark/internal/backend/bn254/cs"
+	bw6633r1cs "github.com/consensys/gnark/internal/backend/bw6-633/cs"
+	bw6761r1cs "github.com/consensys/gnark/internal/backend/bw6-761/cs"
+)
+
+var (
+	ErrUnsupportedField = errors.New("prime field doesn't match any curve supported by gnark")
+)
+
+// ReadR1CS reads a circom .r1cs file and returns the equivalent R1CS on the curve whose
+// scalar field matches the prime of the file
+func ReadR1CS(r io.Reader) (frontend.CompiledConstraintSystem, error) {
+	var cr1cs circom.R1CS
+	if _, err := cr1cs.ReadFrom(r); err != nil {
+		return nil, err
+	}
+
+	curveID, err := curveFromPrime(cr1cs.Prime)
+	if err != nil {
+		return nil, err
+	}
+
+	nbPublic := int(cr1cs.NbPubOut + cr1cs.NbPubIn)
+	nbSecret := int(cr1cs.NbWires) - 1 - nbPublic
+
+	res := compiled.R1CS{
+		ConstraintSystem: compiled.ConstraintSystem{
+			Schema:             newSchema(nbPublic, nbSecret),
+			NbPublicVariables:  nbPublic + 1,
+			NbSecretVariables:  nbSecret,
+			Public:             make([]string, nbPublic+1),
+			Secret:             make([]string, nbSecret),
+			MDebug:             make(map[int]int),
+			MHints:             make(map[int]*compiled.Hint),
+			MHintsDependencies: make(map[hint.ID]string),
+			CurveID:            curveID,
+		},
+		Constraints: make([]compiled.R1C, len(cr1cs.Constraints)),
+	}
+	res.Public[0] = "one"
+	for i := 1; i < len(res.Public); i++ {
+		res.Public[i] = wireName(cr1cs.WireToLabel, i)
+	}
+	for i := 0; i < len(res.Secret); i++ {
+		res.Secret[i] = wireName(cr1cs.WireToLabel, i+res.NbPublicVariables)
+	}
+
+	// coefficients larger than (p-1)/2 are stored as their negative counterpart,
+	// such that -1 maps to compiled.CoeffIdMinusOne
+	st := cs.NewCoeffTable()
+	halfPrime := new(big.Int).Rsh(cr1cs.Prime, 1)
+	var coeff big.Int
+	toLinearExpression := func(l circom.LinearCombination) compiled.LinearExpression {
+		res := make(compiled.LinearExpression, len(l))
+		for i := 0; i < len(l); i++ {
+			coeff.Mod(&l[i].Coeff, cr1cs.Prime)
+			if coeff.Cmp(halfPrime) > 0 {
+				coeff.Sub(&coeff, cr1cs.Prime)
+			}
+			visibility := schema.Secret
+			if int(l[i].WireID) < nbPublic+1 {
+				visibility = schema.Public
+			}
+			res[i] = compiled.Pack(int(l[i].WireID), st.CoeffID(&coeff), visibility)
+		}
+		return res
+	}
+	for i, c := range cr1cs.Constraints {
+		res.Constraints[i] = compiled.R1C{
+			L: toLinearExpression(c.A),
+			R: toLinearExpression(c.B),
+			O: toLinearExpression(c.C),
+		}
+	}
+
+	// all wires are inputs, constraints are independent
+	if len(res.Constraints) != 0 {
+		level := make([]int, len(res.Constraints))
+		for i := range level {
+			level[i] = i
+		}
+		res.Levels = [][]int{level}
+	}
+
+	switch curveID {
+	case ecc.BLS12_377:
+		return bls12377r1cs.NewR1CS(res, st.Coeffs), nil
+	case ecc.BLS12_381:
+		return bls12381r1cs.NewR1CS(res, st.Coeffs), nil
+	case ecc.BN254:
+		return bn254r1cs.NewR1CS(res, st.Coeffs), nil
+	case ecc.BW6_761:
+		return bw6761r1cs.NewR1CS(res, st.Coeffs), nil
+	case ecc.BW6_633:
+		return bw6633r1cs.NewR1CS(res, st.Coeffs), nil
+	case ecc.BLS24_315:
+		return bls24315r1cs.NewR1CS(res, st.Coeffs), nil
+	default:
+		panic("not implemented")
+	}
+}
+
+// ReadWitness reads a circom .wtns file and returns the full witness of ccs
+//
+// ccs is expected to be the result of ReadR1CS on the matching .r1cs file.
+func ReadWitness(r io.Reader, ccs frontend.CompiledConstraintSystem) (*witness.Witness, error) {
+	var wtns circom.Witness
+	if _, err := wtns.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	curveID, err := curveFromPrime(wtns.Prime)
+	if err != nil {
+		return nil, err
+	}
+	if curveID != ccs.CurveID() {
+		return nil, fmt.Errorf("%w: witness is on %s, constraint system on %s", witness.ErrInvalidWitness, curveID, ccs.CurveID())
+	}
+
+	s := ccs.GetSchema()
+	nbInputs := s.NbPublic + s.NbSecret
+	if len(wtns.Values) != nbInputs+1 {
+		return nil, fmt.Errorf("%w: got %d values, expected %d", witness.ErrInvalidWitness, len(wtns.Values), nbInputs+1)
+	}
+	if wtns.Values[0].Cmp(big.NewInt(1)) != 0 {
+		return nil, fmt.Errorf("%w: wire 0 must be equal to 1", witness.ErrInvalidWitness)
+	}
+
+	// re-encode the values following the gnark witness binary protocol
+	// [uint32(nbElements) | publicVariables | secretVariables] (without the one wire)
+	frBytes := curveID.Info().Fr.Bytes
+	buf := make([]byte, 4+nbInputs*frBytes)
+	binary.BigEndian.PutUint32(buf[:4], uint32(nbInputs))
+	modulus := curveID.Info().Fr.Modulus()
+	for i := 1; i < len(wtns.Values); i++ {
+		if wtns.Values[i].Cmp(modulus) >= 0 {
+			return nil, fmt.Errorf("%w: value of wire %d is not reduced", witness.ErrInvalidWitness, i)
+		}
+		offset := 4 + (i-1)*frBytes
+		wtns.Values[i].FillBytes(buf[offset : offset+frBytes])
+	}
+
+	w, err := witness.New(curveID, s)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.UnmarshalBinary(buf); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// curveFromPrime returns the curve whose scalar field modulus is prime
+func curveFromPrime(prime *big.Int) (ecc.ID, error) {
+	for _, curveID := range gnark.Curves() {
+		if curveID.Info().Fr.Modulus().Cmp(prime) == 0 {
+			return curveID, nil
+		}
+	}
+	return ecc.UNKNOWN, fmt.Errorf("%w: %s", ErrUnsupportedField, prime.String())
+}
+
+// newSchema returns the schema of an imported circuit
+func newSchema(nbPublic, nbSecret int) *schema.Schema {
+	s := &schema.Schema{NbPublic: nbPublic, NbSecret: nbSecret}
+	if nbPublic != 0 {
+		s.Fields = append(s.Fields, schema.Field{
+			Name:       "Public",
+			Visibility: schema.Public,
+			Type:       schema.Array,
+			ArraySize:  nbPublic,
+		})
+	}
+	if nbSecret != 0 {
+		s.Fields = append(s.Fields, schema.Field{
+			Name:       "Secret",
+			Visibility: schema.Secret,
+			Type:       schema.Array,
+			ArraySize:  nbSecret,
+		})
+	}
+	return s
+}
+
+func wireName(wireToLabel []uint64, wireID int) string {
+	return fmt.Sprintf("w%d", wireToLabel[wireID])
+}
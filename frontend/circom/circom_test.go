@@ -0,0 +1,196 @@
+package circom
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/internal/circom"
+	"github.com/stretchr/testify/require"
+)
+
+// cubicR1CS returns the circom equivalent of
+//
+//	template Cubic() {
+//		signal input x;
+//		signal output out;
+//		signal x2;
+//		signal x3;
+//		x2 <== x * x;
+//		x3 <== x2 * x;
+//		out <== x3 + x + 5;
+//	}
+//
+// wires: [one, out, x, x2, x3]
+func cubicR1CS(prime *big.Int) *circom.R1CS {
+	term := func(wireID uint32, coeff int64) circom.Term {
+		var t circom.Term
+		t.WireID = wireID
+		t.Coeff.SetInt64(coeff)
+		t.Coeff.Mod(&t.Coeff, prime)
+		return t
+	}
+	return &circom.R1CS{
+		Header: circom.Header{
+			Prime:         prime,
+			NbWires:       5,
+			NbPubOut:      1,
+			NbPrvIn:       1,
+			NbLabels:      5,
+			NbConstraints: 3,
+		},
+		Constraints: []circom.Constraint{
+			{
+				A: circom.LinearCombination{term(2, -1)},
+				B: circom.LinearCombination{term(2, 1)},
+				C: circom.LinearCombination{term(3, -1)},
+			},
+			{
+				A: circom.LinearCombination{term(3, 1)},
+				B: circom.LinearCombination{term(2, 1)},
+				C: circom.LinearCombination{term(4, 1)},
+			},
+			{
+				C: circom.LinearCombination{term(0, 5), term(1, -1), term(2, 1), term(4, 1)},
+			},
+		},
+		WireToLabel: []uint64{0, 1, 2, 3, 4},
+	}
+}
+
+func cubicWitness(prime *big.Int, values ...int64) *circom.Witness {
+	w := &circom.Witness{Prime: prime, Values: make([]big.Int, len(values))}
+	for i, v := range values {
+		w.Values[i].SetInt64(v)
+	}
+	return w
+}
+
+func TestImportGroth16(t *testing.T) {
+	for _, curveID := range gnark.Curves() {
+		t.Run(curveID.String(), func(t *testing.T) {
+			assert := require.New(t)
+			prime := curveID.Info().Fr.Modulus()
+
+			var buf bytes.Buffer
+			_, err := cubicR1CS(prime).WriteTo(&buf)
+			assert.NoError(err)
+
+			ccs, err := ReadR1CS(&buf)
+			assert.NoError(err)
+			assert.Equal(curveID, ccs.CurveID())
+			assert.Equal(3, ccs.GetNbConstraints())
+			internal, secret, public := ccs.GetNbVariables()
+			assert.Equal(0, internal)
+			assert.Equal(3, secret)
+			assert.Equal(2, public)
+
+			buf.Reset()
+			_, err = cubicWitness(prime, 1, 35, 3, 9, 27).WriteTo(&buf)
+			assert.NoError(err)
+			fullWitness, err := ReadWitness(&buf, ccs)
+			assert.NoError(err)
+			assert.NoError(ccs.IsSolved(fullWitness))
+
+			publicWitness, err := fullWitness.Public()
+			assert.NoError(err)
+
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+			proof, err := groth16.Prove(ccs, pk, fullWitness)
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(proof, vk, publicWitness))
+
+			// invalid witness
+			buf.Reset()
+			_, err = cubicWitness(prime, 1, 36, 3, 9, 27).WriteTo(&buf)
+			assert.NoError(err)
+			badWitness, err := ReadWitness(&buf, ccs)
+			assert.NoError(err)
+			assert.Error(ccs.IsSolved(badWitness))
+		})
+	}
+}
+
+func TestReadWitnessErrors(t *testing.T) {
+	assert := require.New(t)
+	prime := ecc.BN254.Info().Fr.Modulus()
+
+	var buf bytes.Buffer
+	_, err := cubicR1CS(prime).WriteTo(&buf)
+	assert.NoError(err)
+	ccs, err := ReadR1CS(&buf)
+	assert.NoError(err)
+
+	// wrong number of values
+	buf.Reset()
+	_, err = cubicWitness(prime, 1, 35, 3, 9).WriteTo(&buf)
+	assert.NoError(err)
+	_, err = ReadWitness(&buf, ccs)
+	assert.Error(err)
+
+	// wrong field
+	buf.Reset()
+	_, err = cubicWitness(ecc.BLS12_381.Info().Fr.Modulus(), 1, 35, 3, 9, 27).WriteTo(&buf)
+	assert.NoError(err)
+	_, err = ReadWitness(&buf, ccs)
+	assert.Error(err)
+
+	// unsupported field
+	buf.Reset()
+	_, err = cubicR1CS(big.NewInt(101)).WriteTo(&buf)
+	assert.NoError(err)
+	_, err = ReadR1CS(&buf)
+	assert.ErrorIs(err, ErrUnsupportedField)
+}
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(circuit.X, circuit.X, circuit.X)
+	api.AssertIsEqual(circuit.Y, api.Add(x3, circuit.X, 5))
+	return nil
+}
+
+func TestExportR1CS(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	err = ccs.ExportR1CS(&buf)
+	assert.NoError(err)
+
+	var cr1cs circom.R1CS
+	_, err = cr1cs.ReadFrom(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+
+	internal, secret, public := ccs.GetNbVariables()
+	assert.Equal(uint32(internal+secret+public), cr1cs.NbWires)
+	assert.Equal(uint32(public-1), cr1cs.NbPubIn)
+	assert.Equal(uint32(secret), cr1cs.NbPrvIn)
+	assert.Equal(uint32(ccs.GetNbConstraints()), cr1cs.NbConstraints)
+
+	// the imported constraint system has the same constraints
+	imported, err := ReadR1CS(&buf)
+	assert.NoError(err)
+	assert.Equal(ccs.GetNbConstraints(), imported.GetNbConstraints())
+
+	// and exporting it again yields the same file
+	var buf2 bytes.Buffer
+	err = imported.ExportR1CS(&buf2)
+	assert.NoError(err)
+	var cr1cs2 circom.R1CS
+	_, err = cr1cs2.ReadFrom(&buf2)
+	assert.NoError(err)
+	assert.Equal(cr1cs.Constraints, cr1cs2.Constraints)
+}
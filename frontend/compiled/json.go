@@ -0,0 +1,177 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiled
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// JSONVersion is the version of the JSON export format. Fields may be added to the format
+// without changing the version, but the meaning of existing fields won't change.
+const JSONVersion = 1
+
+// JSONConstraintSystem is the JSON representation of a R1CS or a SparseR1CS, meant to be
+// consumed by external tools (auditing, formal analysis, ...)
+//
+// Wires are identified by their index in [public | secret | internal]. For a R1CS, wire 0
+// is the constant 1 and is labelled "one". Inputs are labelled with their name in the circuit
+// schema (see schema.Parse); internal wires are labelled as in GetConstraints, "v<i>", or
+// "hv<i>" if they are outputs of a hint.
+//
+// Field elements (modulus, coefficients) are encoded as decimal strings in [0, modulus).
+//
+// R1CS constraints encode L⋅R == O, where L, R and O are linear combinations of wires:
+//
+//	{"l": [{"wire": 0, "coeff": "5"}, ...], "r": [...], "o": [...]}
+//
+// SparseR1CS constraints encode qL⋅xa + qR⋅xb + qO⋅xc + qM⋅(xa⋅xb) + qC == 0, where xa, xb and
+// xc are wire indexes:
+//
+//	{"xa": 1, "xb": 2, "xc": 3, "qL": "0", "qR": "0", "qO": "1", "qM": "1", "qC": "0"}
+//
+// Only one of R1CS or SparseR1CS is set, depending on Backend.
+type JSONConstraintSystem struct {
+	Version    int             `json:"version"`
+	Curve      string          `json:"curve"`
+	Backend    string          `json:"backend"`
+	Modulus    string          `json:"modulus"`
+	NbPublic   int             `json:"nbPublic"`
+	NbSecret   int             `json:"nbSecret"`
+	NbInternal int             `json:"nbInternal"`
+	Wires      []JSONWire      `json:"wires"`
+	R1CS       []JSONR1C       `json:"r1cs,omitempty"`
+	SparseR1CS []JSONSparseR1C `json:"sparseR1cs,omitempty"`
+}
+
+// JSONWire describes a wire of a JSONConstraintSystem
+type JSONWire struct {
+	Label      string `json:"label"`
+	Visibility string `json:"visibility"` // public, secret or internal
+}
+
+// JSONTerm is a coefficient applied to a wire
+type JSONTerm struct {
+	Wire  int    `json:"wire"`
+	Coeff string `json:"coeff"`
+}
+
+// JSONR1C encodes L⋅R == O
+type JSONR1C struct {
+	L []JSONTerm `json:"l"`
+	R []JSONTerm `json:"r"`
+	O []JSONTerm `json:"o"`
+}
+
+// JSONSparseR1C encodes qL⋅xa + qR⋅xb + qO⋅xc + qM⋅(xa⋅xb) + qC == 0
+type JSONSparseR1C struct {
+	XA int    `json:"xa"`
+	XB int    `json:"xb"`
+	XC int    `json:"xc"`
+	QL string `json:"qL"`
+	QR string `json:"qR"`
+	QO string `json:"qO"`
+	QM string `json:"qM"`
+	QC string `json:"qC"`
+}
+
+// ToJSON returns the JSON representation of the R1CS
+//
+// coeffs are the coefficients of the constraint system, reduced modulo the scalar field
+func (r1cs *R1CS) ToJSON(coeffs []big.Int) JSONConstraintSystem {
+	res := r1cs.newJSONConstraintSystem(backend.GROTH16)
+	res.R1CS = make([]JSONR1C, len(r1cs.Constraints))
+
+	toJSONTerms := func(l LinearExpression) []JSONTerm {
+		terms := make([]JSONTerm, len(l))
+		for i := 0; i < len(l); i++ {
+			terms[i].Wire = l[i].WireID()
+			terms[i].Coeff = coeffs[l[i].CoeffID()].String()
+		}
+		return terms
+	}
+	for i, c := range r1cs.Constraints {
+		res.R1CS[i] = JSONR1C{
+			L: toJSONTerms(c.L),
+			R: toJSONTerms(c.R),
+			O: toJSONTerms(c.O),
+		}
+	}
+
+	return res
+}
+
+// ToJSON returns the JSON representation of the SparseR1CS
+//
+// coeffs are the coefficients of the constraint system, reduced modulo the scalar field
+func (cs *SparseR1CS) ToJSON(coeffs []big.Int) JSONConstraintSystem {
+	res := cs.newJSONConstraintSystem(backend.PLONK)
+	res.SparseR1CS = make([]JSONSparseR1C, len(cs.Constraints))
+
+	modulus := cs.CurveID.Info().Fr.Modulus()
+	var qM big.Int
+	for i, c := range cs.Constraints {
+		qM.Mul(&coeffs[c.M[0].CoeffID()], &coeffs[c.M[1].CoeffID()]).Mod(&qM, modulus)
+		res.SparseR1CS[i] = JSONSparseR1C{
+			XA: c.L.WireID(),
+			XB: c.R.WireID(),
+			XC: c.O.WireID(),
+			QL: coeffs[c.L.CoeffID()].String(),
+			QR: coeffs[c.R.CoeffID()].String(),
+			QO: coeffs[c.O.CoeffID()].String(),
+			QM: qM.String(),
+			QC: coeffs[c.K].String(),
+		}
+	}
+
+	return res
+}
+
+// newJSONConstraintSystem returns a JSONConstraintSystem with all fields set but the constraints
+func (cs *ConstraintSystem) newJSONConstraintSystem(backendID backend.ID) JSONConstraintSystem {
+	return JSONConstraintSystem{
+		Version:    JSONVersion,
+		Curve:      cs.CurveID.String(),
+		Backend:    backendID.String(),
+		Modulus:    cs.CurveID.Info().Fr.Modulus().String(),
+		NbPublic:   cs.NbPublicVariables,
+		NbSecret:   cs.NbSecretVariables,
+		NbInternal: cs.NbInternalVariables,
+		Wires:      cs.wires(),
+	}
+}
+
+// wires returns the label and visibility of each wire of the constraint system
+func (cs *ConstraintSystem) wires() []JSONWire {
+	res := make([]JSONWire, 0, cs.NbPublicVariables+cs.NbSecretVariables+cs.NbInternalVariables)
+	for _, name := range cs.Public {
+		res = append(res, JSONWire{Label: name, Visibility: schema.Public.String()})
+	}
+	for _, name := range cs.Secret {
+		res = append(res, JSONWire{Label: name, Visibility: schema.Secret.String()})
+	}
+	offset := cs.NbPublicVariables + cs.NbSecretVariables
+	for i := 0; i < cs.NbInternalVariables; i++ {
+		label := fmt.Sprintf("v%d", i)
+		if _, isHint := cs.MHints[offset+i]; isHint {
+			label = "h" + label
+		}
+		res = append(res, JSONWire{Label: label, Visibility: schema.Internal.String()})
+	}
+	return res
+}
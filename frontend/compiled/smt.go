@@ -0,0 +1,217 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiled
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// ErrUnknownWire is returned by WriteSMT when the output label doesn't match a non-public wire
+var ErrUnknownWire = errors.New("unknown wire")
+
+// WriteSMT writes a SMT-LIB2 query (theory of finite fields, QF_FF) that is satisfiable
+// if and only if two witnesses of the R1CS agree on the public inputs but differ on the
+// wire labelled output. If the query is unsatisfiable, the value of output is uniquely
+// determined by the public inputs.
+//
+// Public wires are declared once, as w<id>. Secret and internal wires are declared once per
+// witness, as w<id>.1 and w<id>.2. Each declaration is followed by a comment with the wire
+// label (see JSONConstraintSystem for labels).
+//
+// coeffs are the coefficients of the constraint system, reduced modulo the scalar field
+func (r1cs *R1CS) WriteSMT(w io.Writer, coeffs []big.Int, output string) error {
+	header := fmt.Sprintf("%s constraint system on %s, %d constraints", backend.GROTH16, r1cs.CurveID, len(r1cs.Constraints))
+	return r1cs.writeSMT(w, header, output, true, func(sw *smtWriter, witness int) {
+		for _, c := range r1cs.Constraints {
+			sw.WriteString("(assert (= (ff.mul ")
+			sw.writeLinearExpression(c.L, coeffs, witness)
+			sw.WriteByte(' ')
+			sw.writeLinearExpression(c.R, coeffs, witness)
+			sw.WriteString(") ")
+			sw.writeLinearExpression(c.O, coeffs, witness)
+			sw.WriteString("))\n")
+		}
+	})
+}
+
+// WriteSMT writes a SMT-LIB2 query (theory of finite fields, QF_FF) that is satisfiable
+// if and only if two witnesses of the SparseR1CS agree on the public inputs but differ on
+// the wire labelled output. See R1CS.WriteSMT.
+//
+// coeffs are the coefficients of the constraint system, reduced modulo the scalar field
+func (cs *SparseR1CS) WriteSMT(w io.Writer, coeffs []big.Int, output string) error {
+	header := fmt.Sprintf("%s constraint system on %s, %d constraints", backend.PLONK, cs.CurveID, len(cs.Constraints))
+	modulus := cs.CurveID.Info().Fr.Modulus()
+	var qM big.Int
+	return cs.writeSMT(w, header, output, false, func(sw *smtWriter, witness int) {
+		for _, c := range cs.Constraints {
+			// qL⋅xa + qR⋅xb + qO⋅xc + qM⋅(xa⋅xb) + qC == 0
+			var terms []string
+			if c.L.CoeffID() != CoeffIdZero {
+				terms = append(terms, sw.term(&coeffs[c.L.CoeffID()], c.L.WireID(), witness))
+			}
+			if c.R.CoeffID() != CoeffIdZero {
+				terms = append(terms, sw.term(&coeffs[c.R.CoeffID()], c.R.WireID(), witness))
+			}
+			if c.O.CoeffID() != CoeffIdZero {
+				terms = append(terms, sw.term(&coeffs[c.O.CoeffID()], c.O.WireID(), witness))
+			}
+			qM.Mul(&coeffs[c.M[0].CoeffID()], &coeffs[c.M[1].CoeffID()]).Mod(&qM, modulus)
+			if qM.Sign() != 0 {
+				xaxb := sw.wire(c.L.WireID(), witness) + " " + sw.wire(c.R.WireID(), witness)
+				if !isOne(&qM) {
+					xaxb = sw.constant(&qM) + " " + xaxb
+				}
+				terms = append(terms, "(ff.mul "+xaxb+")")
+			}
+			if coeffs[c.K].Sign() != 0 {
+				terms = append(terms, sw.constant(&coeffs[c.K]))
+			}
+
+			sw.WriteString("(assert (= ")
+			sw.writeSum(terms)
+			sw.WriteString(" " + sw.constant(new(big.Int)) + "))\n")
+		}
+	})
+}
+
+// writeSMT writes the declarations, calls writeConstraints once per witness, and writes
+// the uniqueness query. oneWire is set if wire 0 is the constant 1 (R1CS).
+func (cs *ConstraintSystem) writeSMT(w io.Writer, header, output string, oneWire bool, writeConstraints func(sw *smtWriter, witness int)) error {
+	wires := cs.wires()
+
+	target := -1
+	for i, wire := range wires {
+		if wire.Label == output && wire.Visibility != schema.Public.String() {
+			target = i
+			break
+		}
+	}
+	if target == -1 {
+		return fmt.Errorf("%w: %q is not a secret or internal wire", ErrUnknownWire, output)
+	}
+
+	sw := smtWriter{
+		Writer:     bufio.NewWriter(w),
+		nbPublic:   cs.NbPublicVariables,
+		oneWire:    oneWire,
+		constCache: make(map[string]string),
+	}
+
+	sw.WriteString("; " + header + "\n")
+	sw.WriteString("; sat iff two witnesses agree on the public inputs but differ on " + output + "\n")
+	sw.WriteString("(set-logic QF_FF)\n")
+	sw.WriteString("(define-sort F () (_ FiniteField " + cs.CurveID.Info().Fr.Modulus().String() + "))\n")
+
+	sw.WriteString("; public wires\n")
+	for i := 0; i < cs.NbPublicVariables; i++ {
+		if i == 0 && sw.oneWire {
+			continue
+		}
+		sw.WriteString("(declare-const " + sw.wire(i, 1) + " F) ; " + wires[i].Label + "\n")
+	}
+	for witness := 1; witness <= 2; witness++ {
+		sw.WriteString("; secret and internal wires, witness " + strconv.Itoa(witness) + "\n")
+		for i := cs.NbPublicVariables; i < len(wires); i++ {
+			sw.WriteString("(declare-const " + sw.wire(i, witness) + " F) ; " + wires[i].Label + "\n")
+		}
+	}
+	for witness := 1; witness <= 2; witness++ {
+		sw.WriteString("; constraints, witness " + strconv.Itoa(witness) + "\n")
+		writeConstraints(&sw, witness)
+	}
+
+	sw.WriteString("; witnesses differ on " + output + "\n")
+	sw.WriteString("(assert (not (= " + sw.wire(target, 1) + " " + sw.wire(target, 2) + ")))\n")
+	sw.WriteString("(check-sat)\n")
+
+	return sw.Flush()
+}
+
+type smtWriter struct {
+	*bufio.Writer
+	nbPublic   int
+	oneWire    bool              // wire 0 is the constant 1
+	constCache map[string]string // decimal value -> SMT-LIB literal
+}
+
+// wire returns the symbol of wire id in the given witness (1 or 2)
+func (sw *smtWriter) wire(id, witness int) string {
+	if id == 0 && sw.oneWire {
+		return "(as ff1 F)"
+	}
+	if id < sw.nbPublic {
+		return "w" + strconv.Itoa(id)
+	}
+	return "w" + strconv.Itoa(id) + "." + strconv.Itoa(witness)
+}
+
+// constant returns the SMT-LIB literal of v
+func (sw *smtWriter) constant(v *big.Int) string {
+	s := v.String()
+	if c, ok := sw.constCache[s]; ok {
+		return c
+	}
+	c := "(as ff" + s + " F)"
+	sw.constCache[s] = c
+	return c
+}
+
+// term returns coeff⋅wire
+func (sw *smtWriter) term(coeff *big.Int, id, witness int) string {
+	if isOne(coeff) {
+		return sw.wire(id, witness)
+	}
+	if id == 0 && sw.oneWire {
+		return sw.constant(coeff)
+	}
+	return "(ff.mul " + sw.constant(coeff) + " " + sw.wire(id, witness) + ")"
+}
+
+// writeSum writes the sum of terms, or 0 if there are none
+func (sw *smtWriter) writeSum(terms []string) {
+	switch len(terms) {
+	case 0:
+		sw.WriteString(sw.constant(new(big.Int)))
+	case 1:
+		sw.WriteString(terms[0])
+	default:
+		sw.WriteString("(ff.add " + strings.Join(terms, " ") + ")")
+	}
+}
+
+func isOne(v *big.Int) bool {
+	return v.IsInt64() && v.Int64() == 1
+}
+
+func (sw *smtWriter) writeLinearExpression(l LinearExpression, coeffs []big.Int, witness int) {
+	terms := make([]string, 0, len(l))
+	for _, t := range l {
+		if t.CoeffID() == CoeffIdZero {
+			continue
+		}
+		terms = append(terms, sw.term(&coeffs[t.CoeffID()], t.WireID(), witness))
+	}
+	sw.writeSum(terms)
+}
@@ -0,0 +1,60 @@
+package compiled_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the SMT-LIB exports")
+
+// smtCircuit has a public input X, a secret input Y and a secret output Out,
+// which depends on the sign of Y and so isn't determined by X
+type smtCircuit struct {
+	X   frontend.Variable `gnark:",public"`
+	Y   frontend.Variable
+	Out frontend.Variable
+}
+
+func (c *smtCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.Y, c.Y), c.X)
+	api.AssertIsEqual(api.Add(api.Mul(c.X, c.Y), 3), c.Out)
+	return nil
+}
+
+func TestWriteSMT(t *testing.T) {
+	for name, newBuilder := range map[string]frontend.NewBuilder{
+		"r1cs.smt2":        r1cs.NewBuilder,
+		"sparse_r1cs.smt2": scs.NewBuilder,
+	} {
+		ccs, err := frontend.Compile(ecc.BN254, newBuilder, &smtCircuit{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := ccs.ExportSMT(&buf, "Out"); err != nil {
+			t.Fatal(err)
+		}
+
+		golden := filepath.Join("testdata", name)
+		if *update {
+			if err := os.WriteFile(golden, buf.Bytes(), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Errorf("%s doesn't match the export, run the test with -update if the change is expected", golden)
+		}
+	}
+}
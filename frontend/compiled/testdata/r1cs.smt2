@@ -0,0 +1,29 @@
+; groth16 constraint system on BN254, 4 constraints
+; sat iff two witnesses agree on the public inputs but differ on Out
+(set-logic QF_FF)
+(define-sort F () (_ FiniteField 21888242871839275222246405745257275088548364400416034343698204186575808495617))
+; public wires
+(declare-const w1 F) ; X
+; secret and internal wires, witness 1
+(declare-const w2.1 F) ; Y
+(declare-const w3.1 F) ; Out
+(declare-const w4.1 F) ; v0
+(declare-const w5.1 F) ; v1
+; secret and internal wires, witness 2
+(declare-const w2.2 F) ; Y
+(declare-const w3.2 F) ; Out
+(declare-const w4.2 F) ; v0
+(declare-const w5.2 F) ; v1
+; constraints, witness 1
+(assert (= (ff.mul w2.1 w2.1) w4.1))
+(assert (= (ff.mul (as ff1 F) w4.1) w1))
+(assert (= (ff.mul w1 w2.1) w5.1))
+(assert (= (ff.mul (as ff1 F) (ff.add (as ff3 F) w5.1)) w3.1))
+; constraints, witness 2
+(assert (= (ff.mul w2.2 w2.2) w4.2))
+(assert (= (ff.mul (as ff1 F) w4.2) w1))
+(assert (= (ff.mul w1 w2.2) w5.2))
+(assert (= (ff.mul (as ff1 F) (ff.add (as ff3 F) w5.2)) w3.2))
+; witnesses differ on Out
+(assert (not (= w3.1 w3.2)))
+(check-sat)
@@ -0,0 +1,33 @@
+; plonk constraint system on BN254, 5 constraints
+; sat iff two witnesses agree on the public inputs but differ on Out
+(set-logic QF_FF)
+(define-sort F () (_ FiniteField 21888242871839275222246405745257275088548364400416034343698204186575808495617))
+; public wires
+(declare-const w0 F) ; X
+; secret and internal wires, witness 1
+(declare-const w1.1 F) ; Y
+(declare-const w2.1 F) ; Out
+(declare-const w3.1 F) ; v0
+(declare-const w4.1 F) ; v1
+(declare-const w5.1 F) ; v2
+; secret and internal wires, witness 2
+(declare-const w1.2 F) ; Y
+(declare-const w2.2 F) ; Out
+(declare-const w3.2 F) ; v0
+(declare-const w4.2 F) ; v1
+(declare-const w5.2 F) ; v2
+; constraints, witness 1
+(assert (= (ff.add (ff.mul (as ff21888242871839275222246405745257275088548364400416034343698204186575808495616 F) w3.1) (ff.mul w1.1 w1.1)) (as ff0 F)))
+(assert (= (ff.add w3.1 (ff.mul (as ff21888242871839275222246405745257275088548364400416034343698204186575808495616 F) w0)) (as ff0 F)))
+(assert (= (ff.add (ff.mul (as ff21888242871839275222246405745257275088548364400416034343698204186575808495616 F) w4.1) (ff.mul w0 w1.1)) (as ff0 F)))
+(assert (= (ff.add w4.1 (ff.mul (as ff21888242871839275222246405745257275088548364400416034343698204186575808495616 F) w5.1) (as ff3 F)) (as ff0 F)))
+(assert (= (ff.add w5.1 (ff.mul (as ff21888242871839275222246405745257275088548364400416034343698204186575808495616 F) w2.1)) (as ff0 F)))
+; constraints, witness 2
+(assert (= (ff.add (ff.mul (as ff21888242871839275222246405745257275088548364400416034343698204186575808495616 F) w3.2) (ff.mul w1.2 w1.2)) (as ff0 F)))
+(assert (= (ff.add w3.2 (ff.mul (as ff21888242871839275222246405745257275088548364400416034343698204186575808495616 F) w0)) (as ff0 F)))
+(assert (= (ff.add (ff.mul (as ff21888242871839275222246405745257275088548364400416034343698204186575808495616 F) w4.2) (ff.mul w0 w1.2)) (as ff0 F)))
+(assert (= (ff.add w4.2 (ff.mul (as ff21888242871839275222246405745257275088548364400416034343698204186575808495616 F) w5.2) (as ff3 F)) (as ff0 F)))
+(assert (= (ff.add w5.2 (ff.mul (as ff21888242871839275222246405745257275088548364400416034343698204186575808495616 F) w2.2)) (as ff0 F)))
+; witnesses differ on Out
+(assert (not (= w2.1 w2.2)))
+(check-sat)
@@ -288,7 +288,12 @@ func ccomputePermutationPolynomials(pk *ProvingKey) {
 	fft.BitReverse(pk.S2Canonical)
 	fft.BitReverse(pk.S3Canonical)
 
-	// evaluation of permutation on the big domain
+	computePermutationBigDomain(pk)
+}
+
+// computePermutationBigDomain computes the evaluation of s1, s2, s3 on the big domain,
+// from their canonical form. It is not serialized, and recomputed when a ProvingKey is read.
+func computePermutationBigDomain(pk *ProvingKey) {
 	pk.EvaluationPermutationBigDomainBitReversed = make([]fr.Element, 3*pk.Domain[1].Cardinality)
 	copy(pk.EvaluationPermutationBigDomainBitReversed, pk.S1Canonical)
 	copy(pk.EvaluationPermutationBigDomainBitReversed[pk.Domain[1].Cardinality:], pk.S2Canonical)
@@ -296,7 +301,6 @@ func ccomputePermutationPolynomials(pk *ProvingKey) {
 	pk.Domain[1].FFT(pk.EvaluationPermutationBigDomainBitReversed[:pk.Domain[1].Cardinality], fft.DIF, true)
 	pk.Domain[1].FFT(pk.EvaluationPermutationBigDomainBitReversed[pk.Domain[1].Cardinality:2*pk.Domain[1].Cardinality], fft.DIF, true)
 	pk.Domain[1].FFT(pk.EvaluationPermutationBigDomainBitReversed[2*pk.Domain[1].Cardinality:], fft.DIF, true)
-
 }
 
 // getIDSmallDomain returns the Lagrange form of ID on the small domain
@@ -17,6 +17,7 @@
 package cs
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
@@ -534,3 +535,19 @@ func (cs *SparseR1CS) ReadFrom(r io.Reader) (int64, error) {
 	err = decoder.Decode(cs)
 	return int64(decoder.NumBytesRead()), err
 }
+
+// ExportR1CS returns an error: the circom .r1cs format can't encode a SparseR1CS
+func (cs *SparseR1CS) ExportR1CS(w io.Writer) error {
+	return errors.New("circom .r1cs format is not supported for SparseR1CS")
+}
+
+// ExportJSON writes the SparseR1CS as a JSON document (see compiled.JSONConstraintSystem)
+func (cs *SparseR1CS) ExportJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(cs.SparseR1CS.ToJSON(toBigInts(cs.Coefficients)))
+}
+
+// ExportSMT writes a SMT-LIB2 query checking that the wire labelled output is uniquely
+// determined by the public inputs (see compiled.SparseR1CS.WriteSMT)
+func (cs *SparseR1CS) ExportSMT(w io.Writer, output string) error {
+	return cs.SparseR1CS.WriteSMT(w, toBigInts(cs.Coefficients), output)
+}
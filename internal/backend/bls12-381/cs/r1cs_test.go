@@ -18,11 +18,16 @@ package cs_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/compiled"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
 	"github.com/consensys/gnark/internal/backend/circuits"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/consensys/gnark/internal/backend/bls12-381/cs"
@@ -116,6 +121,90 @@ func TestSerialization(t *testing.T) {
 	}
 }
 
+func TestExportJSON(t *testing.T) {
+	tc := circuits.Circuits["reference_small"]
+
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BLS12_381, newBuilder, tc.Circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buffer bytes.Buffer
+		if err := ccs.ExportJSON(&buffer); err != nil {
+			t.Fatal(err)
+		}
+		var exported compiled.JSONConstraintSystem
+		if err := json.Unmarshal(buffer.Bytes(), &exported); err != nil {
+			t.Fatal(err)
+		}
+
+		internal, secret, public := ccs.GetNbVariables()
+		if exported.NbInternal != internal || exported.NbSecret != secret || exported.NbPublic != public {
+			t.Fatal("exported number of variables doesn't match")
+		}
+		if len(exported.Wires) != internal+secret+public {
+			t.Fatal("exported number of wires doesn't match")
+		}
+		if exported.Curve != ecc.BLS12_381.String() {
+			t.Fatal("exported curve doesn't match")
+		}
+		nbConstraints := len(exported.R1CS) + len(exported.SparseR1CS)
+		if nbConstraints != ccs.GetNbConstraints() {
+			t.Fatal("exported number of constraints doesn't match")
+		}
+
+		// wire labels are the names of the inputs in the circuit schema
+		var buf bytes.Buffer
+		if err := ccs.GetSchema().WriteSequence(&buf); err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range exported.Wires {
+			if w.Visibility == "internal" || w.Label == "one" {
+				continue
+			}
+			if !bytes.Contains(buf.Bytes(), []byte(w.Label+"\n")) {
+				t.Fatalf("wire label %s is not in the schema", w.Label)
+			}
+		}
+	}
+}
+
+func TestExportSMT(t *testing.T) {
+	tc := circuits.Circuits["reference_small"]
+
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BLS12_381, newBuilder, tc.Circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buffer bytes.Buffer
+		if err := ccs.ExportSMT(&buffer, "X"); err != nil {
+			t.Fatal(err)
+		}
+		query := buffer.String()
+
+		// public wires are shared by both witnesses, secret wires are not
+		for _, decl := range []string{"F) ; Y\n", ".1 F) ; X\n", ".2 F) ; X\n"} {
+			if strings.Count(query, decl) != 1 {
+				t.Fatalf("expected exactly one declaration %q", decl)
+			}
+		}
+		if strings.Count(query, "(assert ") != 2*ccs.GetNbConstraints()+1 {
+			t.Fatal("expected one assertion per constraint and witness, and the uniqueness query")
+		}
+		if !strings.HasSuffix(query, "(check-sat)\n") {
+			t.Fatal("missing check-sat")
+		}
+
+		// public wires can't be checked for uniqueness
+		if err := ccs.ExportSMT(&buffer, "Y"); !errors.Is(err, compiled.ErrUnknownWire) {
+			t.Fatal("expected ErrUnknownWire, got", err)
+		}
+	}
+}
+
 const n = 10000
 
 type circuit struct {
@@ -19,8 +19,11 @@ package plonk
 import (
 	curve "github.com/consensys/gnark-crypto/ecc/bn254"
 
-	"errors"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"errors"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
 	"io"
 )
 
@@ -174,19 +177,33 @@ func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
 		}
 	}
 
+	computePermutationBigDomain(pk)
+
 	return n + dec.BytesRead(), nil
 
 }
 
+// The binary encoding of a VerifyingKey starts with vkEncodingTag, followed by
+// the version of the format. The keys encoded before the format was versioned
+// start with their Size, a power of two which can't be equal to vkEncodingTag,
+// and have no CosetShift.
+const (
+	vkEncodingTag     = ^uint64(0)
+	vkEncodingVersion = uint64(1)
+)
+
 // WriteTo writes binary encoding of VerifyingKey to w
 func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
 	enc := curve.NewEncoder(w)
 
 	toEncode := []interface{}{
+		vkEncodingTag,
+		vkEncodingVersion,
 		vk.Size,
 		&vk.SizeInv,
 		&vk.Generator,
 		vk.NbPublicVariables,
+		&vk.CosetShift,
 		&vk.S[0],
 		&vk.S[1],
 		&vk.S[2],
@@ -207,13 +224,42 @@ func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
 }
 
 // ReadFrom reads from binary representation in r into VerifyingKey
+//
+// The keys encoded before the format was versioned are read with the default
+// CosetShift, the generator of fr* used by Setup.
 func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 	dec := curve.NewDecoder(r)
+
+	var tag uint64
+	if err := dec.Decode(&tag); err != nil {
+		return dec.BytesRead(), err
+	}
 	toDecode := []interface{}{
 		&vk.Size,
 		&vk.SizeInv,
 		&vk.Generator,
 		&vk.NbPublicVariables,
+		&vk.CosetShift,
+	}
+	if tag == vkEncodingTag {
+		var version uint64
+		if err := dec.Decode(&version); err != nil {
+			return dec.BytesRead(), err
+		}
+		if version != vkEncodingVersion {
+			return dec.BytesRead(), fmt.Errorf("unsupported verifying key encoding version %d", version)
+		}
+	} else {
+		// the tag is the size of a key encoded without the version
+		vk.Size = tag
+		vk.CosetShift.Set(&fft.NewDomain(1).FrMultiplicativeGen)
+		toDecode = []interface{}{
+			&vk.SizeInv,
+			&vk.Generator,
+			&vk.NbPublicVariables,
+		}
+	}
+	toDecode = append(toDecode,
 		&vk.S[0],
 		&vk.S[1],
 		&vk.S[2],
@@ -222,7 +268,7 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 		&vk.Qm,
 		&vk.Qo,
 		&vk.Qk,
-	}
+	)
 
 	for _, v := range toDecode {
 		if err := dec.Decode(v); err != nil {
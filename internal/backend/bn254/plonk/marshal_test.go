@@ -32,6 +32,7 @@ func TestProvingKeySerialization(t *testing.T) {
 	var vk VerifyingKey
 	vk.Size = 42
 	vk.SizeInv = fr.One()
+	vk.CosetShift.SetUint64(5)
 
 	_, _, g1gen, _ := curve.Generators()
 	vk.S[0] = g1gen
@@ -65,6 +66,11 @@ func TestProvingKeySerialization(t *testing.T) {
 	pk.Permutation = make([]int64, 3*pk.Domain[0].Cardinality)
 	pk.Permutation[0] = -12
 	pk.Permutation[len(pk.Permutation)-1] = 8888
+	pk.S1Canonical = make([]fr.Element, pk.Domain[0].Cardinality)
+	pk.S2Canonical = make([]fr.Element, pk.Domain[0].Cardinality)
+	pk.S3Canonical = make([]fr.Element, pk.Domain[0].Cardinality)
+	pk.S1Canonical[0].SetUint64(12)
+	computePermutationBigDomain(&pk)
 
 	var buf bytes.Buffer
 	written, err := pk.WriteTo(&buf)
@@ -93,6 +99,7 @@ func TestVerifyingKeySerialization(t *testing.T) {
 	var vk VerifyingKey
 	vk.Size = 42
 	vk.SizeInv = fr.One()
+	vk.CosetShift.SetUint64(5)
 
 	_, _, g1gen, _ := curve.Generators()
 	vk.S[0] = g1gen
@@ -125,3 +132,58 @@ func TestVerifyingKeySerialization(t *testing.T) {
 		t.Fatal("bytes written / read don't match")
 	}
 }
+
+func TestVerifyingKeyUnversionedSerialization(t *testing.T) {
+	// a vk encoded before the format was versioned, without CosetShift
+	var vk VerifyingKey
+	vk.Size = 64
+	vk.SizeInv = fr.One()
+	vk.NbPublicVariables = 3
+	vk.CosetShift.Set(&fft.NewDomain(1).FrMultiplicativeGen)
+
+	_, _, g1gen, _ := curve.Generators()
+	vk.S[0] = g1gen
+	vk.S[1] = g1gen
+	vk.S[2] = g1gen
+	vk.Ql = g1gen
+	vk.Qr = g1gen
+	vk.Qm = g1gen
+	vk.Qo = g1gen
+	vk.Qk = g1gen
+
+	var buf bytes.Buffer
+	enc := curve.NewEncoder(&buf)
+	for _, v := range []interface{}{
+		vk.Size,
+		&vk.SizeInv,
+		&vk.Generator,
+		vk.NbPublicVariables,
+		&vk.S[0],
+		&vk.S[1],
+		&vk.S[2],
+		&vk.Ql,
+		&vk.Qr,
+		&vk.Qm,
+		&vk.Qo,
+		&vk.Qk,
+	} {
+		if err := enc.Encode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	written := enc.BytesWritten()
+
+	var reconstructed VerifyingKey
+	read, err := reconstructed.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal("coudln't deserialize", err)
+	}
+
+	if !reflect.DeepEqual(&vk, &reconstructed) {
+		t.Fatal("reconstructed object don't match original")
+	}
+
+	if written != read {
+		t.Fatal("bytes written / read don't match")
+	}
+}
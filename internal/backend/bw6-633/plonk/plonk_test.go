@@ -39,6 +39,74 @@ import (
 	"github.com/consensys/gnark/frontend/cs/scs"
 )
 
+// TestSerializationRoundTrip checks that the keys read from their binary
+// serialization can be used to prove and verify
+func TestSerializationRoundTrip(t *testing.T) {
+	const nbConstraints = 10
+	ccs, err := frontend.Compile(curve.ID, scs.NewBuilder, &refCircuit{nbConstraints: nbConstraints})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srs, err := kzg.NewSRS(ecc.NextPowerOfTwo(nbConstraints)+3, new(big.Int).SetUint64(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := bw6_633plonk.Setup(ccs.(*cs.SparseR1CS), srs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := pk.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var pkRead bw6_633plonk.ProvingKey
+	if _, err := pkRead.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := pkRead.InitKZG(srs); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if _, err := vk.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var vkRead bw6_633plonk.VerifyingKey
+	if _, err := vkRead.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := vkRead.InitKZG(srs); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(vk, &vkRead) {
+		t.Fatal("verifying key: serialization round trip failed")
+	}
+
+	var assignment refCircuit
+	var y fr.Element
+	y.SetUint64(2)
+	for i := 0; i < nbConstraints; i++ {
+		y.Mul(&y, &y)
+	}
+	assignment.X, assignment.Y = 2, y
+	fullWitness := bw6_633witness.Witness{}
+	if _, err := fullWitness.FromAssignment(&assignment, tVariable, false); err != nil {
+		t.Fatal(err)
+	}
+	publicWitness := bw6_633witness.Witness{}
+	if _, err := publicWitness.FromAssignment(&assignment, tVariable, true); err != nil {
+		t.Fatal(err)
+	}
+
+	proof, err := bw6_633plonk.Prove(ccs.(*cs.SparseR1CS), &pkRead, fullWitness, backend.ProverConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bw6_633plonk.Verify(proof, &vkRead, publicWitness); err != nil {
+		t.Fatal(err)
+	}
+}
+
 //--------------------//
 //     benches		  //
 //--------------------//
@@ -17,6 +17,7 @@
 package cs
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/fxamacker/cbor/v2"
@@ -32,6 +33,7 @@ import (
 	"github.com/consensys/gnark/frontend/compiled"
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/backend/ioutils"
+	"github.com/consensys/gnark/internal/circom"
 	"github.com/consensys/gnark/logger"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -493,3 +495,32 @@ func (cs *R1CS) ReadFrom(r io.Reader) (int64, error) {
 
 	return int64(decoder.NumBytesRead()), nil
 }
+
+// ExportR1CS writes the R1CS in the circom binary .r1cs format
+//
+// public variables are exported as circom public inputs, secret variables as private inputs
+// and internal variables as internal wires
+func (cs *R1CS) ExportR1CS(w io.Writer) error {
+	_, err := circom.NewR1CS(&cs.R1CS, toBigInts(cs.Coefficients)).WriteTo(w)
+	return err
+}
+
+// ExportJSON writes the R1CS as a JSON document (see compiled.JSONConstraintSystem)
+func (cs *R1CS) ExportJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(cs.R1CS.ToJSON(toBigInts(cs.Coefficients)))
+}
+
+// ExportSMT writes a SMT-LIB2 query checking that the wire labelled output is uniquely
+// determined by the public inputs (see compiled.R1CS.WriteSMT)
+func (cs *R1CS) ExportSMT(w io.Writer, output string) error {
+	return cs.R1CS.WriteSMT(w, toBigInts(cs.Coefficients), output)
+}
+
+// toBigInts returns the regular (non-Montgomery) big.Int values of coeffs
+func toBigInts(coeffs []fr.Element) []big.Int {
+	res := make([]big.Int, len(coeffs))
+	for i := 0; i < len(coeffs); i++ {
+		coeffs[i].ToBigIntRegular(&res[i])
+	}
+	return res
+}
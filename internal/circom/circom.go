@@ -0,0 +1,242 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package circom implements the iden3 binary file formats produced by circom and snarkjs.
+//
+// Both formats (.r1cs and .wtns) share the same layout
+//
+//	[magic (4 bytes) | version (uint32) | nbSections (uint32) | sections...]
+//	section -> [sectionType (uint32) | sectionSize (uint64) | data]
+//
+// All integers are encoded in little-endian. Field elements are encoded in little-endian
+// on n8 bytes, in regular (non-Montgomery) form, where n8 is the smallest multiple of 8
+// bytes that fits the prime.
+//
+// See https://github.com/iden3/r1csfile/blob/master/doc/r1cs_bin_format.md
+package circom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+)
+
+var (
+	ErrInvalidMagic   = errors.New("invalid magic number")
+	ErrInvalidVersion = errors.New("unsupported version")
+	ErrMissingSection = errors.New("missing section")
+)
+
+// maxPrealloc bounds the number of items allocated ahead of their decoding: the
+// counts and sizes of a file are not trusted, and larger slices grow as their
+// items are read.
+const maxPrealloc = 1 << 16
+
+// prealloc returns the capacity to allocate for n items declared by a file
+func prealloc(n uint64) int {
+	if n > maxPrealloc {
+		return maxPrealloc
+	}
+	return int(n)
+}
+
+// fieldSize returns the number of bytes (multiple of 8) used to encode an element of F_prime
+func fieldSize(prime *big.Int) int {
+	return ((prime.BitLen() + 63) / 64) * 8
+}
+
+// writer wraps an io.Writer, counts the bytes written and keeps the first error encountered
+type writer struct {
+	w   io.Writer
+	n   int64
+	err error
+	buf [8]byte
+}
+
+func (w *writer) write(b []byte) {
+	if w.err != nil {
+		return
+	}
+	n, err := w.w.Write(b)
+	w.n += int64(n)
+	w.err = err
+}
+
+func (w *writer) uint32(v uint32) {
+	binary.LittleEndian.PutUint32(w.buf[:4], v)
+	w.write(w.buf[:4])
+}
+
+func (w *writer) uint64(v uint64) {
+	binary.LittleEndian.PutUint64(w.buf[:8], v)
+	w.write(w.buf[:8])
+}
+
+// element writes v (expected to be in [0, prime)) in little-endian on n8 bytes
+func (w *writer) element(v *big.Int, n8 int) {
+	if w.err != nil {
+		return
+	}
+	if v.Sign() < 0 || (v.BitLen()+7)/8 > n8 {
+		w.err = fmt.Errorf("field element %s doesn't fit on %d bytes", v.String(), n8)
+		return
+	}
+	b := make([]byte, n8)
+	v.FillBytes(b)
+	reverse(b)
+	w.write(b)
+}
+
+// header writes the file magic, version and number of sections
+func (w *writer) header(magic string, version, nbSections uint32) {
+	w.write([]byte(magic))
+	w.uint32(version)
+	w.uint32(nbSections)
+}
+
+// reader wraps an io.Reader, counts the bytes read and keeps the first error encountered
+type reader struct {
+	r   io.Reader
+	n   int64
+	err error
+	buf [8]byte
+}
+
+func (r *reader) read(b []byte) {
+	if r.err != nil {
+		return
+	}
+	n, err := io.ReadFull(r.r, b)
+	r.n += int64(n)
+	r.err = err
+}
+
+func (r *reader) uint32() uint32 {
+	r.read(r.buf[:4])
+	if r.err != nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(r.buf[:4])
+}
+
+func (r *reader) uint64() uint64 {
+	r.read(r.buf[:8])
+	if r.err != nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(r.buf[:8])
+}
+
+// bytes reads the next n bytes. The buffer grows as the data is read, so that a
+// size declared by a file doesn't allocate more than the file contains.
+func (r *reader) bytes(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > math.MaxInt64 {
+		r.err = fmt.Errorf("invalid size: %d bytes", n)
+		return nil
+	}
+	var b bytes.Buffer
+	read, err := io.CopyN(&b, r.r, int64(n))
+	r.n += read
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	r.err = err
+	return b.Bytes()
+}
+
+// element reads a little-endian field element on len(b) bytes, using b as buffer
+func (r *reader) element(v *big.Int, b []byte) {
+	r.read(b)
+	if r.err != nil {
+		return
+	}
+	reverse(b)
+	v.SetBytes(b)
+}
+
+// prime reads the field size n8 and the prime encoded on n8 bytes
+func (r *reader) prime() (prime *big.Int, n8 uint32) {
+	n8 = r.uint32()
+	if r.err != nil {
+		return
+	}
+	if n8 == 0 || n8%8 != 0 {
+		r.err = fmt.Errorf("invalid field size: %d bytes", n8)
+		return
+	}
+	b := r.bytes(uint64(n8))
+	if r.err != nil {
+		return
+	}
+	reverse(b)
+	return new(big.Int).SetBytes(b), n8
+}
+
+// header reads the file magic, version and number of sections, and checks them against
+// the expected values
+func (r *reader) header(magic string, version uint32) (nbSections uint32) {
+	var m [4]byte
+	r.read(m[:])
+	if r.err != nil {
+		return
+	}
+	if string(m[:]) != magic {
+		r.err = fmt.Errorf("%w: got %q, expected %q", ErrInvalidMagic, string(m[:]), magic)
+		return
+	}
+	if v := r.uint32(); r.err == nil && v != version {
+		r.err = fmt.Errorf("%w: got %d, expected %d", ErrInvalidVersion, v, version)
+		return
+	}
+	return r.uint32()
+}
+
+// section reads a section header and returns its type and size
+func (r *reader) section() (sectionType uint32, size uint64) {
+	sectionType = r.uint32()
+	size = r.uint64()
+	return
+}
+
+// skip discards the next n bytes
+func (r *reader) skip(n uint64) {
+	if r.err != nil {
+		return
+	}
+	if n > math.MaxInt64 {
+		r.err = fmt.Errorf("invalid size: %d bytes", n)
+		return
+	}
+	read, err := io.CopyN(io.Discard, r.r, int64(n))
+	r.n += read
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	r.err = err
+}
+
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
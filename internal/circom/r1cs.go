@@ -0,0 +1,303 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package circom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend/compiled"
+)
+
+const (
+	r1csMagic   = "r1cs"
+	r1csVersion = 1
+
+	r1csSectionHeader      = 1
+	r1csSectionConstraints = 2
+	r1csSectionWireToLabel = 3
+)
+
+// Header of a .r1cs file
+//
+// Wires are ordered as [one | public outputs | public inputs | private inputs | internal]
+type Header struct {
+	Prime         *big.Int
+	NbWires       uint32
+	NbPubOut      uint32
+	NbPubIn       uint32
+	NbPrvIn       uint32
+	NbLabels      uint64
+	NbConstraints uint32
+}
+
+// Term is a coefficient applied to a wire
+type Term struct {
+	WireID uint32
+	Coeff  big.Int
+}
+
+// LinearCombination is a sum of terms
+type LinearCombination []Term
+
+// Constraint encodes A ⋅ B - C = 0
+type Constraint struct {
+	A, B, C LinearCombination
+}
+
+// R1CS is the content of a circom .r1cs file
+type R1CS struct {
+	Header
+	Constraints []Constraint
+
+	// WireToLabel maps a wire id to its label id (labels are described in the .sym file)
+	WireToLabel []uint64
+}
+
+// NewR1CS returns the circom representation of a gnark R1CS
+//
+// gnark public variables are mapped to circom public inputs, secret variables to private
+// inputs and internal variables to internal wires. Wire labels are the wire ids.
+// coeffs are the coefficients of r1cs, reduced modulo the scalar field.
+func NewR1CS(r1cs *compiled.R1CS, coeffs []big.Int) *R1CS {
+	nbWires := r1cs.NbPublicVariables + r1cs.NbSecretVariables + r1cs.NbInternalVariables
+
+	res := R1CS{
+		Header: Header{
+			Prime:         r1cs.CurveID.Info().Fr.Modulus(),
+			NbWires:       uint32(nbWires),
+			NbPubIn:       uint32(r1cs.NbPublicVariables - 1),
+			NbPrvIn:       uint32(r1cs.NbSecretVariables),
+			NbLabels:      uint64(nbWires),
+			NbConstraints: uint32(len(r1cs.Constraints)),
+		},
+		Constraints: make([]Constraint, len(r1cs.Constraints)),
+		WireToLabel: make([]uint64, nbWires),
+	}
+	for i := range res.WireToLabel {
+		res.WireToLabel[i] = uint64(i)
+	}
+
+	toLinearCombination := func(l compiled.LinearExpression) LinearCombination {
+		lc := make(LinearCombination, len(l))
+		for i := 0; i < len(l); i++ {
+			lc[i].WireID = uint32(l[i].WireID())
+			lc[i].Coeff.Set(&coeffs[l[i].CoeffID()])
+		}
+		return lc
+	}
+	for i, c := range r1cs.Constraints {
+		res.Constraints[i] = Constraint{
+			A: toLinearCombination(c.L),
+			B: toLinearCombination(c.R),
+			C: toLinearCombination(c.O),
+		}
+	}
+
+	return &res
+}
+
+// WriteTo encodes the R1CS in the iden3 binary format
+func (r1cs *R1CS) WriteTo(w io.Writer) (int64, error) {
+	if r1cs.Prime == nil {
+		return 0, errors.New("missing prime")
+	}
+	if len(r1cs.Constraints) != int(r1cs.NbConstraints) {
+		return 0, fmt.Errorf("got %d constraints, header expects %d", len(r1cs.Constraints), r1cs.NbConstraints)
+	}
+	if len(r1cs.WireToLabel) != int(r1cs.NbWires) {
+		return 0, fmt.Errorf("got %d wire labels, header expects %d", len(r1cs.WireToLabel), r1cs.NbWires)
+	}
+	n8 := fieldSize(r1cs.Prime)
+	_w := writer{w: w}
+
+	_w.header(r1csMagic, r1csVersion, 3)
+
+	// header section
+	_w.uint32(r1csSectionHeader)
+	_w.uint64(uint64(4 + n8 + 4*4 + 8 + 4))
+	_w.uint32(uint32(n8))
+	_w.element(r1cs.Prime, n8)
+	_w.uint32(r1cs.NbWires)
+	_w.uint32(r1cs.NbPubOut)
+	_w.uint32(r1cs.NbPubIn)
+	_w.uint32(r1cs.NbPrvIn)
+	_w.uint64(r1cs.NbLabels)
+	_w.uint32(r1cs.NbConstraints)
+
+	// constraints section
+	var size uint64
+	for i := 0; i < len(r1cs.Constraints); i++ {
+		c := &r1cs.Constraints[i]
+		size += uint64(3*4 + (len(c.A)+len(c.B)+len(c.C))*(4+n8))
+	}
+	_w.uint32(r1csSectionConstraints)
+	_w.uint64(size)
+	writeLC := func(l LinearCombination) {
+		_w.uint32(uint32(len(l)))
+		for i := 0; i < len(l); i++ {
+			_w.uint32(l[i].WireID)
+			_w.element(&l[i].Coeff, n8)
+		}
+	}
+	for i := 0; i < len(r1cs.Constraints); i++ {
+		writeLC(r1cs.Constraints[i].A)
+		writeLC(r1cs.Constraints[i].B)
+		writeLC(r1cs.Constraints[i].C)
+	}
+
+	// wire to label section
+	_w.uint32(r1csSectionWireToLabel)
+	_w.uint64(uint64(8 * len(r1cs.WireToLabel)))
+	for _, l := range r1cs.WireToLabel {
+		_w.uint64(l)
+	}
+
+	return _w.n, _w.err
+}
+
+// ReadFrom decodes a R1CS in the iden3 binary format
+//
+// Sections may appear in any order; unknown sections (custom gates) are ignored.
+func (r1cs *R1CS) ReadFrom(r io.Reader) (int64, error) {
+	_r := reader{r: r}
+
+	nbSections := _r.header(r1csMagic, r1csVersion)
+
+	var hasHeader, hasConstraints, hasLabels bool
+	var pending [][]byte // sections read before the header, need n8 to be decoded
+	var pendingTypes []uint32
+
+	for i := uint32(0); i < nbSections && _r.err == nil; i++ {
+		sectionType, size := _r.section()
+		if _r.err != nil {
+			break
+		}
+		switch sectionType {
+		case r1csSectionHeader:
+			r1cs.readHeader(&_r)
+			hasHeader = true
+		case r1csSectionConstraints, r1csSectionWireToLabel:
+			if !hasHeader {
+				pending = append(pending, _r.bytes(size))
+				pendingTypes = append(pendingTypes, sectionType)
+				continue
+			}
+			if sectionType == r1csSectionConstraints {
+				r1cs.readConstraints(&_r)
+				hasConstraints = true
+			} else {
+				r1cs.readLabels(&_r, size)
+				hasLabels = true
+			}
+		default:
+			_r.skip(size)
+		}
+	}
+	if _r.err != nil {
+		return _r.n, _r.err
+	}
+	if !hasHeader {
+		return _r.n, fmt.Errorf("%w: header", ErrMissingSection)
+	}
+
+	for i, buf := range pending {
+		pr := reader{r: bytes.NewReader(buf)}
+		if pendingTypes[i] == r1csSectionConstraints {
+			r1cs.readConstraints(&pr)
+			hasConstraints = true
+		} else {
+			r1cs.readLabels(&pr, uint64(len(buf)))
+			hasLabels = true
+		}
+		if pr.err != nil {
+			return _r.n, pr.err
+		}
+	}
+
+	if !hasConstraints {
+		return _r.n, fmt.Errorf("%w: constraints", ErrMissingSection)
+	}
+	if !hasLabels {
+		// the wire to label map is optional in practice; default to the identity
+		r1cs.WireToLabel = make([]uint64, r1cs.NbWires)
+		for i := range r1cs.WireToLabel {
+			r1cs.WireToLabel[i] = uint64(i)
+		}
+	}
+
+	return _r.n, nil
+}
+
+func (r1cs *R1CS) readHeader(r *reader) {
+	r1cs.Prime, _ = r.prime()
+	r1cs.NbWires = r.uint32()
+	r1cs.NbPubOut = r.uint32()
+	r1cs.NbPubIn = r.uint32()
+	r1cs.NbPrvIn = r.uint32()
+	r1cs.NbLabels = r.uint64()
+	r1cs.NbConstraints = r.uint32()
+	if r.err != nil {
+		return
+	}
+	if uint64(r1cs.NbPubOut)+uint64(r1cs.NbPubIn)+uint64(r1cs.NbPrvIn) >= uint64(r1cs.NbWires) {
+		r.err = fmt.Errorf("header declares %d wires, not enough for the inputs and outputs", r1cs.NbWires)
+	}
+}
+
+func (r1cs *R1CS) readConstraints(r *reader) {
+	buf := make([]byte, fieldSize(r1cs.Prime))
+	readLC := func() LinearCombination {
+		n := r.uint32()
+		if r.err != nil {
+			return nil
+		}
+		l := make(LinearCombination, 0, prealloc(uint64(n)))
+		for j := uint32(0); j < n && r.err == nil; j++ {
+			var t Term
+			t.WireID = r.uint32()
+			r.element(&t.Coeff, buf)
+			if r.err == nil && t.WireID >= r1cs.NbWires {
+				r.err = fmt.Errorf("constraint refers to wire %d, header declares %d wires", t.WireID, r1cs.NbWires)
+			}
+			l = append(l, t)
+		}
+		return l
+	}
+	r1cs.Constraints = make([]Constraint, 0, prealloc(uint64(r1cs.NbConstraints)))
+	for i := uint32(0); i < r1cs.NbConstraints && r.err == nil; i++ {
+		var c Constraint
+		c.A = readLC()
+		c.B = readLC()
+		c.C = readLC()
+		r1cs.Constraints = append(r1cs.Constraints, c)
+	}
+}
+
+func (r1cs *R1CS) readLabels(r *reader, size uint64) {
+	if size != 8*uint64(r1cs.NbWires) {
+		r.err = fmt.Errorf("wire to label section has %d bytes, expected %d", size, 8*uint64(r1cs.NbWires))
+		return
+	}
+	r1cs.WireToLabel = make([]uint64, 0, prealloc(uint64(r1cs.NbWires)))
+	for i := uint32(0); i < r1cs.NbWires && r.err == nil; i++ {
+		r1cs.WireToLabel = append(r1cs.WireToLabel, r.uint64())
+	}
+}
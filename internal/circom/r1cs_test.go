@@ -0,0 +1,134 @@
+package circom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestR1CSSectionsOrder(t *testing.T) {
+	assert := require.New(t)
+
+	prime := big.NewInt(101)
+	var r1cs R1CS
+	r1cs.Prime = prime
+	r1cs.NbWires = 3
+	r1cs.NbPubOut = 1
+	r1cs.NbLabels = 3
+	r1cs.NbConstraints = 1
+	r1cs.Constraints = make([]Constraint, 1)
+	r1cs.Constraints[0].A = LinearCombination{{WireID: 2}}
+	r1cs.Constraints[0].A[0].Coeff.SetInt64(100)
+	r1cs.Constraints[0].B = LinearCombination{}
+	r1cs.Constraints[0].C = LinearCombination{{WireID: 1}}
+	r1cs.Constraints[0].C[0].Coeff.SetInt64(1)
+	r1cs.WireToLabel = []uint64{0, 1, 2}
+
+	var buf bytes.Buffer
+	_, err := r1cs.WriteTo(&buf)
+	assert.NoError(err)
+	data := buf.Bytes()
+
+	// move the header section (first one) at the end of the file
+	const fileHeaderSize = 12
+	headerSectionSize := 12 + int(binary.LittleEndian.Uint64(data[fileHeaderSize+4:]))
+	reordered := append([]byte{}, data[:fileHeaderSize]...)
+	reordered = append(reordered, data[fileHeaderSize+headerSectionSize:]...)
+	reordered = append(reordered, data[fileHeaderSize:fileHeaderSize+headerSectionSize]...)
+
+	for _, d := range [][]byte{data, reordered} {
+		var decoded R1CS
+		n, err := decoded.ReadFrom(bytes.NewReader(d))
+		assert.NoError(err)
+		assert.Equal(int64(len(d)), n)
+		assert.Equal(r1cs, decoded)
+	}
+
+	var decoded R1CS
+	_, err = decoded.ReadFrom(bytes.NewReader(append([]byte("wtns"), data[4:]...)))
+	assert.ErrorIs(err, ErrInvalidMagic)
+}
+
+// TestHostileHeaders checks that the sizes and counts declared by a file don't
+// allocate more memory than the file contains
+func TestHostileHeaders(t *testing.T) {
+	assert := require.New(t)
+
+	// header section of a .r1cs file on a 64 bits prime
+	header := func(nbWires, nbConstraints uint32) []byte {
+		var buf bytes.Buffer
+		w := writer{w: &buf}
+		w.uint32(r1csSectionHeader)
+		w.uint64(8 + 8 + 4*4 + 8 + 4)
+		w.uint32(8)
+		w.element(big.NewInt(101), 8)
+		w.uint32(nbWires)
+		w.uint32(1)
+		w.uint32(0)
+		w.uint32(0)
+		w.uint64(uint64(nbWires))
+		w.uint32(nbConstraints)
+		return buf.Bytes()
+	}
+	file := func(magic string, version, nbSections uint32, sections ...[]byte) []byte {
+		var buf bytes.Buffer
+		w := writer{w: &buf}
+		w.header(magic, version, nbSections)
+		for _, s := range sections {
+			w.write(s)
+		}
+		return buf.Bytes()
+	}
+	section := func(sectionType uint32, size uint64, data ...uint32) []byte {
+		var buf bytes.Buffer
+		w := writer{w: &buf}
+		w.uint32(sectionType)
+		w.uint64(size)
+		for _, v := range data {
+			w.uint32(v)
+		}
+		return buf.Bytes()
+	}
+	const huge = ^uint32(0)
+
+	r1csFiles := map[string][]byte{
+		"field size":       file(r1csMagic, r1csVersion, 1, section(r1csSectionHeader, 1<<40, 1<<31)),
+		"pending section":  file(r1csMagic, r1csVersion, 2, section(r1csSectionConstraints, 1<<40)),
+		"constraints":      file(r1csMagic, r1csVersion, 2, header(3, huge), section(r1csSectionConstraints, 1<<40)),
+		"terms":            file(r1csMagic, r1csVersion, 2, header(3, 1), section(r1csSectionConstraints, 1<<40, huge)),
+		"labels":           file(r1csMagic, r1csVersion, 2, header(huge, 0), section(r1csSectionWireToLabel, 8*uint64(huge))),
+		"skipped sections": file(r1csMagic, r1csVersion, 1, section(42, 1<<63)),
+	}
+	wtnsFiles := map[string][]byte{
+		"field size": file(wtnsMagic, wtnsVersion, 1, section(wtnsSectionHeader, 1<<40, 1<<31)),
+		"values":     file(wtnsMagic, wtnsVersion, 2, section(wtnsSectionHeader, 16, 8, 101, 0, huge), section(wtnsSectionValues, 8*uint64(huge))),
+	}
+
+	const maxAlloc = 16 << 20
+	check := func(name string, decode func() error) {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		err := decode()
+		runtime.ReadMemStats(&after)
+		assert.Error(err, name)
+		assert.Less(after.TotalAlloc-before.TotalAlloc, uint64(maxAlloc), name)
+	}
+	for name, data := range r1csFiles {
+		check("r1cs: "+name, func() error {
+			var r1cs R1CS
+			_, err := r1cs.ReadFrom(bytes.NewReader(data))
+			return err
+		})
+	}
+	for name, data := range wtnsFiles {
+		check("wtns: "+name, func() error {
+			var wtns Witness
+			_, err := wtns.ReadFrom(bytes.NewReader(data))
+			return err
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package circom
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+const (
+	wtnsMagic   = "wtns"
+	wtnsVersion = 2
+
+	wtnsSectionHeader = 1
+	wtnsSectionValues = 2
+)
+
+// Witness is the content of a .wtns file: the value of every wire of the circuit,
+// including the constant wire at index 0
+type Witness struct {
+	Prime  *big.Int
+	Values []big.Int
+}
+
+// WriteTo encodes the witness in the iden3 binary format
+func (wtns *Witness) WriteTo(w io.Writer) (int64, error) {
+	if wtns.Prime == nil {
+		return 0, errors.New("missing prime")
+	}
+	n8 := fieldSize(wtns.Prime)
+	_w := writer{w: w}
+
+	_w.header(wtnsMagic, wtnsVersion, 2)
+
+	_w.uint32(wtnsSectionHeader)
+	_w.uint64(uint64(4 + n8 + 4))
+	_w.uint32(uint32(n8))
+	_w.element(wtns.Prime, n8)
+	_w.uint32(uint32(len(wtns.Values)))
+
+	_w.uint32(wtnsSectionValues)
+	_w.uint64(uint64(n8 * len(wtns.Values)))
+	for i := 0; i < len(wtns.Values); i++ {
+		_w.element(&wtns.Values[i], n8)
+	}
+
+	return _w.n, _w.err
+}
+
+// ReadFrom decodes a witness in the iden3 binary format
+//
+// The header section must precede the values section.
+func (wtns *Witness) ReadFrom(r io.Reader) (int64, error) {
+	_r := reader{r: r}
+
+	nbSections := _r.header(wtnsMagic, wtnsVersion)
+
+	var n8, nbValues uint32
+	var hasHeader, hasValues bool
+
+	for i := uint32(0); i < nbSections && _r.err == nil; i++ {
+		sectionType, size := _r.section()
+		if _r.err != nil {
+			break
+		}
+		switch sectionType {
+		case wtnsSectionHeader:
+			wtns.Prime, n8 = _r.prime()
+			nbValues = _r.uint32()
+			hasHeader = true
+		case wtnsSectionValues:
+			if !hasHeader {
+				return _r.n, fmt.Errorf("%w: header must precede values", ErrMissingSection)
+			}
+			if size != uint64(n8)*uint64(nbValues) {
+				return _r.n, fmt.Errorf("values section has %d bytes, expected %d", size, uint64(n8)*uint64(nbValues))
+			}
+			buf := make([]byte, n8)
+			wtns.Values = make([]big.Int, 0, prealloc(uint64(nbValues)))
+			for j := uint32(0); j < nbValues && _r.err == nil; j++ {
+				var v big.Int
+				_r.element(&v, buf)
+				wtns.Values = append(wtns.Values, v)
+			}
+			hasValues = true
+		default:
+			_r.skip(size)
+		}
+	}
+	if _r.err != nil {
+		return _r.n, _r.err
+	}
+	if !hasHeader {
+		return _r.n, fmt.Errorf("%w: header", ErrMissingSection)
+	}
+	if !hasValues {
+		return _r.n, fmt.Errorf("%w: values", ErrMissingSection)
+	}
+
+	return _r.n, nil
+}
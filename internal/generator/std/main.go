@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+)
+
+const copyrightHolder = "ConsenSys Software Inc."
+
+var bgen = bavard.NewBatchGenerator(copyrightHolder, 2022, "gnark")
+
+//go:generate go run main.go
+func main() {
+	bls12_377 := templateData{
+		Package:    "bls12377",
+		Curve:      "BLS12-377",
+		CurveID:    "BLS12_377",
+		OuterCurve: "BW6_761",
+	}
+	bls24_315 := templateData{
+		Package:    "bls24315",
+		Curve:      "BLS24-315",
+		CurveID:    "BLS24_315",
+		OuterCurve: "BW6_633",
+	}
+
+	for _, d := range []templateData{bls12_377, bls24_315} {
+		kzgDir := filepath.Join("../../../std/commitments/kzg", d.Package)
+		entries := []bavard.Entry{
+			{File: filepath.Join(kzgDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+			{File: filepath.Join(kzgDir, "verifier.go"), Templates: []string{"verifier.go.tmpl"}},
+			{File: filepath.Join(kzgDir, "verifier_test.go"), Templates: []string{"tests/verifier.go.tmpl"}},
+		}
+		if err := bgen.Generate(d, d.Package, "./template/commitments/kzg/", entries...); err != nil {
+			panic(err)
+		}
+	}
+
+	// run go fmt on the generated packages
+	cmd := exec.Command("gofmt", "-s", "-w", "../../../std/commitments/kzg")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		panic(err)
+	}
+}
+
+type templateData struct {
+	Package    string // bls12377, bls24315
+	Curve      string // BLS12-377, BLS24-315
+	CurveID    string // BLS12_377, BLS24_315
+	OuterCurve string // ecc.ID of the curve of the circuits: BW6_761, BW6_633
+}
@@ -0,0 +1,59 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package groth16test provides the native side of the tests of the in-circuit
+// Groth16 verifiers (std/groth16_*): proofs of inner circuits, to be assigned to
+// the outer circuits verifying them.
+package groth16test
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+// Prove compiles innerCircuit on curveID, runs a new Groth16 setup and proves
+// innerAssignment. It returns the proof, verified natively, its verifying key
+// and its public witness, which the std/groth16_* packages assign with
+// Proof.Assign, VerifyingKey.Assign and PublicInputsFromWitness.
+func Prove(curveID ecc.ID, innerCircuit, innerAssignment frontend.Circuit) (groth16.Proof, groth16.VerifyingKey, *witness.Witness, error) {
+	ccs, err := frontend.Compile(curveID, r1cs.NewBuilder, innerCircuit)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	innerWitness, err := frontend.NewWitness(innerAssignment, curveID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	proof, err := groth16.Prove(ccs, pk, innerWitness)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	publicWitness, err := innerWitness.Public()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := groth16.Verify(proof, vk, publicWitness); err != nil {
+		return nil, nil, nil, err
+	}
+	return proof, vk, publicWitness, nil
+}
@@ -6,10 +6,16 @@ import (
 
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/sw_bls12377"
 	"github.com/consensys/gnark/std/algebra/sw_bls24315"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash/hashtocurve"
 	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash/pedersen"
 	"github.com/consensys/gnark/std/math/bits"
 )
 
@@ -79,6 +85,39 @@ func initSnippets() {
 		_ = mimc.Sum()
 	})
 
+	registerSnippet("hash/pedersen", func(api frontend.API, newVariable func() frontend.Variable) {
+		pedersen, _ := pedersen.NewPedersen(api, twistedEdwardsID(api.Compiler().Curve()), "gnark")
+		pedersen.Write(newVariable())
+		_ = pedersen.Sum()
+	})
+	registerSnippet("hash/hashtocurve/mapToTwistedEdwards", func(api frontend.API, newVariable func() frontend.Variable) {
+		curve, _ := twistededwards.NewEdCurve(api, twistedEdwardsID(api.Compiler().Curve()))
+		_ = hashtocurve.MapToTwistedEdwards(curve, newVariable())
+	})
+
+	registerSnippet("twistededwards/scalarMul", func(api frontend.API, newVariable func() frontend.Variable) {
+		curve, _ := twistededwards.NewEdCurve(api, twistedEdwardsID(api.Compiler().Curve()))
+		p := twistededwards.Point{X: newVariable(), Y: newVariable()}
+		_ = curve.ScalarMul(p, newVariable())
+	})
+	registerSnippet("twistededwards/scalarMul/fixedBase", func(api frontend.API, newVariable func() frontend.Variable) {
+		curve, _ := twistededwards.NewEdCurve(api, twistedEdwardsID(api.Compiler().Curve()))
+		base := twistededwards.Point{X: curve.Params().Base[0], Y: curve.Params().Base[1]}
+		_ = curve.ScalarMul(base, newVariable())
+	})
+	registerSnippet("twistededwards/doubleBaseScalarMul", func(api frontend.API, newVariable func() frontend.Variable) {
+		curve, _ := twistededwards.NewEdCurve(api, twistedEdwardsID(api.Compiler().Curve()))
+		p1 := twistededwards.Point{X: newVariable(), Y: newVariable()}
+		p2 := twistededwards.Point{X: newVariable(), Y: newVariable()}
+		_ = curve.DoubleBaseScalarMul(p1, p2, newVariable(), newVariable())
+	})
+	registerSnippet("twistededwards/doubleBaseScalarMul/fixedBase", func(api frontend.API, newVariable func() frontend.Variable) {
+		curve, _ := twistededwards.NewEdCurve(api, twistedEdwardsID(api.Compiler().Curve()))
+		base := twistededwards.Point{X: curve.Params().Base[0], Y: curve.Params().Base[1]}
+		p := twistededwards.Point{X: newVariable(), Y: newVariable()}
+		_ = curve.DoubleBaseScalarMul(base, p, newVariable(), newVariable())
+	})
+
 	registerSnippet("pairing_bls12377", func(api frontend.API, newVariable func() frontend.Variable) {
 
 		var dummyG1 sw_bls12377.G1Affine
@@ -97,6 +136,19 @@ func initSnippets() {
 		_ = sw_bls12377.FinalExponentiation(api, resMillerLoop)
 	}, ecc.BW6_761)
 
+	registerSnippet("pairing_bls12377/fixedQ", func(api frontend.API, newVariable func() frontend.Variable) {
+
+		var dummyG1 sw_bls12377.G1Affine
+		dummyG1.X = newVariable()
+		dummyG1.Y = newVariable()
+		_, _, _, g2 := bls12377.Generators()
+
+		resMillerLoop, _ := sw_bls12377.MillerLoopFixedQ(api, []sw_bls12377.G1Affine{dummyG1}, []sw_bls12377.PrecomputedLines{sw_bls12377.PrecomputeLines(g2)})
+
+		// performs the final expo
+		_ = sw_bls12377.FinalExponentiation(api, resMillerLoop)
+	}, ecc.BW6_761)
+
 	registerSnippet("pairing_bls24315", func(api frontend.API, newVariable func() frontend.Variable) {
 
 		var dummyG1 sw_bls24315.G1Affine
@@ -119,6 +171,40 @@ func initSnippets() {
 		_ = sw_bls24315.FinalExponentiation(api, resMillerLoop)
 	}, ecc.BW6_633)
 
+	registerSnippet("pairing_bls24315/fixedQ", func(api frontend.API, newVariable func() frontend.Variable) {
+
+		var dummyG1 sw_bls24315.G1Affine
+		dummyG1.X = newVariable()
+		dummyG1.Y = newVariable()
+		_, _, _, g2 := bls24315.Generators()
+
+		resMillerLoop, _ := sw_bls24315.MillerLoopFixedQ(api, []sw_bls24315.G1Affine{dummyG1}, []sw_bls24315.PrecomputedLines{sw_bls24315.PrecomputeLines(g2)})
+
+		// performs the final expo
+		_ = sw_bls24315.FinalExponentiation(api, resMillerLoop)
+	}, ecc.BW6_633)
+
+}
+
+// twistedEdwardsID returns the twisted Edwards curve defined on the scalar
+// field of curve
+func twistedEdwardsID(curve ecc.ID) tedwards.ID {
+	switch curve {
+	case ecc.BN254:
+		return tedwards.BN254
+	case ecc.BLS12_377:
+		return tedwards.BLS12_377
+	case ecc.BLS12_381:
+		return tedwards.BLS12_381
+	case ecc.BW6_761:
+		return tedwards.BW6_761
+	case ecc.BLS24_315:
+		return tedwards.BLS24_315
+	case ecc.BW6_633:
+		return tedwards.BW6_633
+	default:
+		panic("not implemented")
+	}
 }
 
 type snippetCircuit struct {
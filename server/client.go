@@ -0,0 +1,214 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// Client is a HTTP client of a Server
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a client of the server at baseURL (for example http://localhost:9000).
+// If httpClient is nil, http.DefaultClient is used.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient}
+}
+
+// NewInProcessClient returns a client whose requests are served by s directly, without
+// network
+func NewInProcessClient(s *Server) *Client {
+	return NewClient("http://gnark.local", &http.Client{Transport: handlerTransport{s}})
+}
+
+// handlerTransport is a http.RoundTripper calling a http.Handler
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	w := responseWriter{header: make(http.Header)}
+	t.handler.ServeHTTP(&w, r)
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", w.statusCode, http.StatusText(w.statusCode)),
+		StatusCode:    w.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        w.header,
+		Body:          ioutil.NopCloser(&w.body),
+		ContentLength: int64(w.body.Len()),
+		Request:       r,
+	}, nil
+}
+
+// responseWriter is a http.ResponseWriter buffering the response in memory
+type responseWriter struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// Circuits returns the circuits registered on the server
+func (c *Client) Circuits(ctx context.Context) ([]CircuitInfo, error) {
+	var res []CircuitInfo
+	err := c.do(ctx, http.MethodGet, "/circuits", "", nil, &res)
+	return res, err
+}
+
+// Prove submits a full witness for the circuit registered under name, and returns the
+// status of the created job
+func (c *Client) Prove(ctx context.Context, name string, fullWitness *witness.Witness) (JobStatus, error) {
+	data, err := fullWitness.MarshalBinary()
+	if err != nil {
+		return JobStatus{}, err
+	}
+	return c.ProveRaw(ctx, name, contentTypeBinary, data)
+}
+
+// ProveJSON submits a full witness encoded in JSON (see witness.Witness.UnmarshalJSON)
+func (c *Client) ProveJSON(ctx context.Context, name string, data []byte) (JobStatus, error) {
+	return c.ProveRaw(ctx, name, contentTypeJSON, data)
+}
+
+// ProveRaw submits an encoded witness with the given content type
+func (c *Client) ProveRaw(ctx context.Context, name, contentType string, data []byte) (JobStatus, error) {
+	var res JobStatus
+	err := c.do(ctx, http.MethodPost, "/circuits/"+url.PathEscape(name)+"/prove", contentType, data, &res)
+	return res, err
+}
+
+// Job returns the status of a job
+func (c *Client) Job(ctx context.Context, id string) (JobStatus, error) {
+	var res JobStatus
+	err := c.do(ctx, http.MethodGet, "/jobs/"+url.PathEscape(id), "", nil, &res)
+	return res, err
+}
+
+// Cancel cancels a queued job and returns its status
+func (c *Client) Cancel(ctx context.Context, id string) (JobStatus, error) {
+	var res JobStatus
+	err := c.do(ctx, http.MethodDelete, "/jobs/"+url.PathEscape(id), "", nil, &res)
+	return res, err
+}
+
+// Proof returns the serialized proof of a done job
+func (c *Client) Proof(ctx context.Context, id string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := c.do(ctx, http.MethodGet, "/jobs/"+url.PathEscape(id)+"/proof", "", nil, &buf)
+	return buf.Bytes(), err
+}
+
+// Wait polls the status of a job every interval, until it is finished or ctx is done
+func (c *Client) Wait(ctx context.Context, id string, interval time.Duration) (JobStatus, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		status, err := c.Job(ctx, id)
+		if err != nil || status.Status.finished() {
+			return status, err
+		}
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// do sends a request and decodes the response in res: a *bytes.Buffer receives the raw body,
+// other types are decoded from JSON
+func (c *Client) do(ctx context.Context, method, path, contentType string, body []byte, res interface{}) error {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, r)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		var e errorResponse
+		data, _ := ioutil.ReadAll(resp.Body)
+		if err := json.Unmarshal(data, &e); err != nil || e.Error == "" {
+			e.Error = strings.TrimSpace(string(data))
+		}
+		return responseError(resp.StatusCode, e.Error)
+	}
+
+	if buf, ok := res.(*bytes.Buffer); ok {
+		_, err = buf.ReadFrom(resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
+
+// responseError returns the error matching an error message of the server, such that
+// errors.Is works with the errors of this package
+func responseError(statusCode int, msg string) error {
+	for _, err := range []error{ErrUnknownCircuit, ErrUnknownJob, ErrQueueFull, ErrClosed, ErrJobNotDone, ErrJobRunning, witness.ErrInvalidWitness} {
+		if strings.HasPrefix(msg, err.Error()) {
+			return fmt.Errorf("%w%s", err, strings.TrimPrefix(msg, err.Error()))
+		}
+	}
+	return fmt.Errorf("server returned %d: %s", statusCode, msg)
+}
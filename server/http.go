@@ -0,0 +1,218 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/consensys/gnark/backend/witness"
+)
+
+const (
+	contentTypeJSON   = "application/json"
+	contentTypeBinary = "application/octet-stream"
+)
+
+// CircuitInfo describes a registered circuit
+type CircuitInfo struct {
+	Name          string `json:"name"`
+	Curve         string `json:"curve"`
+	Backend       string `json:"backend"`
+	NbConstraints int    `json:"nbConstraints"`
+	NbPublic      int    `json:"nbPublic"` // number of public values in the witness
+	NbSecret      int    `json:"nbSecret"` // number of secret values in the witness
+}
+
+// errorResponse is the body of non 2xx responses
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP implements http.Handler. The routes are
+//
+//	GET    /circuits              list the registered circuits ([]CircuitInfo)
+//	POST   /circuits/<name>/prove submit a full witness, returns the JobStatus (202)
+//	GET    /jobs/<id>             JobStatus of a job
+//	GET    /jobs/<id>/proof       serialized proof of a done job
+//	DELETE /jobs/<id>             cancel a queued job
+//	GET    /metrics               metrics, in the Prometheus text format
+//
+// Witnesses are submitted either as JSON (Content-Type: application/json, see
+// witness.Witness.UnmarshalJSON) or binary (Content-Type: application/octet-stream, see
+// witness.Witness.UnmarshalBinary). Errors are returned as {"error": "..."}.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(path) == 1 && path[0] == "circuits":
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+		s.handleCircuits(w)
+	case len(path) == 3 && path[0] == "circuits" && path[2] == "prove":
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+		s.handleProve(w, r, path[1])
+	case len(path) == 2 && path[0] == "jobs":
+		switch r.Method {
+		case http.MethodGet:
+			status, err := s.Job(path[1])
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, status)
+		case http.MethodDelete:
+			if err := s.Cancel(path[1]); err != nil {
+				writeError(w, err)
+				return
+			}
+			status, err := s.Job(path[1])
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, status)
+		default:
+			w.Header().Set("Allow", "GET, DELETE")
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"method not allowed"})
+		}
+	case len(path) == 3 && path[0] == "jobs" && path[2] == "proof":
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+		proof, err := s.Proof(path[1])
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", contentTypeBinary)
+		_, _ = w.Write(proof)
+	case len(path) == 1 && path[0] == "metrics":
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		s.metrics.writeTo(w)
+	default:
+		writeJSON(w, http.StatusNotFound, errorResponse{"not found"})
+	}
+}
+
+func (s *Server) handleCircuits(w http.ResponseWriter) {
+	names := s.Circuits()
+	res := make([]CircuitInfo, 0, len(names))
+	for _, name := range names {
+		c, err := s.Circuit(name)
+		if err != nil {
+			continue
+		}
+		res = append(res, CircuitInfo{
+			Name:          name,
+			Curve:         c.CCS.CurveID().String(),
+			Backend:       c.Backend.String(),
+			NbConstraints: c.CCS.GetNbConstraints(),
+			NbPublic:      c.CCS.GetSchema().NbPublic,
+			NbSecret:      c.CCS.GetSchema().NbSecret,
+		})
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) handleProve(w http.ResponseWriter, r *http.Request, name string) {
+	c, err := s.Circuit(name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, s.cfg.MaxWitnessSize))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{err.Error()})
+		return
+	}
+
+	fullWitness, err := witness.New(c.CCS.CurveID(), c.CCS.GetSchema())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case contentTypeJSON:
+		err = fullWitness.UnmarshalJSON(data)
+	case contentTypeBinary:
+		err = fullWitness.UnmarshalBinary(data)
+	default:
+		writeJSON(w, http.StatusUnsupportedMediaType, errorResponse{"expected Content-Type " + contentTypeJSON + " or " + contentTypeBinary})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	id, err := s.Submit(name, fullWitness)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	status, err := s.Job(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/jobs/"+id)
+	writeJSON(w, http.StatusAccepted, status)
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"method not allowed"})
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err with the matching HTTP status code
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrUnknownCircuit), errors.Is(err, ErrUnknownJob):
+		code = http.StatusNotFound
+	case errors.Is(err, witness.ErrInvalidWitness):
+		code = http.StatusBadRequest
+	case errors.Is(err, ErrJobNotDone), errors.Is(err, ErrJobRunning):
+		code = http.StatusConflict
+	case errors.Is(err, ErrQueueFull), errors.Is(err, ErrClosed):
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, errorResponse{err.Error()})
+}
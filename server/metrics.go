@@ -0,0 +1,111 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// proofDurationBuckets are the upper bounds, in seconds, of the proof duration histogram
+var proofDurationBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300}
+
+type counter struct{ v int64 }
+
+func (c *counter) Add(delta int64) { atomic.AddInt64(&c.v, delta) }
+func (c *counter) Load() int64     { return atomic.LoadInt64(&c.v) }
+
+// histogram of proof durations of a circuit
+type histogram struct {
+	buckets []uint64 // cumulative counts, one per proofDurationBuckets
+	count   uint64
+	sum     float64
+}
+
+type metrics struct {
+	submitted, rejected, done, failed, cancelled counter
+	queued, running                              counter
+
+	mu        sync.Mutex
+	durations map[string]*histogram // circuit -> proof durations
+}
+
+func (m *metrics) init() {
+	m.durations = make(map[string]*histogram)
+}
+
+// observe records the duration of a successful proof of circuit
+func (m *metrics) observe(circuit string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.durations[circuit]
+	if !ok {
+		h = &histogram{buckets: make([]uint64, len(proofDurationBuckets))}
+		m.durations[circuit] = h
+	}
+	seconds := d.Seconds()
+	for i, bound := range proofDurationBuckets {
+		if seconds <= bound {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// labelEscaper escapes a label value in the Prometheus text exposition format, in which only
+// the backslash, the double quote and the line feed are escaped
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// writeTo writes the metrics in the Prometheus text exposition format
+func (m *metrics) writeTo(w io.Writer) {
+	writeMetric := func(name, typ, help string, value int64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", name, help, name, typ, name, value)
+	}
+	writeMetric("gnark_jobs_submitted_total", "counter", "Number of accepted proof jobs.", m.submitted.Load())
+	writeMetric("gnark_jobs_rejected_total", "counter", "Number of proof jobs rejected because the queue was full.", m.rejected.Load())
+	writeMetric("gnark_jobs_done_total", "counter", "Number of successful proof jobs.", m.done.Load())
+	writeMetric("gnark_jobs_failed_total", "counter", "Number of failed proof jobs.", m.failed.Load())
+	writeMetric("gnark_jobs_cancelled_total", "counter", "Number of cancelled proof jobs.", m.cancelled.Load())
+	writeMetric("gnark_jobs_queued", "gauge", "Number of proof jobs waiting for a worker.", m.queued.Load())
+	writeMetric("gnark_jobs_running", "gauge", "Number of proof jobs being computed.", m.running.Load())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	const name = "gnark_proof_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Duration of successful proofs.\n# TYPE %s histogram\n", name, name)
+	circuits := make([]string, 0, len(m.durations))
+	for circuit := range m.durations {
+		circuits = append(circuits, circuit)
+	}
+	sort.Strings(circuits)
+	for _, circuit := range circuits {
+		h := m.durations[circuit]
+		label := labelEscaper.Replace(circuit)
+		for i, bound := range proofDurationBuckets {
+			fmt.Fprintf(w, "%s_bucket{circuit=\"%s\",le=\"%g\"} %d\n", name, label, bound, h.buckets[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{circuit=\"%s\",le=\"+Inf\"} %d\n", name, label, h.count)
+		fmt.Fprintf(w, "%s_sum{circuit=\"%s\"} %g\n", name, label, h.sum)
+		fmt.Fprintf(w, "%s_count{circuit=\"%s\"} %d\n", name, label, h.count)
+	}
+}
@@ -0,0 +1,466 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package server provides a proving server: registered circuits (a compiled constraint
+// system and its proving key) are proven from submitted witnesses by a bounded pool of
+// workers.
+//
+// Jobs are queued; when the queue is full, submissions are rejected with ErrQueueFull. A job
+// can only be cancelled while it is queued: the provers can't be interrupted, so cancelling a
+// running job fails with ErrJobRunning. Finished jobs are removed periodically once they are
+// older than Config.JobRetention.
+//
+// Server implements http.Handler (see ServeHTTP for the routes), and Client is the matching
+// HTTP client. NewInProcessClient returns a Client calling the Server directly, without
+// network.
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/logger"
+)
+
+var (
+	ErrUnknownCircuit = errors.New("unknown circuit")
+	ErrCircuitExists  = errors.New("circuit already registered")
+	ErrUnknownJob     = errors.New("unknown job")
+	ErrQueueFull      = errors.New("job queue is full")
+	ErrClosed         = errors.New("server is closed")
+	ErrJobNotDone     = errors.New("job is not done")
+	ErrJobRunning     = errors.New("job is running")
+)
+
+// Config of a Server
+type Config struct {
+	// NbWorkers is the number of proofs computed concurrently (default 1)
+	NbWorkers int
+
+	// QueueSize is the number of jobs waiting for a worker before submissions are rejected
+	// (default 64)
+	QueueSize int
+
+	// JobRetention is how long finished jobs (and their proofs) are kept (default 1 hour)
+	JobRetention time.Duration
+
+	// MaxWitnessSize is the maximum size in bytes of a witness submitted over HTTP
+	// (default 64MB)
+	MaxWitnessSize int64
+}
+
+// Circuit is a circuit registered to the server
+type Circuit struct {
+	CCS     frontend.CompiledConstraintSystem
+	Backend backend.ID
+
+	// ProvingKey is a groth16.ProvingKey or a plonk.ProvingKey, depending on Backend. A
+	// plonk.ProvingKey must be initialized with its KZG SRS.
+	ProvingKey interface{}
+
+	// ProverOptions are passed to the prover, for example to provide hint functions
+	ProverOptions []backend.ProverOption
+}
+
+// Status of a job
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusDone      Status = "done"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// finished returns true if the status is final
+func (s Status) finished() bool {
+	return s == StatusDone || s == StatusFailed || s == StatusCancelled
+}
+
+// JobStatus describes a job
+type JobStatus struct {
+	ID         string     `json:"id"`
+	Circuit    string     `json:"circuit"`
+	Status     Status     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	Submitted  time.Time  `json:"submitted"`
+	Started    *time.Time `json:"started,omitempty"`
+	Finished   *time.Time `json:"finished,omitempty"`
+	ProofBytes int        `json:"proofBytes,omitempty"`
+}
+
+type job struct {
+	JobStatus
+	witness *witness.Witness
+	proof   []byte
+	done    chan struct{} // closed when the job is finished
+}
+
+// Server proves witnesses of registered circuits
+type Server struct {
+	cfg Config
+
+	mu       sync.RWMutex
+	circuits map[string]*Circuit
+	jobs     map[string]*job
+	closed   bool
+
+	queue   chan *job
+	stop    chan struct{} // closed by Close to stop the removal of expired jobs
+	wg      sync.WaitGroup
+	metrics metrics
+}
+
+// New returns a Server and starts its workers. Close must be called to stop them.
+func New(cfg Config) *Server {
+	if cfg.NbWorkers <= 0 {
+		cfg.NbWorkers = 1
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 64
+	}
+	if cfg.JobRetention <= 0 {
+		cfg.JobRetention = time.Hour
+	}
+	if cfg.MaxWitnessSize <= 0 {
+		cfg.MaxWitnessSize = 64 << 20
+	}
+	s := &Server{
+		cfg:      cfg,
+		circuits: make(map[string]*Circuit),
+		jobs:     make(map[string]*job),
+		queue:    make(chan *job, cfg.QueueSize),
+		stop:     make(chan struct{}),
+	}
+	s.metrics.init()
+	s.wg.Add(cfg.NbWorkers + 1)
+	for i := 0; i < cfg.NbWorkers; i++ {
+		go s.worker()
+	}
+	go s.removeExpiredJobsPeriodically()
+	return s
+}
+
+// Register adds a circuit to the server, under name
+func (s *Server) Register(name string, c Circuit) error {
+	if c.CCS == nil {
+		return errors.New("missing constraint system")
+	}
+	switch c.Backend {
+	case backend.GROTH16:
+		if _, ok := c.ProvingKey.(groth16.ProvingKey); !ok {
+			return errors.New("expected a groth16.ProvingKey")
+		}
+	case backend.PLONK:
+		if _, ok := c.ProvingKey.(plonk.ProvingKey); !ok {
+			return errors.New("expected a plonk.ProvingKey")
+		}
+	default:
+		return fmt.Errorf("unsupported backend %s", c.Backend)
+	}
+	if c.CCS.GetSchema() == nil {
+		return errors.New("constraint system has no schema")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.circuits[name]; ok {
+		return fmt.Errorf("%w: %s", ErrCircuitExists, name)
+	}
+	s.circuits[name] = &c
+	return nil
+}
+
+// Circuits returns the names of the registered circuits, sorted
+func (s *Server) Circuits() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.circuits))
+	for name := range s.circuits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Circuit returns the circuit registered under name
+func (s *Server) Circuit(name string) (*Circuit, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.circuits[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCircuit, name)
+	}
+	return c, nil
+}
+
+// Submit queues a proof of the circuit registered under name, for the given full witness,
+// and returns the job id
+func (s *Server) Submit(name string, fullWitness *witness.Witness) (string, error) {
+	c, err := s.Circuit(name)
+	if err != nil {
+		return "", err
+	}
+	if fullWitness.CurveID != c.CCS.CurveID() {
+		return "", fmt.Errorf("%w: witness is on %s, circuit on %s", witness.ErrInvalidWitness, fullWitness.CurveID, c.CCS.CurveID())
+	}
+	nbValues := c.CCS.GetSchema().NbPublic + c.CCS.GetSchema().NbSecret
+	if fullWitness.Vector == nil || fullWitness.Vector.Len() != nbValues {
+		return "", fmt.Errorf("%w: expected a full witness with %d values", witness.ErrInvalidWitness, nbValues)
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return "", err
+	}
+	j := &job{
+		JobStatus: JobStatus{
+			ID:        id,
+			Circuit:   name,
+			Status:    StatusQueued,
+			Submitted: time.Now(),
+		},
+		witness: fullWitness,
+		done:    make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return "", ErrClosed
+	}
+	select {
+	case s.queue <- j:
+	default:
+		s.metrics.rejected.Add(1)
+		return "", ErrQueueFull
+	}
+	s.jobs[id] = j
+	s.metrics.submitted.Add(1)
+	s.metrics.queued.Add(1)
+	return id, nil
+}
+
+// Job returns the status of a job
+func (s *Server) Job(id string) (JobStatus, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return JobStatus{}, fmt.Errorf("%w: %s", ErrUnknownJob, id)
+	}
+	return j.JobStatus, nil
+}
+
+// Proof returns the serialized proof of a job (see groth16.Proof and plonk.Proof WriteTo)
+func (s *Server) Proof(id string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJob, id)
+	}
+	if j.Status != StatusDone {
+		return nil, fmt.Errorf("%w: job is %s", ErrJobNotDone, j.Status)
+	}
+	return j.proof, nil
+}
+
+// Wait blocks until the job is finished or ctx is done, and returns its status
+func (s *Server) Wait(ctx context.Context, id string) (JobStatus, error) {
+	s.mu.RLock()
+	j, ok := s.jobs[id]
+	s.mu.RUnlock()
+	if !ok {
+		return JobStatus{}, fmt.Errorf("%w: %s", ErrUnknownJob, id)
+	}
+	select {
+	case <-j.done:
+		return s.Job(id)
+	case <-ctx.Done():
+		return JobStatus{}, ctx.Err()
+	}
+}
+
+// Cancel cancels a queued job. A running job can't be cancelled (ErrJobRunning), and
+// cancelling a finished job has no effect.
+func (s *Server) Cancel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownJob, id)
+	}
+	switch j.Status {
+	case StatusQueued:
+		s.metrics.queued.Add(-1)
+	case StatusRunning:
+		return fmt.Errorf("%w: %s", ErrJobRunning, id)
+	default:
+		return nil
+	}
+	s.finish(j, StatusCancelled, nil, nil)
+	return nil
+}
+
+// Close cancels the pending jobs and waits for the running ones to finish
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.queue)
+	close(s.stop)
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Server) worker() {
+	defer s.wg.Done()
+	for j := range s.queue {
+		s.mu.Lock()
+		if j.Status != StatusQueued || s.closed {
+			if j.Status == StatusQueued {
+				s.metrics.queued.Add(-1)
+				s.finish(j, StatusCancelled, nil, ErrClosed)
+			}
+			s.mu.Unlock()
+			continue
+		}
+		now := time.Now()
+		j.Status = StatusRunning
+		j.Started = &now
+		c := s.circuits[j.Circuit]
+		s.metrics.queued.Add(-1)
+		s.metrics.running.Add(1)
+		s.mu.Unlock()
+
+		proof, err := prove(c, j.witness)
+
+		s.mu.Lock()
+		s.metrics.running.Add(-1)
+		if err != nil {
+			s.finish(j, StatusFailed, nil, err)
+		} else {
+			s.finish(j, StatusDone, proof, nil)
+			s.metrics.observe(j.Circuit, j.Finished.Sub(*j.Started))
+		}
+		s.mu.Unlock()
+	}
+}
+
+// finish sets the final status of j; s.mu must be held
+func (s *Server) finish(j *job, status Status, proof []byte, err error) {
+	now := time.Now()
+	j.Status = status
+	j.Finished = &now
+	j.proof = proof
+	j.ProofBytes = len(proof)
+	j.witness = nil
+	if err != nil {
+		j.Error = err.Error()
+	}
+	close(j.done)
+
+	log := logger.Logger()
+	switch status {
+	case StatusDone:
+		s.metrics.done.Add(1)
+		log.Debug().Str("job", j.ID).Str("circuit", j.Circuit).Dur("took", now.Sub(*j.Started)).Msg("proof done")
+	case StatusFailed:
+		s.metrics.failed.Add(1)
+		log.Warn().Str("job", j.ID).Str("circuit", j.Circuit).Err(err).Msg("proof failed")
+	case StatusCancelled:
+		s.metrics.cancelled.Add(1)
+	}
+}
+
+// removeExpiredJobsPeriodically calls removeExpiredJobs every half retention, until the server
+// is closed
+func (s *Server) removeExpiredJobsPeriodically() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.cfg.JobRetention / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			s.removeExpiredJobs()
+			s.mu.Unlock()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// removeExpiredJobs removes the jobs finished for longer than the retention; s.mu must be held
+func (s *Server) removeExpiredJobs() {
+	deadline := time.Now().Add(-s.cfg.JobRetention)
+	for id, j := range s.jobs {
+		if j.Status.finished() && j.Finished.Before(deadline) {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// prove runs the prover of c and returns the serialized proof
+func prove(c *Circuit, fullWitness *witness.Witness) (_ []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("prover panicked: %v", r)
+		}
+	}()
+
+	var proof io.WriterTo
+	switch c.Backend {
+	case backend.GROTH16:
+		proof, err = groth16.Prove(c.CCS, c.ProvingKey.(groth16.ProvingKey), fullWitness, c.ProverOptions...)
+	case backend.PLONK:
+		proof, err = plonk.Prove(c.CCS, c.ProvingKey.(plonk.ProvingKey), fullWitness, c.ProverOptions...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func newJobID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(circuit.X, circuit.X, circuit.X)
+	api.AssertIsEqual(circuit.Y, api.Add(x3, circuit.X, 5))
+	return nil
+}
+
+// unblock releases the calls to blockingHint
+var unblock = make(chan struct{})
+
+func blockingHint(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	<-unblock
+	results[0].Set(inputs[0])
+	return nil
+}
+
+// blockingCircuit can only be proven once unblock is closed
+type blockingCircuit struct {
+	X frontend.Variable `gnark:",public"`
+}
+
+func (circuit *blockingCircuit) Define(api frontend.API) error {
+	res, err := api.Compiler().NewHint(blockingHint, 1, circuit.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(res[0], circuit.X)
+	return nil
+}
+
+// registerCubic registers cubicCircuit for groth16 and plonk, and returns the groth16
+// verifying key
+func registerCubic(t *testing.T, s *Server) groth16.VerifyingKey {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	assert.NoError(s.Register("cubic_groth16", Circuit{CCS: ccs, Backend: backend.GROTH16, ProvingKey: pk}))
+	assert.ErrorIs(s.Register("cubic_groth16", Circuit{CCS: ccs, Backend: backend.GROTH16, ProvingKey: pk}), ErrCircuitExists)
+
+	ccs, err = frontend.Compile(ecc.BN254, scs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	ppk, _, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	assert.Error(s.Register("wrong_key", Circuit{CCS: ccs, Backend: backend.GROTH16, ProvingKey: ppk}))
+	assert.NoError(s.Register("cubic_plonk", Circuit{CCS: ccs, Backend: backend.PLONK, ProvingKey: ppk}))
+
+	return vk
+}
+
+func TestProve(t *testing.T) {
+	assert := require.New(t)
+	ctx := context.Background()
+
+	s := New(Config{NbWorkers: 2})
+	defer s.Close()
+	vk := registerCubic(t, s)
+	client := NewInProcessClient(s)
+
+	circuits, err := client.Circuits(ctx)
+	assert.NoError(err)
+	assert.Len(circuits, 2)
+	assert.Equal("cubic_groth16", circuits[0].Name)
+	assert.Equal("groth16", circuits[0].Backend)
+	assert.Equal(1, circuits[0].NbPublic)
+	assert.Equal(1, circuits[0].NbSecret)
+
+	fullWitness, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, ecc.BN254)
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+
+	// binary witness, groth16
+	job, err := client.Prove(ctx, "cubic_groth16", fullWitness)
+	assert.NoError(err)
+	status, err := client.Wait(ctx, job.ID, time.Millisecond)
+	assert.NoError(err)
+	assert.Equal(StatusDone, status.Status, status.Error)
+	data, err := client.Proof(ctx, job.ID)
+	assert.NoError(err)
+	assert.Equal(status.ProofBytes, len(data))
+	proof := groth16.NewProof(ecc.BN254)
+	_, err = proof.ReadFrom(bytes.NewReader(data))
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, publicWitness))
+
+	// JSON witness, plonk
+	job, err = client.ProveJSON(ctx, "cubic_plonk", []byte(`{"X": 3, "Y": 35}`))
+	assert.NoError(err)
+	status, err = s.Wait(ctx, job.ID)
+	assert.NoError(err)
+	assert.Equal(StatusDone, status.Status, status.Error)
+
+	// a witness that doesn't solve the circuit
+	job, err = client.ProveJSON(ctx, "cubic_plonk", []byte(`{"X": 3, "Y": 36}`))
+	assert.NoError(err)
+	status, err = client.Wait(ctx, job.ID, time.Millisecond)
+	assert.NoError(err)
+	assert.Equal(StatusFailed, status.Status)
+	_, err = client.Proof(ctx, job.ID)
+	assert.ErrorIs(err, ErrJobNotDone)
+
+	// invalid submissions
+	_, err = client.Prove(ctx, "unknown", fullWitness)
+	assert.ErrorIs(err, ErrUnknownCircuit)
+	_, err = client.Prove(ctx, "cubic_groth16", publicWitness)
+	assert.Error(err)
+	_, err = client.ProveJSON(ctx, "cubic_groth16", []byte(`{"X": 3, "Z": 35}`))
+	assert.Error(err)
+	_, err = client.Job(ctx, "unknown")
+	assert.ErrorIs(err, ErrUnknownJob)
+
+	// metrics
+	resp, err := client.httpClient.Get(client.baseURL + "/metrics")
+	assert.NoError(err)
+	defer resp.Body.Close()
+	var metrics bytes.Buffer
+	_, err = metrics.ReadFrom(resp.Body)
+	assert.NoError(err)
+	assert.Contains(metrics.String(), "gnark_jobs_done_total 2\n")
+	assert.Contains(metrics.String(), "gnark_jobs_failed_total 1\n")
+	assert.Contains(metrics.String(), `gnark_proof_duration_seconds_count{circuit="cubic_plonk"} 1`)
+}
+
+func TestQueue(t *testing.T) {
+	assert := require.New(t)
+	ctx := context.Background()
+
+	s := New(Config{NbWorkers: 1, QueueSize: 1})
+	defer s.Close()
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &blockingCircuit{})
+	assert.NoError(err)
+	pk, _, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	assert.NoError(s.Register("blocking", Circuit{
+		CCS:           ccs,
+		Backend:       backend.GROTH16,
+		ProvingKey:    pk,
+		ProverOptions: []backend.ProverOption{backend.WithHints(blockingHint)},
+	}))
+	client := NewInProcessClient(s)
+
+	fullWitness, err := frontend.NewWitness(&blockingCircuit{X: 42}, ecc.BN254)
+	assert.NoError(err)
+
+	// the first job is picked by the worker and blocks it
+	running, err := client.Prove(ctx, "blocking", fullWitness)
+	assert.NoError(err)
+	for status, _ := s.Job(running.ID); status.Status != StatusRunning; status, _ = s.Job(running.ID) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the second one waits in the queue, the third one is rejected
+	queued, err := client.Prove(ctx, "blocking", fullWitness)
+	assert.NoError(err)
+	assert.Equal(StatusQueued, queued.Status)
+	_, err = client.Prove(ctx, "blocking", fullWitness)
+	assert.ErrorIs(err, ErrQueueFull)
+
+	// the running job can't be cancelled
+	_, err = client.Cancel(ctx, running.ID)
+	assert.ErrorIs(err, ErrJobRunning)
+
+	// cancel the queued job
+	status, err := client.Cancel(ctx, queued.ID)
+	assert.NoError(err)
+	assert.Equal(StatusCancelled, status.Status)
+
+	// a job can't be waited on forever
+	shortCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = s.Wait(shortCtx, running.ID)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+
+	close(unblock)
+	status, err = s.Wait(ctx, running.ID)
+	assert.NoError(err)
+	assert.Equal(StatusDone, status.Status, status.Error)
+
+	// the cancelled job is skipped by the worker
+	status, err = s.Job(queued.ID)
+	assert.NoError(err)
+	assert.Equal(StatusCancelled, status.Status)
+
+	s.Close()
+	_, err = s.Submit("blocking", fullWitness)
+	assert.ErrorIs(err, ErrClosed)
+}
+
+func TestJobRetention(t *testing.T) {
+	assert := require.New(t)
+	ctx := context.Background()
+
+	s := New(Config{JobRetention: 20 * time.Millisecond})
+	defer s.Close()
+	registerCubic(t, s)
+
+	fullWitness, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, ecc.BN254)
+	assert.NoError(err)
+	id, err := s.Submit("cubic_groth16", fullWitness)
+	assert.NoError(err)
+	status, err := s.Wait(ctx, id)
+	assert.NoError(err)
+	assert.Equal(StatusDone, status.Status, status.Error)
+
+	// the job is removed without further submissions
+	deadline := time.Now().Add(5 * time.Second)
+	for _, err = s.Job(id); err == nil && time.Now().Before(deadline); _, err = s.Job(id) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.ErrorIs(err, ErrUnknownJob)
+}
+
+func TestMetricsLabels(t *testing.T) {
+	assert := require.New(t)
+
+	var m metrics
+	m.init()
+	m.observe("é\u00a0\t\"\\\n", time.Second)
+	var buf bytes.Buffer
+	m.writeTo(&buf)
+	assert.Contains(buf.String(), "gnark_proof_duration_seconds_count{circuit=\"é\u00a0\t\\\"\\\\\\n\"} 1")
+}
+
+func TestHTTPErrors(t *testing.T) {
+	assert := require.New(t)
+
+	s := New(Config{})
+	defer s.Close()
+	client := NewInProcessClient(s)
+
+	for _, tc := range []struct {
+		method, path, contentType string
+		code                      int
+	}{
+		{"GET", "/unknown", "", 404},
+		{"POST", "/circuits", "", 405},
+		{"GET", "/circuits/cubic/prove", "", 405},
+		{"POST", "/circuits/cubic/prove", "application/json", 404},
+		{"PUT", "/jobs/42", "", 405},
+		{"GET", "/jobs/42/proof", "", 404},
+	} {
+		req, err := http.NewRequest(tc.method, client.baseURL+tc.path, strings.NewReader("{}"))
+		assert.NoError(err)
+		req.Header.Set("Content-Type", tc.contentType)
+		resp, err := client.httpClient.Do(req)
+		assert.NoError(err)
+		resp.Body.Close()
+		assert.Equal(tc.code, resp.StatusCode, tc.method+" "+tc.path)
+	}
+}
@@ -0,0 +1,324 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fields_bn254
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fp "github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// E12 element in a quadratic extension
+type E12 struct {
+	C0, C1 E6
+}
+
+// E12Placeholder returns an E12 whose limbs are allocated, to be used in a
+// circuit definition
+func E12Placeholder() E12 {
+	return E12{C0: E6Placeholder(), C1: E6Placeholder()}
+}
+
+// frobeniusCoeffs[j-1][i-1] = (9+u)^(i(p^j-1)/6), for j=1,2 and i=1..5
+var frobeniusCoeffs [2][5][2]*big.Int
+
+func init() {
+	p := bn254fp.Modulus()
+	var q bn254.G2Affine
+	xi := q.X
+	xi.A0.SetUint64(9)
+	xi.A1.SetOne()
+
+	// p-1 and p²-1
+	var e [2]big.Int
+	e[0].Sub(p, big.NewInt(1))
+	e[1].Mul(p, p).Sub(&e[1], big.NewInt(1))
+	for j := 0; j < 2; j++ {
+		for i := 1; i <= 5; i++ {
+			exp := new(big.Int).Mul(&e[j], big.NewInt(int64(i)))
+			exp.Div(exp, big.NewInt(6))
+			c := q.X
+			c.Exp(xi, exp)
+			var a0, a1 big.Int
+			c.A0.ToBigIntRegular(&a0)
+			c.A1.ToBigIntRegular(&a1)
+			frobeniusCoeffs[j][i-1] = [2]*big.Int{&a0, &a1}
+		}
+	}
+}
+
+// SetZero returns a newly allocated element equal to 0
+func (e *E12) SetZero(fp *emulated.Field) *E12 {
+	e.C0.SetZero(fp)
+	e.C1.SetZero(fp)
+	return e
+}
+
+// SetOne returns a newly allocated element equal to 1
+func (e *E12) SetOne(fp *emulated.Field) *E12 {
+	e.C0.SetOne(fp)
+	e.C1.SetZero(fp)
+	return e
+}
+
+// Add adds 2 elmts in Fp12
+func (e *E12) Add(fp *emulated.Field, e1, e2 E12) *E12 {
+	e.C0.Add(fp, e1.C0, e2.C0)
+	e.C1.Add(fp, e1.C1, e2.C1)
+	return e
+}
+
+// Sub substracts 2 elmts in Fp12
+func (e *E12) Sub(fp *emulated.Field, e1, e2 E12) *E12 {
+	e.C0.Sub(fp, e1.C0, e2.C0)
+	e.C1.Sub(fp, e1.C1, e2.C1)
+	return e
+}
+
+// Neg negates an Fp12elmt
+func (e *E12) Neg(fp *emulated.Field, e1 E12) *E12 {
+	e.C0.Neg(fp, e1.C0)
+	e.C1.Neg(fp, e1.C1)
+	return e
+}
+
+// Conjugate applies Frob**6 (conjugation over Fp6)
+func (e *E12) Conjugate(fp *emulated.Field, e1 E12) *E12 {
+	e.C0 = e1.C0
+	e.C1.Neg(fp, e1.C1)
+	return e
+}
+
+// Mul multiplies 2 elmts in Fp12
+func (e *E12) Mul(fp *emulated.Field, e1, e2 E12) *E12 {
+	var a, b, c E6
+	a.Add(fp, e1.C0, e1.C1)
+	b.Add(fp, e2.C0, e2.C1)
+	a.Mul(fp, a, b)
+	b.Mul(fp, e1.C0, e2.C0)
+	c.Mul(fp, e1.C1, e2.C1)
+	e.C1.Sub(fp, a, b).Sub(fp, e.C1, c)
+	e.C0.MulByNonResidue(fp, c).Add(fp, e.C0, b)
+	return e
+}
+
+// Square squares an element in Fp12
+func (e *E12) Square(fp *emulated.Field, x E12) *E12 {
+	// Algorithm 22 from https://eprint.iacr.org/2010/354.pdf
+	var c0, c2, c3 E6
+	c0.Sub(fp, x.C0, x.C1)
+	c3.MulByNonResidue(fp, x.C1).Neg(fp, c3).Add(fp, x.C0, c3)
+	c2.Mul(fp, x.C0, x.C1)
+	c0.Mul(fp, c0, c3).Add(fp, c0, c2)
+	e.C1.Double(fp, c2)
+	c2.MulByNonResidue(fp, c2)
+	e.C0.Add(fp, c0, c2)
+	return e
+}
+
+// CyclotomicSquare squares an element of the cyclotomic subgroup of Fp12
+// (Granger-Scott, https://eprint.iacr.org/2009/565.pdf, 3.2)
+func (e *E12) CyclotomicSquare(fp *emulated.Field, x E12) *E12 {
+	var t [9]E2
+
+	t[0].Square(fp, x.C1.B1)
+	t[1].Square(fp, x.C0.B0)
+	t[6].Add(fp, x.C1.B1, x.C0.B0).Square(fp, t[6]).Sub(fp, t[6], t[0]).Sub(fp, t[6], t[1]) // 2*x4*x0
+	t[2].Square(fp, x.C0.B2)
+	t[3].Square(fp, x.C1.B0)
+	t[7].Add(fp, x.C0.B2, x.C1.B0).Square(fp, t[7]).Sub(fp, t[7], t[2]).Sub(fp, t[7], t[3]) // 2*x2*x3
+	t[4].Square(fp, x.C1.B2)
+	t[5].Square(fp, x.C0.B1)
+	t[8].Add(fp, x.C1.B2, x.C0.B1).Square(fp, t[8]).Sub(fp, t[8], t[4]).Sub(fp, t[8], t[5]).MulByNonResidue(fp, t[8]) // 2*x5*x1*u
+
+	t[0].MulByNonResidue(fp, t[0]).Add(fp, t[0], t[1]) // x4^2*u + x0^2
+	t[2].MulByNonResidue(fp, t[2]).Add(fp, t[2], t[3]) // x2^2*u + x3^2
+	t[4].MulByNonResidue(fp, t[4]).Add(fp, t[4], t[5]) // x5^2*u + x1^2
+
+	e.C0.B0.Sub(fp, t[0], x.C0.B0).Double(fp, e.C0.B0).Add(fp, e.C0.B0, t[0])
+	e.C0.B1.Sub(fp, t[2], x.C0.B1).Double(fp, e.C0.B1).Add(fp, e.C0.B1, t[2])
+	e.C0.B2.Sub(fp, t[4], x.C0.B2).Double(fp, e.C0.B2).Add(fp, e.C0.B2, t[4])
+
+	e.C1.B0.Add(fp, t[8], x.C1.B0).Double(fp, e.C1.B0).Add(fp, e.C1.B0, t[8])
+	e.C1.B1.Add(fp, t[6], x.C1.B1).Double(fp, e.C1.B1).Add(fp, e.C1.B1, t[6])
+	e.C1.B2.Add(fp, t[7], x.C1.B2).Double(fp, e.C1.B2).Add(fp, e.C1.B2, t[7])
+
+	return e
+}
+
+// nSquare repeated cyclotomic squarings
+func (e *E12) nSquare(fp *emulated.Field, n int) *E12 {
+	for i := 0; i < n; i++ {
+		e.CyclotomicSquare(fp, *e)
+	}
+	return e
+}
+
+// Inverse inverses an Fp12 elmt
+func (e *E12) Inverse(fp *emulated.Field, e1 E12) *E12 {
+	// Algorithm 23 from https://eprint.iacr.org/2010/354.pdf
+	var t0, t1, tmp E6
+	t0.Square(fp, e1.C0)
+	t1.Square(fp, e1.C1)
+	tmp.MulByNonResidue(fp, t1)
+	t0.Sub(fp, t0, tmp)
+	t1.Inverse(fp, t0)
+	e.C0.Mul(fp, e1.C0, t1)
+	e.C1.Mul(fp, e1.C1, t1).Neg(fp, e.C1)
+	return e
+}
+
+// Frobenius applies frob to an fp12 elmt
+func (e *E12) Frobenius(fp *emulated.Field, e1 E12) *E12 {
+	// Algorithm 28 from https://eprint.iacr.org/2010/354.pdf
+	var t [6]E2
+	t[0].Conjugate(fp, e1.C0.B0)
+	t[1].Conjugate(fp, e1.C0.B1)
+	t[2].Conjugate(fp, e1.C0.B2)
+	t[3].Conjugate(fp, e1.C1.B0)
+	t[4].Conjugate(fp, e1.C1.B1)
+	t[5].Conjugate(fp, e1.C1.B2)
+
+	t[1].mulByFixed(fp, t[1], frobeniusCoeffs[0][1])
+	t[2].mulByFixed(fp, t[2], frobeniusCoeffs[0][3])
+	t[3].mulByFixed(fp, t[3], frobeniusCoeffs[0][0])
+	t[4].mulByFixed(fp, t[4], frobeniusCoeffs[0][2])
+	t[5].mulByFixed(fp, t[5], frobeniusCoeffs[0][4])
+
+	e.C0.B0, e.C0.B1, e.C0.B2 = t[0], t[1], t[2]
+	e.C1.B0, e.C1.B1, e.C1.B2 = t[3], t[4], t[5]
+	return e
+}
+
+// FrobeniusSquare applies frob**2 to an fp12 elmt
+func (e *E12) FrobeniusSquare(fp *emulated.Field, e1 E12) *E12 {
+	// Algorithm 29 from https://eprint.iacr.org/2010/354.pdf
+	e.C0.B0 = e1.C0.B0
+	e.C0.B1.mulByFixed(fp, e1.C0.B1, frobeniusCoeffs[1][1])
+	e.C0.B2.mulByFixed(fp, e1.C0.B2, frobeniusCoeffs[1][3])
+	e.C1.B0.mulByFixed(fp, e1.C1.B0, frobeniusCoeffs[1][0])
+	e.C1.B1.mulByFixed(fp, e1.C1.B1, frobeniusCoeffs[1][2])
+	e.C1.B2.mulByFixed(fp, e1.C1.B2, frobeniusCoeffs[1][4])
+	return e
+}
+
+// Expt sets e to x**t in the cyclotomic subgroup of Fp12, where t is the seed
+// of the curve (t = 0x44e992b44a6909f1)
+func (e *E12) Expt(fp *emulated.Field, x E12) *E12 {
+	// addition chain of gnark-crypto, 62 squares and 17 multiplies
+	var result, t0, t1, t2, t3, t4, t5, t6 E12
+
+	t3.CyclotomicSquare(fp, x)
+	t5.CyclotomicSquare(fp, t3)
+	result.CyclotomicSquare(fp, t5)
+	t0.CyclotomicSquare(fp, result)
+	t2.Mul(fp, x, t0)
+	t0.Mul(fp, t3, t2)
+	t1.Mul(fp, x, t0)
+	t4.Mul(fp, result, t2)
+	t6.CyclotomicSquare(fp, t2)
+	t1.Mul(fp, t0, t1)
+	t0.Mul(fp, t3, t1)
+	t6.nSquare(fp, 6)
+	t5.Mul(fp, t5, t6)
+	t5.Mul(fp, t4, t5)
+	t5.nSquare(fp, 7)
+	t4.Mul(fp, t4, t5)
+	t4.nSquare(fp, 8)
+	t4.Mul(fp, t0, t4)
+	t3.Mul(fp, t3, t4)
+	t3.nSquare(fp, 6)
+	t2.Mul(fp, t2, t3)
+	t2.nSquare(fp, 8)
+	t2.Mul(fp, t0, t2)
+	t2.nSquare(fp, 6)
+	t2.Mul(fp, t0, t2)
+	t2.nSquare(fp, 10)
+	t1.Mul(fp, t1, t2)
+	t1.nSquare(fp, 6)
+	t0.Mul(fp, t0, t1)
+	e.Mul(fp, result, t0)
+
+	return e
+}
+
+// MulBy034 multiplication by sparse element (c0,0,0,c3,c4,0)
+func (e *E12) MulBy034(fp *emulated.Field, e1 E12, c0, c3, c4 E2) *E12 {
+	var a, b, d E6
+
+	a.MulByE2(fp, e1.C0, c0)
+	b.MulBy01(fp, e1.C1, c3, c4)
+
+	c0.Add(fp, c0, c3)
+	d.Add(fp, e1.C0, e1.C1)
+	d.MulBy01(fp, d, c0, c4)
+
+	e.C1.Add(fp, a, b).Neg(fp, e.C1).Add(fp, e.C1, d)
+	e.C0.MulByNonResidue(fp, b).Add(fp, e.C0, a)
+
+	return e
+}
+
+// Mul034by034 multiplication of sparse element (c0,0,0,c3,c4,0) by sparse
+// element (d0,0,0,d3,d4,0)
+func (e *E12) Mul034by034(fp *emulated.Field, d0, d3, d4, c0, c3, c4 E2) *E12 {
+	var tmp, x0, x3, x4, x04, x03, x34 E2
+	x0.Mul(fp, c0, d0)
+	x3.Mul(fp, c3, d3)
+	x4.Mul(fp, c4, d4)
+	tmp.Add(fp, c0, c4)
+	x04.Add(fp, d0, d4).Mul(fp, x04, tmp).Sub(fp, x04, x0).Sub(fp, x04, x4)
+	tmp.Add(fp, c0, c3)
+	x03.Add(fp, d0, d3).Mul(fp, x03, tmp).Sub(fp, x03, x0).Sub(fp, x03, x3)
+	tmp.Add(fp, c3, c4)
+	x34.Add(fp, d3, d4).Mul(fp, x34, tmp).Sub(fp, x34, x3).Sub(fp, x34, x4)
+
+	e.C0.B0.MulByNonResidue(fp, x4).Add(fp, e.C0.B0, x0)
+	e.C0.B1 = x3
+	e.C0.B2 = x34
+	e.C1.B0 = x03
+	e.C1.B1 = x04
+	e.C1.B2.SetZero(fp)
+
+	return e
+}
+
+// Select sets e to e1 if b is 1, e2 if b is 0
+func (e *E12) Select(fp *emulated.Field, b frontend.Variable, e1, e2 E12) *E12 {
+	e.C0.Select(fp, b, e1.C0, e2.C0)
+	e.C1.Select(fp, b, e1.C1, e2.C1)
+	return e
+}
+
+// AssertIsEqual constraint self to be equal to other into the given constraint system
+func (e *E12) AssertIsEqual(fp *emulated.Field, other E12) {
+	e.C0.AssertIsEqual(fp, other.C0)
+	e.C1.AssertIsEqual(fp, other.C1)
+}
+
+// Assign a value to self (witness assignment)
+func (e *E12) Assign(a *bn254.GT) {
+	e.C0.B0.Assign(&a.C0.B0.A0, &a.C0.B0.A1)
+	e.C0.B1.Assign(&a.C0.B1.A0, &a.C0.B1.A1)
+	e.C0.B2.Assign(&a.C0.B2.A0, &a.C0.B2.A1)
+	e.C1.B0.Assign(&a.C1.B0.A0, &a.C1.B0.A1)
+	e.C1.B1.Assign(&a.C1.B1.A0, &a.C1.B1.A1)
+	e.C1.B2.Assign(&a.C1.B2.A0, &a.C1.B2.A1)
+}
@@ -0,0 +1,106 @@
+package fields_bn254
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+type e12Circuit struct {
+	A, B, Cyclo                      E12
+	Mul, Square, Inverse             E12
+	Frobenius, FrobeniusSquare       E12
+	CyclotomicSquare, Expt, MulBy034 E12
+	C0, C3, C4                       E2
+}
+
+func (circuit *e12Circuit) Define(api frontend.API) error {
+	fp, err := emulated.NewField(api, emulated.BN254Fp)
+	if err != nil {
+		return err
+	}
+	var res E12
+	res.Mul(fp, circuit.A, circuit.B)
+	res.AssertIsEqual(fp, circuit.Mul)
+	res.Square(fp, circuit.A)
+	res.AssertIsEqual(fp, circuit.Square)
+	res.Inverse(fp, circuit.A)
+	res.AssertIsEqual(fp, circuit.Inverse)
+	res.Frobenius(fp, circuit.A)
+	res.AssertIsEqual(fp, circuit.Frobenius)
+	res.FrobeniusSquare(fp, circuit.A)
+	res.AssertIsEqual(fp, circuit.FrobeniusSquare)
+	res.CyclotomicSquare(fp, circuit.Cyclo)
+	res.AssertIsEqual(fp, circuit.CyclotomicSquare)
+	res.Expt(fp, circuit.Cyclo)
+	res.AssertIsEqual(fp, circuit.Expt)
+	res.MulBy034(fp, circuit.A, circuit.C0, circuit.C3, circuit.C4)
+	res.AssertIsEqual(fp, circuit.MulBy034)
+	return nil
+}
+
+func newE12Circuit() *e12Circuit {
+	return &e12Circuit{
+		A: E12Placeholder(), B: E12Placeholder(), Cyclo: E12Placeholder(),
+		Mul: E12Placeholder(), Square: E12Placeholder(), Inverse: E12Placeholder(),
+		Frobenius: E12Placeholder(), FrobeniusSquare: E12Placeholder(),
+		CyclotomicSquare: E12Placeholder(), Expt: E12Placeholder(), MulBy034: E12Placeholder(),
+		C0: E2Placeholder(), C3: E2Placeholder(), C4: E2Placeholder(),
+	}
+}
+
+func TestE12(t *testing.T) {
+	var a, b, cyclo, c bn254.GT
+	a.SetRandom()
+	b.SetRandom()
+
+	// a^((p⁶-1)(p²+1)) is in the cyclotomic subgroup
+	cyclo.Inverse(&a)
+	c.Conjugate(&a)
+	cyclo.Mul(&cyclo, &c)
+	c.FrobeniusSquare(&cyclo)
+	cyclo.Mul(&cyclo, &c)
+
+	var witness e12Circuit
+	witness.A.Assign(&a)
+	witness.B.Assign(&b)
+	witness.Cyclo.Assign(&cyclo)
+	c.Mul(&a, &b)
+	witness.Mul.Assign(&c)
+	c.Square(&a)
+	witness.Square.Assign(&c)
+	c.Inverse(&a)
+	witness.Inverse.Assign(&c)
+	c.Frobenius(&a)
+	witness.Frobenius.Assign(&c)
+	c.FrobeniusSquare(&a)
+	witness.FrobeniusSquare.Assign(&c)
+	c.CyclotomicSquare(&cyclo)
+	witness.CyclotomicSquare.Assign(&c)
+	c.Expt(&cyclo)
+	witness.Expt.Assign(&c)
+
+	// sparse element (c0,0,0,c3,c4,0), taken from the coefficients of b
+	c0, c3, c4 := b.C0.B0, b.C1.B0, b.C1.B1
+	witness.C0.Assign(&c0.A0, &c0.A1)
+	witness.C3.Assign(&c3.A0, &c3.A1)
+	witness.C4.Assign(&c4.A0, &c4.A1)
+	c = a
+	c.MulBy034(&c0, &c3, &c4)
+	witness.MulBy034.Assign(&c)
+
+	if err := test.IsSolved(newE12Circuit(), &witness, ecc.BN254, backend.UNKNOWN); err != nil {
+		t.Fatal(err)
+	}
+
+	// wrong product
+	witness.Mul.Assign(&a)
+	if err := test.IsSolved(newE12Circuit(), &witness, ecc.BN254, backend.UNKNOWN); err == nil {
+		t.Fatal("expected the verification to fail")
+	}
+}
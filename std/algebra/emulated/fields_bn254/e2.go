@@ -0,0 +1,197 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fields_bn254 implements the arithmetic of the extensions of the base
+// field of BN254 used by the pairing, over an emulated 𝔽p (see std/math/emulated).
+//
+// The tower is the one of gnark-crypto:
+//
+//	𝔽p²[u] = 𝔽p/u²+1
+//	𝔽p⁶[v] = 𝔽p²/v³-9-u
+//	𝔽p¹²[w] = 𝔽p⁶/w²-v
+//
+// The methods take the emulated field 𝔽p as first argument, which must be
+// created with emulated.BN254Fp.
+package fields_bn254
+
+import (
+	"math/big"
+
+	bn254fp "github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// E2 element in a quadratic extension
+type E2 struct {
+	A0, A1 emulated.Element
+}
+
+// E2Placeholder returns an E2 whose limbs are allocated, to be used in a
+// circuit definition
+func E2Placeholder() E2 {
+	return E2{A0: emulated.BN254Fp.Placeholder(), A1: emulated.BN254Fp.Placeholder()}
+}
+
+// SetZero returns a newly allocated element equal to 0
+func (e *E2) SetZero(fp *emulated.Field) *E2 {
+	e.A0 = *fp.Zero()
+	e.A1 = *fp.Zero()
+	return e
+}
+
+// SetOne returns a newly allocated element equal to 1
+func (e *E2) SetOne(fp *emulated.Field) *E2 {
+	e.A0 = *fp.One()
+	e.A1 = *fp.Zero()
+	return e
+}
+
+// Add e2 elmts
+func (e *E2) Add(fp *emulated.Field, e1, e2 E2) *E2 {
+	e.A0 = *fp.Add(&e1.A0, &e2.A0)
+	e.A1 = *fp.Add(&e1.A1, &e2.A1)
+	return e
+}
+
+// Double e2 elmt
+func (e *E2) Double(fp *emulated.Field, e1 E2) *E2 {
+	return e.Add(fp, e1, e1)
+}
+
+// Sub e2 elmts
+func (e *E2) Sub(fp *emulated.Field, e1, e2 E2) *E2 {
+	e.A0 = *fp.Sub(&e1.A0, &e2.A0)
+	e.A1 = *fp.Sub(&e1.A1, &e2.A1)
+	return e
+}
+
+// Neg negates an e2 elmt
+func (e *E2) Neg(fp *emulated.Field, e1 E2) *E2 {
+	e.A0 = *fp.Neg(&e1.A0)
+	e.A1 = *fp.Neg(&e1.A1)
+	return e
+}
+
+// Conjugate conjugation of an e2 elmt
+func (e *E2) Conjugate(fp *emulated.Field, e1 E2) *E2 {
+	e.A0 = e1.A0
+	e.A1 = *fp.Neg(&e1.A1)
+	return e
+}
+
+// Mul e2 elmts: 3 multiplications in 𝔽p (Karatsuba)
+func (e *E2) Mul(fp *emulated.Field, e1, e2 E2) *E2 {
+	a := fp.Mul(fp.Add(&e1.A0, &e1.A1), fp.Add(&e2.A0, &e2.A1))
+	b := fp.Mul(&e1.A0, &e2.A0)
+	c := fp.Mul(&e1.A1, &e2.A1)
+	e.A1 = *fp.Sub(fp.Sub(a, b), c)
+	e.A0 = *fp.Sub(b, c)
+	return e
+}
+
+// Square e2 elt: (a0+a1)(a0-a1) + 2a0a1·u
+func (e *E2) Square(fp *emulated.Field, x E2) *E2 {
+	a := fp.Mul(fp.Add(&x.A0, &x.A1), fp.Sub(&x.A0, &x.A1))
+	b := fp.Mul(&x.A0, &x.A1)
+	e.A0 = *a
+	e.A1 = *fp.Add(b, b)
+	return e
+}
+
+// MulByElement multiplies an e2 elmt by an element of 𝔽p
+func (e *E2) MulByElement(fp *emulated.Field, e1 E2, c emulated.Element) *E2 {
+	e.A0 = *fp.Mul(&e1.A0, &c)
+	e.A1 = *fp.Mul(&e1.A1, &c)
+	return e
+}
+
+// MulByConstant multiplies an e2 elmt by a small non-negative constant
+func (e *E2) MulByConstant(fp *emulated.Field, e1 E2, c *big.Int) *E2 {
+	e.A0 = *fp.MulConst(&e1.A0, c)
+	e.A1 = *fp.MulConst(&e1.A1, c)
+	return e
+}
+
+// mulByFixed multiplies an e2 elmt by a constant of 𝔽p²
+func (e *E2) mulByFixed(fp *emulated.Field, e1 E2, c [2]*big.Int) *E2 {
+	if c[1].Sign() == 0 {
+		cst := fp.Constant(c[0])
+		return e.MulByElement(fp, e1, *cst)
+	}
+	var cst E2
+	cst.A0 = *fp.Constant(c[0])
+	cst.A1 = *fp.Constant(c[1])
+	return e.Mul(fp, e1, cst)
+}
+
+// MulByNonResidue1Power2 multiplies an e2 elmt by (9+u)^(2(p-1)/6)
+func (e *E2) MulByNonResidue1Power2(fp *emulated.Field, e1 E2) *E2 {
+	return e.mulByFixed(fp, e1, frobeniusCoeffs[0][1])
+}
+
+// MulByNonResidue1Power3 multiplies an e2 elmt by (9+u)^(3(p-1)/6)
+func (e *E2) MulByNonResidue1Power3(fp *emulated.Field, e1 E2) *E2 {
+	return e.mulByFixed(fp, e1, frobeniusCoeffs[0][2])
+}
+
+// MulByNonResidue2Power2 multiplies an e2 elmt by (9+u)^(2(p²-1)/6)
+func (e *E2) MulByNonResidue2Power2(fp *emulated.Field, e1 E2) *E2 {
+	return e.mulByFixed(fp, e1, frobeniusCoeffs[1][1])
+}
+
+// MulByNonResidue2Power3 multiplies an e2 elmt by (9+u)^(3(p²-1)/6)
+func (e *E2) MulByNonResidue2Power3(fp *emulated.Field, e1 E2) *E2 {
+	return e.mulByFixed(fp, e1, frobeniusCoeffs[1][2])
+}
+
+// MulByNonResidue multiplies an e2 elmt by the non residue 9+u of 𝔽p⁶:
+// (9a0-a1) + (9a1+a0)·u
+func (e *E2) MulByNonResidue(fp *emulated.Field, e1 E2) *E2 {
+	nine := big.NewInt(9)
+	a0 := fp.Sub(fp.MulConst(&e1.A0, nine), &e1.A1)
+	a1 := fp.Add(fp.MulConst(&e1.A1, nine), &e1.A0)
+	e.A0, e.A1 = *a0, *a1
+	return e
+}
+
+// Inverse e2 elmts: (a0 - a1·u)/(a0²+a1²)
+func (e *E2) Inverse(fp *emulated.Field, e1 E2) *E2 {
+	norm := fp.Add(fp.Square(&e1.A0), fp.Square(&e1.A1))
+	a0 := fp.Div(&e1.A0, norm)
+	a1 := fp.Neg(fp.Div(&e1.A1, norm))
+	e.A0, e.A1 = *a0, *a1
+	return e
+}
+
+// Select sets e to e1 if b is 1, e2 if b is 0
+func (e *E2) Select(fp *emulated.Field, b frontend.Variable, e1, e2 E2) *E2 {
+	e.A0 = *fp.Select(b, &e1.A0, &e2.A0)
+	e.A1 = *fp.Select(b, &e1.A1, &e2.A1)
+	return e
+}
+
+// AssertIsEqual constraint self to be equal to other into the given constraint system
+func (e *E2) AssertIsEqual(fp *emulated.Field, other E2) {
+	fp.AssertIsEqual(&e.A0, &other.A0)
+	fp.AssertIsEqual(&e.A1, &other.A1)
+}
+
+// Assign a value to self (witness assignment): a0 + a1·u
+func (e *E2) Assign(a0, a1 *bn254fp.Element) {
+	e.A0 = emulated.BN254Fp.ValueOf(a0)
+	e.A1 = emulated.BN254Fp.ValueOf(a1)
+}
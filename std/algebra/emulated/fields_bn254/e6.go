@@ -0,0 +1,209 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fields_bn254
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// E6 element in a cubic extension
+type E6 struct {
+	B0, B1, B2 E2
+}
+
+// E6Placeholder returns an E6 whose limbs are allocated, to be used in a
+// circuit definition
+func E6Placeholder() E6 {
+	return E6{B0: E2Placeholder(), B1: E2Placeholder(), B2: E2Placeholder()}
+}
+
+// SetZero returns a newly allocated element equal to 0
+func (e *E6) SetZero(fp *emulated.Field) *E6 {
+	e.B0.SetZero(fp)
+	e.B1.SetZero(fp)
+	e.B2.SetZero(fp)
+	return e
+}
+
+// SetOne returns a newly allocated element equal to 1
+func (e *E6) SetOne(fp *emulated.Field) *E6 {
+	e.B0.SetOne(fp)
+	e.B1.SetZero(fp)
+	e.B2.SetZero(fp)
+	return e
+}
+
+// Add creates a fp6elmt from fp elmts
+func (e *E6) Add(fp *emulated.Field, e1, e2 E6) *E6 {
+	e.B0.Add(fp, e1.B0, e2.B0)
+	e.B1.Add(fp, e1.B1, e2.B1)
+	e.B2.Add(fp, e1.B2, e2.B2)
+	return e
+}
+
+// Double e6 elmt
+func (e *E6) Double(fp *emulated.Field, e1 E6) *E6 {
+	return e.Add(fp, e1, e1)
+}
+
+// Sub creates a fp6elmt from fp elmts
+func (e *E6) Sub(fp *emulated.Field, e1, e2 E6) *E6 {
+	e.B0.Sub(fp, e1.B0, e2.B0)
+	e.B1.Sub(fp, e1.B1, e2.B1)
+	e.B2.Sub(fp, e1.B2, e2.B2)
+	return e
+}
+
+// Neg negates an Fp6 elmt
+func (e *E6) Neg(fp *emulated.Field, e1 E6) *E6 {
+	e.B0.Neg(fp, e1.B0)
+	e.B1.Neg(fp, e1.B1)
+	e.B2.Neg(fp, e1.B2)
+	return e
+}
+
+// Mul multiplies two E6 elmts
+func (e *E6) Mul(fp *emulated.Field, e1, e2 E6) *E6 {
+	var t0, t1, t2, c0, c1, c2, tmp E2
+	t0.Mul(fp, e1.B0, e2.B0)
+	t1.Mul(fp, e1.B1, e2.B1)
+	t2.Mul(fp, e1.B2, e2.B2)
+
+	// c0 = ((b1+b2)(b1'+b2') - t1 - t2)·(9+u) + t0
+	c0.Add(fp, e1.B1, e1.B2)
+	tmp.Add(fp, e2.B1, e2.B2)
+	c0.Mul(fp, c0, tmp).Sub(fp, c0, t1).Sub(fp, c0, t2).MulByNonResidue(fp, c0).Add(fp, c0, t0)
+
+	// c1 = (b0+b1)(b0'+b1') - t0 - t1 + t2·(9+u)
+	c1.Add(fp, e1.B0, e1.B1)
+	tmp.Add(fp, e2.B0, e2.B1)
+	c1.Mul(fp, c1, tmp).Sub(fp, c1, t0).Sub(fp, c1, t1)
+	tmp.MulByNonResidue(fp, t2)
+	c1.Add(fp, c1, tmp)
+
+	// c2 = (b0+b2)(b0'+b2') - t0 - t2 + t1
+	tmp.Add(fp, e1.B0, e1.B2)
+	c2.Add(fp, e2.B0, e2.B2).Mul(fp, c2, tmp).Sub(fp, c2, t0).Sub(fp, c2, t2).Add(fp, c2, t1)
+
+	e.B0, e.B1, e.B2 = c0, c1, c2
+	return e
+}
+
+// Square sets z to the E6 product of x,x, returns e
+func (e *E6) Square(fp *emulated.Field, x E6) *E6 {
+	// Algorithm 16 from https://eprint.iacr.org/2010/354.pdf
+	var c4, c5, c1, c2, c3, c0 E2
+	c4.Mul(fp, x.B0, x.B1).Double(fp, c4)
+	c5.Square(fp, x.B2)
+	c1.MulByNonResidue(fp, c5).Add(fp, c1, c4)
+	c2.Sub(fp, c4, c5)
+	c3.Square(fp, x.B0)
+	c4.Sub(fp, x.B0, x.B1).Add(fp, c4, x.B2)
+	c5.Mul(fp, x.B1, x.B2).Double(fp, c5)
+	c4.Square(fp, c4)
+	c0.MulByNonResidue(fp, c5).Add(fp, c0, c3)
+
+	e.B2.Add(fp, c2, c4).Add(fp, e.B2, c5).Sub(fp, e.B2, c3)
+	e.B0, e.B1 = c0, c1
+	return e
+}
+
+// MulByE2 multiplies an element in E6 by an element in E2
+func (e *E6) MulByE2(fp *emulated.Field, e1 E6, e2 E2) *E6 {
+	e.B0.Mul(fp, e1.B0, e2)
+	e.B1.Mul(fp, e1.B1, e2)
+	e.B2.Mul(fp, e1.B2, e2)
+	return e
+}
+
+// MulByNonResidue multiplies e by the non residue v of 𝔽p¹²:
+// (b0, b1, b2) -> (b2·(9+u), b0, b1)
+func (e *E6) MulByNonResidue(fp *emulated.Field, e1 E6) *E6 {
+	var b0 E2
+	b0.MulByNonResidue(fp, e1.B2)
+	e.B2, e.B1, e.B0 = e1.B1, e1.B0, b0
+	return e
+}
+
+// MulBy01 multiplication by sparse element (c0,c1,0)
+func (e *E6) MulBy01(fp *emulated.Field, e1 E6, c0, c1 E2) *E6 {
+	var a, b, tmp, t0, t1, t2 E2
+
+	a.Mul(fp, e1.B0, c0)
+	b.Mul(fp, e1.B1, c1)
+
+	tmp.Add(fp, e1.B1, e1.B2)
+	t0.Mul(fp, c1, tmp)
+	t0.Sub(fp, t0, b)
+	t0.MulByNonResidue(fp, t0)
+	t0.Add(fp, t0, a)
+
+	tmp.Add(fp, e1.B0, e1.B2)
+	t2.Mul(fp, c0, tmp)
+	t2.Sub(fp, t2, a)
+	t2.Add(fp, t2, b)
+
+	t1.Add(fp, c0, c1)
+	tmp.Add(fp, e1.B0, e1.B1)
+	t1.Mul(fp, t1, tmp)
+	t1.Sub(fp, t1, a)
+	t1.Sub(fp, t1, b)
+
+	e.B0, e.B1, e.B2 = t0, t1, t2
+	return e
+}
+
+// Inverse inverses an Fp6 elmt
+func (e *E6) Inverse(fp *emulated.Field, e1 E6) *E6 {
+	// Algorithm 17 from https://eprint.iacr.org/2010/354.pdf
+	var t0, t1, t2, t3, t4, t5, t6, c0, c1, c2, d1, d2 E2
+	t0.Square(fp, e1.B0)
+	t1.Square(fp, e1.B1)
+	t2.Square(fp, e1.B2)
+	t3.Mul(fp, e1.B0, e1.B1)
+	t4.Mul(fp, e1.B0, e1.B2)
+	t5.Mul(fp, e1.B1, e1.B2)
+	c0.MulByNonResidue(fp, t5).Neg(fp, c0).Add(fp, c0, t0)
+	c1.MulByNonResidue(fp, t2).Sub(fp, c1, t3)
+	c2.Sub(fp, t1, t4)
+	t6.Mul(fp, e1.B0, c0)
+	d1.Mul(fp, e1.B2, c1)
+	d2.Mul(fp, e1.B1, c2)
+	d1.Add(fp, d1, d2).MulByNonResidue(fp, d1)
+	t6.Add(fp, t6, d1)
+	t6.Inverse(fp, t6)
+	e.B0.Mul(fp, c0, t6)
+	e.B1.Mul(fp, c1, t6)
+	e.B2.Mul(fp, c2, t6)
+	return e
+}
+
+// Select sets e to e1 if b is 1, e2 if b is 0
+func (e *E6) Select(fp *emulated.Field, b frontend.Variable, e1, e2 E6) *E6 {
+	e.B0.Select(fp, b, e1.B0, e2.B0)
+	e.B1.Select(fp, b, e1.B1, e2.B1)
+	e.B2.Select(fp, b, e1.B2, e2.B2)
+	return e
+}
+
+// AssertIsEqual constraint self to be equal to other into the given constraint system
+func (e *E6) AssertIsEqual(fp *emulated.Field, other E6) {
+	e.B0.AssertIsEqual(fp, other.B0)
+	e.B1.AssertIsEqual(fp, other.B1)
+	e.B2.AssertIsEqual(fp, other.B2)
+}
@@ -0,0 +1,27 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sw_bn254 implements the arithmetic of the BN254 curves and the
+// optimal ate pairing over an emulated base field (see std/math/emulated), so
+// that they can be used in a circuit defined over any curve.
+//
+// The Miller loop and the final exponentiation follow gnark-crypto step by
+// step, hence the in-circuit pairing returns the same 𝔽p¹² element as the
+// native one. The formulas are incomplete: the inputs must not be the point
+// at infinity, in which case the circuit is not satisfiable.
+//
+// Emulated arithmetic is expensive, a pairing costs millions of constraints.
+package sw_bn254
@@ -0,0 +1,95 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bn254
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// G1Affine point in affine coords
+type G1Affine struct {
+	X, Y emulated.Element
+}
+
+// G1AffinePlaceholder returns a G1Affine whose limbs are allocated, to be used
+// in a circuit definition
+func G1AffinePlaceholder() G1Affine {
+	return G1Affine{X: emulated.BN254Fp.Placeholder(), Y: emulated.BN254Fp.Placeholder()}
+}
+
+// Neg outputs -p
+func (p *G1Affine) Neg(fp *emulated.Field, p1 G1Affine) *G1Affine {
+	p.X = p1.X
+	p.Y = *fp.Neg(&p1.Y)
+	return p
+}
+
+// AddAssign adds p1 to p using the affine formulas with division, and return p
+func (p *G1Affine) AddAssign(fp *emulated.Field, p1 G1Affine) *G1Affine {
+
+	// compute lambda = (p1.y-p.y)/(p1.x-p.x)
+	lambda := fp.Div(fp.Sub(&p1.Y, &p.Y), fp.Sub(&p1.X, &p.X))
+
+	// xr = lambda**2-p.x-p1.x
+	xr := fp.Sub(fp.Square(lambda), fp.Add(&p.X, &p1.X))
+
+	// p.y = lambda(p.x-xr) - p.y
+	p.Y = *fp.Sub(fp.Mul(lambda, fp.Sub(&p.X, xr)), &p.Y)
+
+	//p.x = xr
+	p.X = *xr
+	return p
+}
+
+// Double double a point in affine coords
+func (p *G1Affine) Double(fp *emulated.Field, p1 G1Affine) *G1Affine {
+
+	// compute lambda = (3*p1.x**2)/2*p1.y
+	xx := fp.Square(&p1.X)
+	lambda := fp.Div(fp.Add(fp.Add(xx, xx), xx), fp.Add(&p1.Y, &p1.Y))
+
+	// xr = lambda**2-2*p1.x
+	xr := fp.Sub(fp.Square(lambda), fp.Add(&p1.X, &p1.X))
+
+	// p.y = lambda(p.x-xr) - p.y
+	p.Y = *fp.Sub(fp.Mul(lambda, fp.Sub(&p1.X, xr)), &p1.Y)
+
+	//p.x = xr
+	p.X = *xr
+	return p
+}
+
+// Select sets p1 if b=1, p2 if b=0, and returns it. b must be boolean constrained
+func (p *G1Affine) Select(fp *emulated.Field, b frontend.Variable, p1, p2 G1Affine) *G1Affine {
+	p.X = *fp.Select(b, &p1.X, &p2.X)
+	p.Y = *fp.Select(b, &p1.Y, &p2.Y)
+	return p
+}
+
+// AssertIsEqual constraint self to be equal to other into the given constraint system
+func (p *G1Affine) AssertIsEqual(fp *emulated.Field, other G1Affine) {
+	fp.AssertIsEqual(&p.X, &other.X)
+	fp.AssertIsEqual(&p.Y, &other.Y)
+}
+
+// Assign a value to self (witness assignment)
+func (p *G1Affine) Assign(p1 *bn254.G1Affine) {
+	p.X = emulated.BN254Fp.ValueOf(&p1.X)
+	p.Y = emulated.BN254Fp.ValueOf(&p1.Y)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bn254
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bn254"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// G2Affine point in affine coords on the twist
+type G2Affine struct {
+	X, Y fields_bn254.E2
+}
+
+// G2AffinePlaceholder returns a G2Affine whose limbs are allocated, to be used
+// in a circuit definition
+func G2AffinePlaceholder() G2Affine {
+	return G2Affine{X: fields_bn254.E2Placeholder(), Y: fields_bn254.E2Placeholder()}
+}
+
+// g2Proj point in homogenous projective coords, used in the Miller loop
+type g2Proj struct {
+	x, y, z fields_bn254.E2
+}
+
+// Neg outputs -p
+func (p *G2Affine) Neg(fp *emulated.Field, p1 G2Affine) *G2Affine {
+	p.X = p1.X
+	p.Y.Neg(fp, p1.Y)
+	return p
+}
+
+// AssertIsEqual constraint self to be equal to other into the given constraint system
+func (p *G2Affine) AssertIsEqual(fp *emulated.Field, other G2Affine) {
+	p.X.AssertIsEqual(fp, other.X)
+	p.Y.AssertIsEqual(fp, other.Y)
+}
+
+// Assign a value to self (witness assignment)
+func (p *G2Affine) Assign(p1 *bn254.G2Affine) {
+	p.X.Assign(&p1.X.A0, &p1.X.A1)
+	p.Y.Assign(&p1.Y.A0, &p1.Y.A1)
+}
+
+// fromAffine sets p to the projective representation of p1
+func (p *g2Proj) fromAffine(fp *emulated.Field, p1 G2Affine) *g2Proj {
+	p.x = p1.X
+	p.y = p1.Y
+	p.z.SetOne(fp)
+	return p
+}
@@ -0,0 +1,274 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bn254
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fp "github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bn254"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// GT target group of the pairing
+type GT = fields_bn254.E12
+
+// lineEvaluation represents a sparse Fp12 Elmt (result of the line evaluation)
+type lineEvaluation struct {
+	r0, r1, r2 fields_bn254.E2
+}
+
+// loopCounter NAF decomposition of 6x+2, x being the seed of the curve
+var loopCounter [66]int8
+
+// bTwistCurveCoeff b coeff of the twist 3/(9+u)
+var bTwistCurveCoeff [2]*big.Int
+
+// half (p+1)/2, the inverse of 2 in 𝔽p
+var half *big.Int
+
+func init() {
+	optimalAteLoop, _ := new(big.Int).SetString("29793968203157093288", 10)
+	ecc.NafDecomposition(optimalAteLoop, loopCounter[:])
+
+	var q bn254.G2Affine
+	twist := q.X
+	twist.A0.SetUint64(9)
+	twist.A1.SetOne()
+	var three bn254fp.Element
+	three.SetUint64(3)
+	b := q.X
+	b.Inverse(&twist).MulByElement(&b, &three)
+	bTwistCurveCoeff = [2]*big.Int{new(big.Int), new(big.Int)}
+	b.A0.ToBigIntRegular(bTwistCurveCoeff[0])
+	b.A1.ToBigIntRegular(bTwistCurveCoeff[1])
+
+	half = new(big.Int).Add(bn254fp.Modulus(), big.NewInt(1))
+	half.Rsh(half, 1)
+}
+
+// MillerLoop computes the product of n miller loops (n can be 1)
+func MillerLoop(fp *emulated.Field, P []G1Affine, Q []G2Affine) (GT, error) {
+	// check input size match
+	n := len(P)
+	if n == 0 || n != len(Q) {
+		return GT{}, errors.New("invalid inputs sizes")
+	}
+
+	var bTwist fields_bn254.E2
+	bTwist.A0 = *fp.Constant(bTwistCurveCoeff[0])
+	bTwist.A1 = *fp.Constant(bTwistCurveCoeff[1])
+	h := fp.Constant(half)
+
+	qProj := make([]g2Proj, n)
+	qNeg := make([]G2Affine, n)
+	for k := 0; k < n; k++ {
+		qProj[k].fromAffine(fp, Q[k])
+		qNeg[k].Neg(fp, Q[k])
+	}
+
+	var result GT
+	result.SetOne(fp)
+
+	var l lineEvaluation
+	for i := len(loopCounter) - 2; i >= 0; i-- {
+		result.Square(fp, result)
+
+		for k := 0; k < n; k++ {
+			qProj[k].doubleStep(fp, &l, bTwist, *h)
+			l.evaluate(fp, P[k])
+			result.MulBy034(fp, result, l.r0, l.r1, l.r2)
+
+			if loopCounter[i] == 1 {
+				qProj[k].addMixedStep(fp, &l, Q[k])
+				l.evaluate(fp, P[k])
+				result.MulBy034(fp, result, l.r0, l.r1, l.r2)
+			} else if loopCounter[i] == -1 {
+				qProj[k].addMixedStep(fp, &l, qNeg[k])
+				l.evaluate(fp, P[k])
+				result.MulBy034(fp, result, l.r0, l.r1, l.r2)
+			}
+		}
+	}
+
+	var Q1, Q2 G2Affine
+	var l0 lineEvaluation
+	var tmp GT
+	// cf https://eprint.iacr.org/2010/354.pdf for instance for optimal Ate Pairing
+	for k := 0; k < n; k++ {
+		//Q1 = Frob(Q)
+		Q1.X.Conjugate(fp, Q[k].X).MulByNonResidue1Power2(fp, Q1.X)
+		Q1.Y.Conjugate(fp, Q[k].Y).MulByNonResidue1Power3(fp, Q1.Y)
+
+		// Q2 = -Frob2(Q)
+		Q2.X.MulByNonResidue2Power2(fp, Q[k].X)
+		Q2.Y.MulByNonResidue2Power3(fp, Q[k].Y).Neg(fp, Q2.Y)
+
+		qProj[k].addMixedStep(fp, &l0, Q1)
+		l0.evaluate(fp, P[k])
+
+		qProj[k].addMixedStep(fp, &l, Q2)
+		l.evaluate(fp, P[k])
+		tmp.Mul034by034(fp, l.r0, l.r1, l.r2, l0.r0, l0.r1, l0.r2)
+		result.Mul(fp, result, tmp)
+	}
+
+	return result, nil
+}
+
+// FinalExponentiation computes the final expo x**(p**6-1)(p**2+1)(p**4 - p**2 +1)/r
+func FinalExponentiation(fp *emulated.Field, e1 GT) GT {
+	var result GT
+
+	// https://eprint.iacr.org/2008/490.pdf
+	var mt [4]GT // mt[i] is m^(t^i)
+
+	// easy part
+	var temp GT
+	temp.Conjugate(fp, e1)
+	mt[0].Inverse(fp, e1)
+	temp.Mul(fp, temp, mt[0])
+	mt[0].FrobeniusSquare(fp, temp).Mul(fp, mt[0], temp)
+
+	// hard part
+	mt[1].Expt(fp, mt[0])
+	mt[2].Expt(fp, mt[1])
+	mt[3].Expt(fp, mt[2])
+
+	var y [7]GT
+
+	y[1].Conjugate(fp, mt[0])
+	y[4] = mt[1]
+	y[5].Conjugate(fp, mt[2])
+	y[6] = mt[3]
+
+	mt[0].Frobenius(fp, mt[0])
+	mt[1].Frobenius(fp, mt[1])
+	mt[2].Frobenius(fp, mt[2])
+	mt[3].Frobenius(fp, mt[3])
+
+	y[0] = mt[0]
+	y[3].Conjugate(fp, mt[1])
+	y[4].Mul(fp, y[4], mt[2]).Conjugate(fp, y[4])
+	y[6].Mul(fp, y[6], mt[3]).Conjugate(fp, y[6])
+
+	mt[0].Frobenius(fp, mt[0])
+	mt[2].Frobenius(fp, mt[2])
+
+	y[0].Mul(fp, y[0], mt[0])
+	y[2] = mt[2]
+
+	mt[0].Frobenius(fp, mt[0])
+
+	y[0].Mul(fp, y[0], mt[0])
+
+	// compute addition chain
+	mt[0].CyclotomicSquare(fp, y[6])
+	mt[0].Mul(fp, mt[0], y[4])
+	mt[0].Mul(fp, mt[0], y[5])
+	mt[1].Mul(fp, y[3], y[5])
+	mt[1].Mul(fp, mt[1], mt[0])
+	mt[0].Mul(fp, mt[0], y[2])
+	mt[1].CyclotomicSquare(fp, mt[1])
+	mt[1].Mul(fp, mt[1], mt[0])
+	mt[1].CyclotomicSquare(fp, mt[1])
+	mt[0].Mul(fp, mt[1], y[1])
+	mt[1].Mul(fp, mt[1], y[0])
+	mt[0].CyclotomicSquare(fp, mt[0])
+	result.Mul(fp, mt[0], mt[1])
+
+	return result
+}
+
+// Pair calculates the reduced pairing for a set of points
+func Pair(fp *emulated.Field, P []G1Affine, Q []G2Affine) (GT, error) {
+	f, err := MillerLoop(fp, P, Q)
+	if err != nil {
+		return GT{}, err
+	}
+	return FinalExponentiation(fp, f), nil
+}
+
+// evaluate evaluates the line at P: (r0·P.y, r1·P.x, r2)
+func (l *lineEvaluation) evaluate(fp *emulated.Field, P G1Affine) {
+	l.r0.MulByElement(fp, l.r0, P.Y)
+	l.r1.MulByElement(fp, l.r1, P.X)
+}
+
+// doubleStep doubles a point in Homogenous projective coordinates, and evaluates the line in Miller loop
+// https://eprint.iacr.org/2013/722.pdf (Section 4.3)
+func (p *g2Proj) doubleStep(fp *emulated.Field, evaluations *lineEvaluation, bTwist fields_bn254.E2, half emulated.Element) {
+	var t1, A, B, C, D, E, EE, F, G, H, I, J, K fields_bn254.E2
+	A.Mul(fp, p.x, p.y).MulByElement(fp, A, half)
+	B.Square(fp, p.y)
+	C.Square(fp, p.z)
+	D.Double(fp, C).Add(fp, D, C)
+	E.Mul(fp, D, bTwist)
+	F.Double(fp, E).Add(fp, F, E)
+	G.Add(fp, B, F).MulByElement(fp, G, half)
+	H.Add(fp, p.y, p.z).Square(fp, H)
+	t1.Add(fp, B, C)
+	H.Sub(fp, H, t1)
+	I.Sub(fp, E, B)
+	J.Square(fp, p.x)
+	EE.Square(fp, E)
+	K.Double(fp, EE).Add(fp, K, EE)
+
+	// X, Y, Z
+	p.x.Sub(fp, B, F).Mul(fp, p.x, A)
+	p.y.Square(fp, G).Sub(fp, p.y, K)
+	p.z.Mul(fp, B, H)
+
+	// Line evaluation
+	evaluations.r0.Neg(fp, H)
+	evaluations.r1.Double(fp, J).Add(fp, evaluations.r1, J)
+	evaluations.r2 = I
+}
+
+// addMixedStep point addition in Mixed Homogenous projective and Affine coordinates
+// https://eprint.iacr.org/2013/722.pdf (Section 4.3)
+func (p *g2Proj) addMixedStep(fp *emulated.Field, evaluations *lineEvaluation, a G2Affine) {
+	var Y2Z1, X2Z1, O, L, C, D, E, F, G, H, t0, t1, t2, J fields_bn254.E2
+	Y2Z1.Mul(fp, a.Y, p.z)
+	O.Sub(fp, p.y, Y2Z1)
+	X2Z1.Mul(fp, a.X, p.z)
+	L.Sub(fp, p.x, X2Z1)
+	C.Square(fp, O)
+	D.Square(fp, L)
+	E.Mul(fp, L, D)
+	F.Mul(fp, p.z, C)
+	G.Mul(fp, p.x, D)
+	t0.Double(fp, G)
+	H.Add(fp, E, F).Sub(fp, H, t0)
+	t1.Mul(fp, p.y, E)
+
+	// X, Y, Z
+	p.x.Mul(fp, L, H)
+	p.y.Sub(fp, G, H).Mul(fp, p.y, O).Sub(fp, p.y, t1)
+	p.z.Mul(fp, E, p.z)
+
+	t2.Mul(fp, L, a.Y)
+	J.Mul(fp, a.X, O).Sub(fp, J, t2)
+
+	// Line evaluation
+	evaluations.r0 = L
+	evaluations.r1.Neg(fp, O)
+	evaluations.r2 = J
+}
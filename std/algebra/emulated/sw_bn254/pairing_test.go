@@ -0,0 +1,109 @@
+package sw_bn254
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bn254"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+type g1Circuit struct {
+	A, B, Sum, Double G1Affine
+}
+
+func (circuit *g1Circuit) Define(api frontend.API) error {
+	fp, err := emulated.NewField(api, emulated.BN254Fp)
+	if err != nil {
+		return err
+	}
+	res := circuit.A
+	res.AddAssign(fp, circuit.B)
+	res.AssertIsEqual(fp, circuit.Sum)
+	res.Double(fp, circuit.A)
+	res.AssertIsEqual(fp, circuit.Double)
+	return nil
+}
+
+func TestG1(t *testing.T) {
+	_, _, g1, _ := bn254.Generators()
+	var a, b, c bn254.G1Affine
+	a.ScalarMultiplication(&g1, big.NewInt(3))
+	b.ScalarMultiplication(&g1, big.NewInt(5))
+
+	var witness g1Circuit
+	witness.A.Assign(&a)
+	witness.B.Assign(&b)
+	c.Add(&a, &b)
+	witness.Sum.Assign(&c)
+	c.ScalarMultiplication(&g1, big.NewInt(6))
+	witness.Double.Assign(&c)
+
+	circuit := g1Circuit{A: G1AffinePlaceholder(), B: G1AffinePlaceholder(), Sum: G1AffinePlaceholder(), Double: G1AffinePlaceholder()}
+	if err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type pairingCircuit struct {
+	P          [2]G1Affine
+	Q          [2]G2Affine
+	MillerLoop GT
+	Pairing    GT
+}
+
+func (circuit *pairingCircuit) Define(api frontend.API) error {
+	fp, err := emulated.NewField(api, emulated.BN254Fp)
+	if err != nil {
+		return err
+	}
+	ml, err := MillerLoop(fp, circuit.P[:], circuit.Q[:])
+	if err != nil {
+		return err
+	}
+	ml.AssertIsEqual(fp, circuit.MillerLoop)
+	res := FinalExponentiation(fp, ml)
+	res.AssertIsEqual(fp, circuit.Pairing)
+	return nil
+}
+
+func TestPairing(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping emulated pairing in short mode")
+	}
+	_, _, g1, g2 := bn254.Generators()
+	var p [2]bn254.G1Affine
+	var q [2]bn254.G2Affine
+	p[0].ScalarMultiplication(&g1, big.NewInt(7))
+	p[1].ScalarMultiplication(&g1, big.NewInt(11))
+	q[0].ScalarMultiplication(&g2, big.NewInt(13))
+	q[1].ScalarMultiplication(&g2, big.NewInt(17))
+
+	ml, err := bn254.MillerLoop(p[:], q[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairing := bn254.FinalExponentiation(&ml)
+
+	var witness pairingCircuit
+	for i := 0; i < 2; i++ {
+		witness.P[i].Assign(&p[i])
+		witness.Q[i].Assign(&q[i])
+	}
+	witness.MillerLoop.Assign(&ml)
+	witness.Pairing.Assign(&pairing)
+
+	circuit := pairingCircuit{MillerLoop: fields_bn254.E12Placeholder(), Pairing: fields_bn254.E12Placeholder()}
+	for i := 0; i < 2; i++ {
+		circuit.P[i] = G1AffinePlaceholder()
+		circuit.Q[i] = G2AffinePlaceholder()
+	}
+	if err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN); err != nil {
+		t.Fatal(err)
+	}
+}
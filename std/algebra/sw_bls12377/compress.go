@@ -0,0 +1,208 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12377
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields_bls12377"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// The compressed encodings are the ones of gnark-crypto (G1Affine.Bytes and
+// G2Affine.Bytes): X in big endian, the three most significant bits of the
+// first byte being 0b100, or 0b101 if Y is lexicographically largest. The point
+// at infinity (0b110) has no affine representation in the circuit and is not
+// supported.
+
+func init() {
+	hint.Register(decompressG1Hint)
+	hint.Register(decompressG2Hint)
+}
+
+// Compress returns the compressed encoding of p (fp.Bytes bytes)
+func (p *G1Affine) Compress(api frontend.API) []frontend.Variable {
+	return fpToBytes(api, bits.ToCanonicalBinary(api, p.X), bits.IsLexicographicallyLargest(api, p.Y))
+}
+
+// Decompress sets p to the point encoded by buf (see Compress) and returns p.
+// It returns an error if buf doesn't have fp.Bytes elements, and the solving
+// fails if buf is not the canonical encoding of a point of the curve. The point
+// is not checked to be in G1.
+func (p *G1Affine) Decompress(api frontend.API, buf []frontend.Variable) (*G1Affine, error) {
+	if len(buf) != fp.Bytes {
+		return nil, errors.New("invalid size of compressed point")
+	}
+	x, sign := fpFromBytes(api, buf, true)
+	y, err := api.Compiler().NewHint(decompressG1Hint, 1, x, sign)
+	if err != nil {
+		return nil, err
+	}
+
+	// y² = x³ + 1
+	api.AssertIsEqual(api.Mul(y[0], y[0]), api.Add(api.Mul(x, x, x), 1))
+	api.AssertIsEqual(bits.IsLexicographicallyLargest(api, y[0]), sign)
+
+	p.X = x
+	p.Y = y[0]
+	return p, nil
+}
+
+// Compress returns the compressed encoding of p (2·fp.Bytes bytes, X.A1 then
+// X.A0)
+func (p *G2Affine) Compress(api frontend.API) []frontend.Variable {
+	res := fpToBytes(api, bits.ToCanonicalBinary(api, p.X.A1), isLexicographicallyLargestE2(api, p.Y))
+	return append(res, fpToBytes(api, bits.ToCanonicalBinary(api, p.X.A0), nil)...)
+}
+
+// Decompress sets p to the point encoded by buf (see Compress) and returns p.
+// It returns an error if buf doesn't have 2·fp.Bytes elements, and the solving
+// fails if buf is not the canonical encoding of a point of the twist. The point
+// is not checked to be in G2.
+func (p *G2Affine) Decompress(api frontend.API, buf []frontend.Variable) (*G2Affine, error) {
+	if len(buf) != 2*fp.Bytes {
+		return nil, errors.New("invalid size of compressed point")
+	}
+	var x, y fields_bls12377.E2
+	var sign frontend.Variable
+	x.A1, sign = fpFromBytes(api, buf[:fp.Bytes], true)
+	x.A0, _ = fpFromBytes(api, buf[fp.Bytes:], false)
+	res, err := api.Compiler().NewHint(decompressG2Hint, 2, x.A0, x.A1, sign)
+	if err != nil {
+		return nil, err
+	}
+	y.A0, y.A1 = res[0], res[1]
+
+	// y² = x³ + b'
+	var lhs, rhs, b fields_bls12377.E2
+	bTwist := bTwistCurveCoeff()
+	b.Assign(&bTwist)
+	lhs.Square(api, y)
+	rhs.Square(api, x).Mul(api, rhs, x).Add(api, rhs, b)
+	lhs.AssertIsEqual(api, rhs)
+	api.AssertIsEqual(isLexicographicallyLargestE2(api, y), sign)
+
+	p.X = x
+	p.Y = y
+	return p, nil
+}
+
+// fpToBytes returns the big endian bytes of the element of Fp with the little
+// endian bits b. If sign is not nil, the flags of a compressed point are set in
+// the most significant bits.
+func fpToBytes(api frontend.API, b []frontend.Variable, sign frontend.Variable) []frontend.Variable {
+	le := make([]frontend.Variable, 8*fp.Bytes)
+	for i := range le {
+		if i < len(b) {
+			le[i] = b[i]
+		} else {
+			le[i] = 0
+		}
+	}
+	if sign != nil {
+		le[len(le)-1] = 1
+		le[len(le)-3] = sign
+	}
+
+	res := make([]frontend.Variable, fp.Bytes)
+	for i := range res {
+		res[fp.Bytes-1-i] = api.FromBinary(le[8*i : 8*i+8]...)
+	}
+	return res
+}
+
+// fpFromBytes returns the element of Fp encoded by the big endian bytes buf,
+// checked to be canonical. If flags is true, the three most significant bits
+// are the flags of a compressed point, and the sign of Y is returned;
+// otherwise they must be 0.
+func fpFromBytes(api frontend.API, buf []frontend.Variable, flags bool) (frontend.Variable, frontend.Variable) {
+	le := make([]frontend.Variable, 0, 8*fp.Bytes)
+	for i := len(buf) - 1; i >= 0; i-- {
+		le = append(le, api.ToBinary(buf[i], 8)...)
+	}
+
+	var sign frontend.Variable
+	if flags {
+		api.AssertIsEqual(le[len(le)-1], 1)
+		api.AssertIsEqual(le[len(le)-2], 0)
+		sign = le[len(le)-3]
+		le = le[:len(le)-3]
+	}
+
+	modulus := api.Compiler().Curve().Info().Fr.Modulus()
+	bits.AssertBitsLessOrEqual(api, le, new(big.Int).Sub(modulus, big.NewInt(1)))
+	return bits.FromBinary(api, le, bits.WithUnconstrainedInputs()), sign
+}
+
+// isLexicographicallyLargestE2 compares A1, or A0 if A1 = 0
+func isLexicographicallyLargestE2(api frontend.API, x fields_bls12377.E2) frontend.Variable {
+	return api.Select(api.IsZero(x.A1), bits.IsLexicographicallyLargest(api, x.A0), bits.IsLexicographicallyLargest(api, x.A1))
+}
+
+// bTwistCurveCoeff returns the coefficient b' = 1/u of the twist
+func bTwistCurveCoeff() bls12377.E2 {
+	var res bls12377.E2
+	res.A1.SetOne()
+	res.Inverse(&res)
+	return res
+}
+
+// decompressG1Hint returns the y coordinate of the point of G1 with the x
+// coordinate and the sign given as inputs
+func decompressG1Hint(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	var x, y fp.Element
+	x.SetBigInt(inputs[0])
+
+	// y² = x³ + 1
+	y.Square(&x).Mul(&y, &x).Add(&y, new(fp.Element).SetOne())
+	if y.Sqrt(&y) == nil {
+		return errors.New("no point with this x coordinate")
+	}
+	if y.LexicographicallyLargest() != (inputs[1].Sign() != 0) {
+		y.Neg(&y)
+	}
+	y.ToBigIntRegular(results[0])
+	return nil
+}
+
+// decompressG2Hint returns the y coordinate of the point of the twist with the
+// x coordinate and the sign given as inputs
+func decompressG2Hint(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	var x, y bls12377.E2
+	x.A0.SetBigInt(inputs[0])
+	x.A1.SetBigInt(inputs[1])
+
+	// y² = x³ + b'
+	b := bTwistCurveCoeff()
+	y.Square(&x).Mul(&y, &x).Add(&y, &b)
+	if y.Legendre() == -1 {
+		return errors.New("no point with this x coordinate")
+	}
+	y.Sqrt(&y)
+	if y.LexicographicallyLargest() != (inputs[2].Sign() != 0) {
+		y.Neg(&y)
+	}
+	y.A0.ToBigIntRegular(results[0])
+	y.A1.ToBigIntRegular(results[1])
+	return nil
+}
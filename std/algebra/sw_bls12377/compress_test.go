@@ -0,0 +1,159 @@
+package sw_bls12377
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+)
+
+func assignBytes(buf []byte) []frontend.Variable {
+	res := make([]frontend.Variable, len(buf))
+	for i := range buf {
+		res[i] = buf[i]
+	}
+	return res
+}
+
+type g1Compress struct {
+	P   G1Affine
+	Buf [bls12377.SizeOfG1AffineCompressed]frontend.Variable
+}
+
+func (circuit *g1Compress) Define(api frontend.API) error {
+	buf := circuit.P.Compress(api)
+	for i := range buf {
+		api.AssertIsEqual(buf[i], circuit.Buf[i])
+	}
+
+	var p G1Affine
+	if _, err := p.Decompress(api, circuit.Buf[:]); err != nil {
+		return err
+	}
+	p.AssertIsEqual(api, circuit.P)
+	return nil
+}
+
+func TestCompressG1(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var circuit g1Compress
+	pJac := randomPointG1()
+	var p bls12377.G1Affine
+	p.FromJacobian(&pJac)
+
+	for _, q := range []bls12377.G1Affine{p, *new(bls12377.G1Affine).Neg(&p)} {
+		var witness g1Compress
+		buf := q.Bytes()
+		witness.P.Assign(&q)
+		copy(witness.Buf[:], assignBytes(buf[:]))
+		assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_761))
+
+		// wrong sign
+		buf[0] ^= 0b001 << 5
+		copy(witness.Buf[:], assignBytes(buf[:]))
+		assert.SolvingFailed(&circuit, &witness, test.WithCurves(ecc.BW6_761))
+
+		// infinity flag
+		buf[0] ^= 0b011 << 5
+		copy(witness.Buf[:], assignBytes(buf[:]))
+		assert.SolvingFailed(&circuit, &witness, test.WithCurves(ecc.BW6_761))
+	}
+}
+
+type g1Decompress struct {
+	P   G1Affine
+	Buf []frontend.Variable
+}
+
+func (circuit *g1Decompress) Define(api frontend.API) error {
+	var p G1Affine
+	if _, err := p.Decompress(api, circuit.Buf); err != nil {
+		return err
+	}
+	p.AssertIsEqual(api, circuit.P)
+	return nil
+}
+
+func TestDecompressG1NonCanonical(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	pJac := randomPointG1()
+	var p bls12377.G1Affine
+	p.FromJacobian(&pJac)
+
+	circuit := g1Decompress{Buf: make([]frontend.Variable, fp.Bytes)}
+	witness := g1Decompress{}
+	witness.P.Assign(&p)
+	buf := p.Bytes()
+	witness.Buf = assignBytes(buf[:])
+	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_761))
+
+	// X + p encodes the same X, but is not canonical
+	var x big.Int
+	p.X.ToBigIntRegular(&x)
+	x.Add(&x, fp.Modulus())
+	nonCanonical := make([]byte, fp.Bytes)
+	x.FillBytes(nonCanonical)
+	nonCanonical[0] |= buf[0] & (0b111 << 5)
+	witness.Buf = assignBytes(nonCanonical)
+	assert.SolvingFailed(&circuit, &witness, test.WithCurves(ecc.BW6_761))
+
+	// the flags must be 0b100 or 0b101
+	for _, flags := range []byte{0b000, 0b001, 0b010, 0b011, 0b110, 0b111} {
+		badFlags := buf
+		badFlags[0] = badFlags[0]&^(0b111<<5) | flags<<5
+		witness.Buf = assignBytes(badFlags[:])
+		assert.SolvingFailed(&circuit, &witness, test.WithCurves(ecc.BW6_761))
+	}
+
+	// wrong size
+	_, err := frontend.Compile(ecc.BW6_761, r1cs.NewBuilder, &g1Decompress{Buf: make([]frontend.Variable, fp.Bytes-1)})
+	assert.Error(err)
+}
+
+type g2Compress struct {
+	P   G2Affine
+	Buf [bls12377.SizeOfG2AffineCompressed]frontend.Variable
+}
+
+func (circuit *g2Compress) Define(api frontend.API) error {
+	buf := circuit.P.Compress(api)
+	for i := range buf {
+		api.AssertIsEqual(buf[i], circuit.Buf[i])
+	}
+
+	var p G2Affine
+	if _, err := p.Decompress(api, circuit.Buf[:]); err != nil {
+		return err
+	}
+	p.AssertIsEqual(api, circuit.P)
+	return nil
+}
+
+func TestCompressG2(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var circuit g2Compress
+	pJac := randomPointG2()
+	var p bls12377.G2Affine
+	p.FromJacobian(&pJac)
+
+	for _, q := range []bls12377.G2Affine{p, *new(bls12377.G2Affine).Neg(&p)} {
+		var witness g2Compress
+		buf := q.Bytes()
+		witness.P.Assign(&q)
+		copy(witness.Buf[:], assignBytes(buf[:]))
+		assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_761))
+
+		// wrong sign
+		buf[0] ^= 0b001 << 5
+		copy(witness.Buf[:], assignBytes(buf[:]))
+		assert.SolvingFailed(&circuit, &witness, test.WithCurves(ecc.BW6_761))
+	}
+}
@@ -451,3 +451,30 @@ func (p *G1Affine) DoubleAndAdd(api frontend.API, p1, p2 *G1Affine) *G1Affine {
 
 	return p
 }
+
+// ClearCofactor maps p1, a point of the curve, to the r-torsion G1, assigns
+// the result to p and returns it. The result is the same as gnark-crypto's,
+// [1-x]p1 where x is the seed of the curve (cf https://eprint.iacr.org/2019/403.pdf, 5).
+func (p *G1Affine) ClearCofactor(api frontend.API, p1 G1Affine) *G1Affine {
+	var xP G1Affine
+	xP.mulBySeed(api, p1)
+	xP.Neg(api, xP)
+	xP.AddAssign(api, p1)
+	p.X, p.Y = xP.X, xP.Y
+	return p
+}
+
+// mulBySeed sets p to [x]p1, where x is the seed of the curve, and returns
+// it. The GLV scalar multiplication can't be used, as p1 may be out of G1.
+func (p *G1Affine) mulBySeed(api frontend.API, p1 G1Affine) *G1Affine {
+	acc := p1
+	for i := xGen.BitLen() - 2; i >= 0; i-- {
+		if xGen.Bit(i) == 1 {
+			acc.DoubleAndAdd(api, &acc, &p1)
+		} else {
+			acc.Double(api, acc)
+		}
+	}
+	p.X, p.Y = acc.X, acc.Y
+	return p
+}
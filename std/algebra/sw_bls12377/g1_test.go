@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
@@ -368,6 +369,44 @@ func TestScalarMulG1(t *testing.T) {
 	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_761))
 }
 
+// -------------------------------------------------------------------------------------------------
+// Clear cofactor
+
+type g1ClearCofactor struct {
+	A G1Affine
+	C G1Affine `gnark:",public"`
+}
+
+func (circuit *g1ClearCofactor) Define(api frontend.API) error {
+	var expected G1Affine
+	expected.ClearCofactor(api, circuit.A)
+	expected.AssertIsEqual(api, circuit.C)
+	return nil
+}
+
+func TestClearCofactorG1(t *testing.T) {
+	// sample a random point of the curve, out of G1 w.h.p.
+	var a, c bls12377.G1Affine
+	var one fp.Element
+	one.SetOne()
+	for {
+		a.X.SetRandom()
+		a.Y.Square(&a.X).Mul(&a.Y, &a.X).Add(&a.Y, &one)
+		if a.Y.Legendre() == 1 {
+			a.Y.Sqrt(&a.Y)
+			break
+		}
+	}
+	c.ClearCofactor(&a)
+
+	var circuit, witness g1ClearCofactor
+	witness.A.Assign(&a)
+	witness.C.Assign(&c)
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_761))
+}
+
 func randomPointG1() bls12377.G1Jac {
 
 	p1, _, _, _ := bls12377.Generators()
@@ -471,3 +471,70 @@ func (p *G2Affine) DoubleAndAdd(api frontend.API, p1, p2 *G2Affine) *G2Affine {
 
 	return p
 }
+
+// ClearCofactor maps p1, a point of the twist, to the r-torsion G2, assigns
+// the result to p and returns it. The result is the same as gnark-crypto's,
+// [x²-x-1]p1 + ψ([x-1]p1) + ψ²([2]p1) (cf https://eprint.iacr.org/2017/419.pdf, 4.1).
+func (p *G2Affine) ClearCofactor(api frontend.API, p1 G2Affine) *G2Affine {
+	var xP, xxP, res, t G2Affine
+	xP.mulBySeed(api, p1)
+	xxP.mulBySeed(api, xP)
+
+	// [x²-x-1]p1
+	res = xxP
+	t.Neg(api, xP)
+	res.AddAssign(api, t)
+	t.Neg(api, p1)
+	res.AddAssign(api, t)
+
+	// ψ([x-1]p1)
+	t.Neg(api, p1)
+	t.AddAssign(api, xP)
+	t.psi(api, t)
+	res.AddAssign(api, t)
+
+	// ψ²([2]p1) = (ω·x, -y) where ω is a primitive third root of one in Fp
+	t.Double(api, p1)
+	t.X.MulByFp(api, t.X, thirdRootOneG1)
+	t.Y.Neg(api, t.Y)
+	res.AddAssign(api, t)
+
+	p.X, p.Y = res.X, res.Y
+	return p
+}
+
+// psi sets p to ψ(p1) = (x̄·u, ȳ·v), the untwist-Frobenius-twist endomorphism,
+// and returns it
+func (p *G2Affine) psi(api frontend.API, p1 G2Affine) *G2Affine {
+	p.X.Conjugate(api, p1.X).MulByFp(api, p.X, endoU)
+	p.Y.Conjugate(api, p1.Y).MulByFp(api, p.Y, endoV)
+	return p
+}
+
+// mulBySeed sets p to [x]p1, where x is the seed of the curve, and returns
+// it. The GLV scalar multiplication can't be used, as p1 may be out of G2.
+func (p *G2Affine) mulBySeed(api frontend.API, p1 G2Affine) *G2Affine {
+	acc := p1
+	for i := xGen.BitLen() - 2; i >= 0; i-- {
+		if xGen.Bit(i) == 1 {
+			acc.DoubleAndAdd(api, &acc, &p1)
+		} else {
+			acc.Double(api, acc)
+		}
+	}
+	p.X, p.Y = acc.X, acc.Y
+	return p
+}
+
+var (
+	// xGen is the seed of BLS12-377
+	xGen, _ = new(big.Int).SetString("9586122913090633729", 10)
+
+	// thirdRootOneG1 is the primitive third root of one in Fp used by
+	// gnark-crypto to compute ψ²
+	thirdRootOneG1, _ = new(big.Int).SetString("80949648264912719408558363140637477264845294720710499478137287262712535938301461879813459410945", 10)
+
+	// endoU and endoV are the (Fp) coefficients of ψ
+	endoU, _ = new(big.Int).SetString("80949648264912719408558363140637477264845294720710499478137287262712535938301461879813459410946", 10)
+	endoV, _ = new(big.Int).SetString("216465761340224619389371505802605247630151569547285782856803747159100223055385581585702401816380679166954762214499", 10)
+)
@@ -374,6 +374,52 @@ func TestScalarMulG2(t *testing.T) {
 	assert := test.NewAssert(t)
 	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_761))
 }
+
+// -------------------------------------------------------------------------------------------------
+// Clear cofactor
+
+type g2ClearCofactor struct {
+	A G2Affine
+	C G2Affine `gnark:",public"`
+}
+
+func (circuit *g2ClearCofactor) Define(api frontend.API) error {
+	var expected G2Affine
+	expected.ClearCofactor(api, circuit.A)
+	expected.AssertIsEqual(api, circuit.C)
+	return nil
+}
+
+func TestClearCofactorG2(t *testing.T) {
+	// b' = y²-x³ for the generator of G2
+	_, _, _, g2 := bls12377.Generators()
+	var b, x3 bls12377.E2
+	b.Square(&g2.Y)
+	x3.Square(&g2.X).Mul(&x3, &g2.X)
+	b.Sub(&b, &x3)
+
+	// sample a random point of the twist, out of G2 w.h.p.
+	var a, c bls12377.G2Affine
+	for {
+		if _, err := a.X.SetRandom(); err != nil {
+			t.Fatal(err)
+		}
+		a.Y.Square(&a.X).Mul(&a.Y, &a.X).Add(&a.Y, &b)
+		if a.Y.Legendre() == 1 {
+			a.Y.Sqrt(&a.Y)
+			break
+		}
+	}
+	c.ClearCofactor(&a)
+
+	var circuit, witness g2ClearCofactor
+	witness.A.Assign(&a)
+	witness.C.Assign(&c)
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_761))
+}
+
 func randomPointG2() bls12377.G2Jac {
 	_, p2, _, _ := bls12377.Generators()
 
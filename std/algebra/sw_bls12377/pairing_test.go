@@ -128,6 +128,51 @@ func TestTriplePairingBLS377(t *testing.T) {
 }
 
 // utils
+type fixedQPairingBLS377 struct {
+	P1, P2, P3 G1Affine `gnark:",public"`
+	q          [3]bls12377.G2Affine
+	pairingRes bls12377.GT
+}
+
+func (circuit *fixedQPairingBLS377) Define(api frontend.API) error {
+
+	lines := []PrecomputedLines{PrecomputeLines(circuit.q[0]), PrecomputeLines(circuit.q[1]), PrecomputeLines(circuit.q[2])}
+	milRes, err := MillerLoopFixedQ(api, []G1Affine{circuit.P1, circuit.P2, circuit.P3}, lines)
+	if err != nil {
+		return err
+	}
+	pairingRes := FinalExponentiation(api, milRes)
+
+	mustbeEq(api, pairingRes, &circuit.pairingRes)
+
+	return nil
+}
+
+func TestFixedQPairingBLS377(t *testing.T) {
+
+	// pairing test data
+	P, Q, pairingRes := triplePairingData()
+
+	// create cs
+	var circuit, witness fixedQPairingBLS377
+	circuit.q = Q
+	circuit.pairingRes = pairingRes
+
+	// assign values to witness
+	witness.P1.Assign(&P[0])
+	witness.P2.Assign(&P[1])
+	witness.P3.Assign(&P[2])
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_761))
+
+	// wrong fixed Q
+	wrongCircuit := circuit
+	wrongCircuit.q[0] = Q[1]
+	assert.SolvingFailed(&wrongCircuit, &witness, test.WithCurves(ecc.BW6_761))
+
+}
+
 func pairingData() (P bls12377.G1Affine, Q bls12377.G2Affine, milRes, pairingRes bls12377.GT) {
 	_, _, P, Q = bls12377.Generators()
 	milRes, _ = bls12377.MillerLoop([]bls12377.G1Affine{P}, []bls12377.G2Affine{Q})
@@ -0,0 +1,219 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls24315
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark/frontend"
+)
+
+// PrecomputedLines are the line evaluations of the Miller loop for a fixed G2
+// point, in the order MillerLoopFixedQ consumes them
+type PrecomputedLines []LineEvaluation
+
+// ateLoopNAF NAF decomposition of the ate loop counter
+var ateLoopNAF [33]int8
+
+// nbPrecomputedLines is the number of line evaluations of a Miller loop
+var nbPrecomputedLines int
+
+func init() {
+	ecc.NafDecomposition(big.NewInt(ateLoop), ateLoopNAF[:])
+	nbPrecomputedLines = 1
+	for i := len(ateLoopNAF) - 3; i >= 0; i-- {
+		nbPrecomputedLines++
+		if ateLoopNAF[i] != 0 {
+			nbPrecomputedLines++
+		}
+	}
+}
+
+// PrecomputeLines computes natively the line evaluations of the Miller loop
+// for the fixed point Q. The result is made of constants: it must be computed
+// in the circuit definition, or stored in an unexported field of the circuit.
+func PrecomputeLines(Q bls24315.G2Affine) PrecomputedLines {
+	lines := make(PrecomputedLines, 0, nbPrecomputedLines)
+
+	var l1, l2 LineEvaluation
+	var Qneg bls24315.G2Affine
+	Qneg.Neg(&Q)
+	Qacc := Q
+	l1 = doubleStepNative(&Qacc)
+	lines = append(lines, l1)
+
+	for i := len(ateLoopNAF) - 3; i >= 0; i-- {
+		switch ateLoopNAF[i] {
+		case 0:
+			l1 = doubleStepNative(&Qacc)
+			lines = append(lines, l1)
+		case 1:
+			l1, l2 = doubleAndAddStepNative(&Qacc, &Q)
+			lines = append(lines, l1, l2)
+		default:
+			l1, l2 = doubleAndAddStepNative(&Qacc, &Qneg)
+			lines = append(lines, l1, l2)
+		}
+	}
+
+	return lines
+}
+
+// MillerLoopFixedQ computes the product of n miller loops (n can be 1) for
+// fixed G2 points given by their precomputed line evaluations. The result is
+// the same as MillerLoop's.
+func MillerLoopFixedQ(api frontend.API, P []G1Affine, lines []PrecomputedLines) (GT, error) {
+	// check input size match
+	n := len(P)
+	if n == 0 || n != len(lines) {
+		return GT{}, errors.New("invalid inputs sizes")
+	}
+	for k := 0; k < n; k++ {
+		if len(lines[k]) != nbPrecomputedLines {
+			return GT{}, errors.New("invalid number of precomputed lines")
+		}
+	}
+
+	var res GT
+	res.SetOne()
+
+	var l1 LineEvaluation
+	yInv := make([]frontend.Variable, n)
+	xOverY := make([]frontend.Variable, n)
+	for k := 0; k < n; k++ {
+		yInv[k] = api.DivUnchecked(1, P[k].Y)
+		xOverY[k] = api.DivUnchecked(P[k].X, P[k].Y)
+	}
+
+	// line evaluation of the j-th line of the k-th point
+	evaluate := func(k, j int) LineEvaluation {
+		var l LineEvaluation
+		l.R0.MulByFp(api, lines[k][j].R0, xOverY[k])
+		l.R1.MulByFp(api, lines[k][j].R1, yInv[k])
+		return l
+	}
+
+	// k = 0
+	l1 = evaluate(0, 0)
+	res.D1.C0, res.D1.C1 = l1.R0, l1.R1
+
+	if n >= 2 {
+		// k = 1
+		l1 = evaluate(1, 0)
+		res.Mul034By034(api, l1.R0, l1.R1, res.D1.C0, res.D1.C1)
+	}
+
+	// k >= 2
+	for k := 2; k < n; k++ {
+		l1 = evaluate(k, 0)
+		res.MulBy034(api, l1.R0, l1.R1)
+	}
+
+	j := 1
+	for i := len(ateLoopNAF) - 3; i >= 0; i-- {
+		res.Square(api, res)
+
+		nbLines := 1
+		if ateLoopNAF[i] != 0 {
+			nbLines = 2
+		}
+		for k := 0; k < n; k++ {
+			for m := 0; m < nbLines; m++ {
+				l1 = evaluate(k, j+m)
+				res.MulBy034(api, l1.R0, l1.R1)
+			}
+		}
+		j += nbLines
+	}
+
+	res.Conjugate(api, res)
+
+	return res, nil
+}
+
+// doubleStepNative is DoubleStep computed out of circuit
+func doubleStepNative(p1 *bls24315.G2Affine) LineEvaluation {
+	var n, d, l, xr, yr bls24315.E4
+
+	// lambda = 3*p1.x**2/2*p.y
+	n.Square(&p1.X)
+	d.Double(&n)
+	n.Add(&n, &d)
+	d.Double(&p1.Y)
+	d.Inverse(&d)
+	l.Mul(&n, &d)
+
+	// xr = lambda**2-2*p1.x
+	xr.Square(&l).Sub(&xr, &p1.X).Sub(&xr, &p1.X)
+
+	// yr = lambda*(p.x-xr)-p.y
+	yr.Sub(&p1.X, &xr).Mul(&l, &yr).Sub(&yr, &p1.Y)
+
+	line := newLineEvaluation(&l, &p1.X, &p1.Y)
+	p1.X, p1.Y = xr, yr
+
+	return line
+}
+
+// doubleAndAddStepNative is DoubleAndAddStep computed out of circuit
+func doubleAndAddStepNative(p1, p2 *bls24315.G2Affine) (LineEvaluation, LineEvaluation) {
+	var n, d, l1, l2, x3, x4, y4 bls24315.E4
+
+	// compute lambda1 = (y2-y1)/(x2-x1)
+	n.Sub(&p1.Y, &p2.Y)
+	d.Sub(&p1.X, &p2.X)
+	d.Inverse(&d)
+	l1.Mul(&n, &d)
+
+	// x3 =lambda1**2-p1.x-p2.x
+	x3.Square(&l1).Sub(&x3, &p1.X).Sub(&x3, &p2.X)
+
+	line1 := newLineEvaluation(&l1, &p1.X, &p1.Y)
+
+	// compute lambda2 = -lambda1-2*y1/(x3-x1)
+	n.Double(&p1.Y)
+	d.Sub(&x3, &p1.X)
+	d.Inverse(&d)
+	l2.Mul(&n, &d)
+	l2.Add(&l2, &l1).Neg(&l2)
+
+	// compute x4 = lambda2**2-x1-x3
+	x4.Square(&l2).Sub(&x4, &p1.X).Sub(&x4, &x3)
+
+	// compute y4 = lambda2*(x1 - x4)-y1
+	y4.Sub(&p1.X, &x4).Mul(&l2, &y4).Sub(&y4, &p1.Y)
+
+	line2 := newLineEvaluation(&l2, &p1.X, &p1.Y)
+	p1.X, p1.Y = x4, y4
+
+	return line1, line2
+}
+
+// newLineEvaluation returns the constant line evaluation (-λ, λ·x-y)
+func newLineEvaluation(lambda, x, y *bls24315.E4) LineEvaluation {
+	var r0, r1 bls24315.E4
+	r0.Neg(lambda)
+	r1.Mul(lambda, x).Sub(&r1, y)
+
+	var line LineEvaluation
+	line.R0.Assign(&r0)
+	line.R1.Assign(&r1)
+	return line
+}
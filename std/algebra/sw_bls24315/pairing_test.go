@@ -128,6 +128,51 @@ func TestTriplePairingBLS24315(t *testing.T) {
 
 }
 
+type fixedQPairingBLS24315 struct {
+	P1, P2, P3 G1Affine `gnark:",public"`
+	q          [3]bls24315.G2Affine
+	pairingRes bls24315.GT
+}
+
+func (circuit *fixedQPairingBLS24315) Define(api frontend.API) error {
+
+	lines := []PrecomputedLines{PrecomputeLines(circuit.q[0]), PrecomputeLines(circuit.q[1]), PrecomputeLines(circuit.q[2])}
+	milRes, err := MillerLoopFixedQ(api, []G1Affine{circuit.P1, circuit.P2, circuit.P3}, lines)
+	if err != nil {
+		return err
+	}
+	pairingRes := FinalExponentiation(api, milRes)
+
+	mustbeEq(api, pairingRes, &circuit.pairingRes)
+
+	return nil
+}
+
+func TestFixedQPairingBLS24315(t *testing.T) {
+
+	// pairing test data
+	P, Q, pairingRes := triplePairingData()
+
+	// create cs
+	var circuit, witness fixedQPairingBLS24315
+	circuit.q = Q
+	circuit.pairingRes = pairingRes
+
+	// assign values to witness
+	witness.P1.Assign(&P[0])
+	witness.P2.Assign(&P[1])
+	witness.P3.Assign(&P[2])
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_633), test.WithBackends(backend.GROTH16))
+
+	// wrong fixed Q
+	wrongCircuit := circuit
+	wrongCircuit.q[0] = Q[1]
+	assert.SolvingFailed(&wrongCircuit, &witness, test.WithCurves(ecc.BW6_633), test.WithBackends(backend.GROTH16))
+
+}
+
 // utils
 func pairingData() (P bls24315.G1Affine, Q bls24315.G2Affine, milRes bls24315.E24, pairingRes bls24315.GT) {
 	_, _, P, Q = bls24315.Generators()
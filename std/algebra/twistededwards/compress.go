@@ -0,0 +1,124 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package twistededwards
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+func init() {
+	hint.Register(decompressHint)
+}
+
+// Compress returns the compressed encoding of p1, as the bytes of gnark-crypto's
+// PointAffine.Bytes: Y in little endian on the byte size of the field, with the
+// most significant bit of the last byte set if X is lexicographically largest
+// (X > (q-1)/2). The encoding is canonical: the coordinates are decomposed
+// modulo q.
+func Compress(curve Curve, p1 Point) []frontend.Variable {
+	api := curve.API()
+	nbBytes := (api.Compiler().Curve().Info().Fr.Bits + 7) / 8
+
+	yBits := bits.ToCanonicalBinary(api, p1.Y)
+	sign := bits.IsLexicographicallyLargest(api, p1.X)
+
+	res := make([]frontend.Variable, nbBytes)
+	for i := range res {
+		b := make([]frontend.Variable, 8)
+		for j := range b {
+			if 8*i+j < len(yBits) {
+				b[j] = yBits[8*i+j]
+			} else {
+				b[j] = 0
+			}
+		}
+		if i == nbBytes-1 {
+			b[7] = sign
+		}
+		res[i] = api.FromBinary(b...)
+	}
+	return res
+}
+
+// Decompress returns the point encoded by buf (see Compress). The solving
+// fails if buf is not the canonical encoding of a point of the curve: each
+// element must be a byte, Y must be smaller than q, and the sign of X must be
+// 0 when X = 0.
+//
+// The X coordinate is computed by a hint, and the constraints check that the
+// point is on the curve and that X has the encoded sign.
+func Decompress(curve Curve, buf []frontend.Variable) (Point, error) {
+	api := curve.API()
+	modulus := api.Compiler().Curve().Info().Fr.Modulus()
+	nbBytes := (modulus.BitLen() + 7) / 8
+	if len(buf) != nbBytes {
+		return Point{}, errors.New("invalid size of compressed point")
+	}
+
+	b := make([]frontend.Variable, 0, 8*nbBytes)
+	for i := range buf {
+		b = append(b, api.ToBinary(buf[i], 8)...)
+	}
+	sign := b[len(b)-1]
+	yBits := b[:len(b)-1]
+	bits.AssertBitsLessOrEqual(api, yBits, new(big.Int).Sub(modulus, big.NewInt(1)))
+
+	var res Point
+	res.Y = bits.FromBinary(api, yBits, bits.WithUnconstrainedInputs())
+	x, err := api.Compiler().NewHint(decompressHint, 1, curve.Params().A, curve.Params().D, res.Y, sign)
+	if err != nil {
+		return Point{}, err
+	}
+	res.X = x[0]
+
+	curve.AssertIsOnCurve(res)
+	api.AssertIsEqual(bits.IsLexicographicallyLargest(api, res.X), sign)
+
+	return res, nil
+}
+
+// decompressHint returns the x coordinate of the point (x, y) of the curve
+// ax²+y² = 1+dx²y² with the given sign; inputs are a, d, y and the sign
+func decompressHint(curve ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	q := curve.Info().Fr.Modulus()
+	a, d, y, sign := inputs[0], inputs[1], inputs[2], inputs[3]
+
+	// x² = (1 - y²) / (a - dy²)
+	var yy, num, den big.Int
+	yy.Mul(y, y).Mod(&yy, q)
+	num.Sub(big.NewInt(1), &yy).Mod(&num, q)
+	den.Mul(d, &yy).Sub(a, &den).Mod(&den, q)
+	if den.ModInverse(&den, q) == nil {
+		return errors.New("invalid y coordinate")
+	}
+	num.Mul(&num, &den).Mod(&num, q)
+	if results[0].ModSqrt(&num, q) == nil {
+		return errors.New("no point with this y coordinate")
+	}
+
+	halfQ := new(big.Int).Rsh(q, 1)
+	if (results[0].Cmp(halfQ) > 0) != (sign.Sign() != 0) {
+		results[0].Sub(q, results[0]).Mod(results[0], q)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package twistededwards
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	tbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+	tbls12381_bandersnatch "github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	tbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+	tbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+	tbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tbw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/twistededwards"
+	tbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// compressNative returns gnark-crypto's compressed encoding of p
+func compressNative(curveID twistededwards.ID, p [2]*big.Int) []byte {
+	switch curveID {
+	case twistededwards.BN254:
+		var q tbn254.PointAffine
+		q.X.SetBigInt(p[0])
+		q.Y.SetBigInt(p[1])
+		return q.Marshal()
+	case twistededwards.BLS12_377:
+		var q tbls12377.PointAffine
+		q.X.SetBigInt(p[0])
+		q.Y.SetBigInt(p[1])
+		return q.Marshal()
+	case twistededwards.BLS12_381:
+		var q tbls12381.PointAffine
+		q.X.SetBigInt(p[0])
+		q.Y.SetBigInt(p[1])
+		return q.Marshal()
+	case twistededwards.BLS12_381_BANDERSNATCH:
+		var q tbls12381_bandersnatch.PointAffine
+		q.X.SetBigInt(p[0])
+		q.Y.SetBigInt(p[1])
+		return q.Marshal()
+	case twistededwards.BLS24_315:
+		var q tbls24315.PointAffine
+		q.X.SetBigInt(p[0])
+		q.Y.SetBigInt(p[1])
+		return q.Marshal()
+	case twistededwards.BW6_761:
+		var q tbw6761.PointAffine
+		q.X.SetBigInt(p[0])
+		q.Y.SetBigInt(p[1])
+		return q.Marshal()
+	case twistededwards.BW6_633:
+		var q tbw6633.PointAffine
+		q.X.SetBigInt(p[0])
+		q.Y.SetBigInt(p[1])
+		return q.Marshal()
+	default:
+		panic("not implemented")
+	}
+}
+
+func assignBytes(buf []byte) []frontend.Variable {
+	res := make([]frontend.Variable, len(buf))
+	for i := range buf {
+		res[i] = buf[i]
+	}
+	return res
+}
+
+type compressCircuit struct {
+	curveID twistededwards.ID
+	P       Point
+	Buf     []frontend.Variable
+}
+
+func (circuit *compressCircuit) Define(api frontend.API) error {
+	curve, err := NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+
+	buf := Compress(curve, circuit.P)
+	for i := range buf {
+		api.AssertIsEqual(buf[i], circuit.Buf[i])
+	}
+
+	p, err := Decompress(curve, circuit.Buf)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(p.X, circuit.P.X)
+	api.AssertIsEqual(p.Y, circuit.P.Y)
+
+	return nil
+}
+
+func TestCompress(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	circuits := make([]compressCircuit, len(curves))
+	for i, curveID := range curves {
+		native, err := NewNativeCurve(curveID)
+		assert.NoError(err)
+		snarkCurve, err := GetSnarkCurve(curveID)
+		assert.NoError(err)
+
+		s, err := rand.Int(rand.Reader, native.Params().Order)
+		assert.NoError(err)
+		p := native.ScalarMul(native.Params().Base, s)
+		buf := compressNative(curveID, p)
+		assert.Equal(buf, native.Compress(p))
+
+		circuits[i] = compressCircuit{curveID: curveID, Buf: make([]frontend.Variable, len(buf))}
+		witness := compressCircuit{P: Point{X: p[0], Y: p[1]}, Buf: assignBytes(buf)}
+		assert.SolvingSucceeded(&circuits[i], &witness, test.WithCurves(snarkCurve))
+
+		// -p
+		buf[len(buf)-1] ^= 0x80
+		witness.Buf = assignBytes(buf)
+		assert.SolvingFailed(&circuits[i], &witness, test.WithCurves(snarkCurve))
+	}
+}
+
+type decompressCircuit struct {
+	curveID twistededwards.ID
+	P       Point
+	Buf     []frontend.Variable
+}
+
+func (circuit *decompressCircuit) Define(api frontend.API) error {
+	curve, err := NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	p, err := Decompress(curve, circuit.Buf)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(p.X, circuit.P.X)
+	api.AssertIsEqual(p.Y, circuit.P.Y)
+	return nil
+}
+
+func TestDecompressNonCanonical(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	curveID := twistededwards.BN254
+	snarkCurve, err := GetSnarkCurve(curveID)
+	assert.NoError(err)
+	q := snarkCurve.Info().Fr.Modulus()
+
+	// the neutral element (0, 1)
+	buf := make([]byte, 32)
+	buf[0] = 1
+	circuit := decompressCircuit{curveID: curveID, Buf: make([]frontend.Variable, len(buf))}
+	witness := decompressCircuit{P: Point{X: 0, Y: 1}, Buf: assignBytes(buf)}
+	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(snarkCurve))
+
+	// the sign of X = 0 must be 0
+	buf[31] |= 0x80
+	witness.Buf = assignBytes(buf)
+	assert.SolvingFailed(&circuit, &witness, test.WithCurves(snarkCurve))
+
+	// Y + q encodes the same Y, but is not canonical
+	y := new(big.Int).Add(q, big.NewInt(1))
+	yBytes := y.Bytes()
+	for i := range buf {
+		buf[i] = 0
+	}
+	for i := range yBytes {
+		buf[i] = yBytes[len(yBytes)-1-i]
+	}
+	witness.Buf = assignBytes(buf)
+	assert.SolvingFailed(&circuit, &witness, test.WithCurves(snarkCurve))
+}
@@ -46,7 +46,9 @@ func (c *curve) AssertIsOnCurve(p1 Point) {
 }
 func (c *curve) ScalarMul(p1 Point, scalar frontend.Variable) Point {
 	var p Point
-	if c.endo != nil {
+	if base, ok := constantPoint(c.api, &p1, c.params); ok {
+		p.fixedBaseScalarMul(c.api, base, scalar, c.params)
+	} else if c.endo != nil {
 		// TODO restore
 		// this is disabled until this issue is solved https://github.com/ConsenSys/gnark/issues/268
 		// p.scalarMulGLV(c.api, &p1, scalar, c.params, c.endo)
@@ -57,6 +59,13 @@ func (c *curve) ScalarMul(p1 Point, scalar frontend.Variable) Point {
 	return p
 }
 func (c *curve) DoubleBaseScalarMul(p1, p2 Point, s1, s2 frontend.Variable) Point {
+	_, const1 := constantPoint(c.api, &p1, c.params)
+	_, const2 := constantPoint(c.api, &p2, c.params)
+	if const1 || const2 {
+		// the scalar multiplication of a constant point doesn't need doublings,
+		// so sharing them doesn't pay off
+		return c.Add(c.ScalarMul(p1, s1), c.ScalarMul(p2, s2))
+	}
 	var p Point
 	p.doubleBaseScalarMul(c.api, &p1, &p2, s1, s2, c.params)
 	return p
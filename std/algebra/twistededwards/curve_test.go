@@ -31,6 +31,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/twistededwards"
 
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/consensys/gnark/test"
 )
 
@@ -382,3 +383,188 @@ func (p *CurveParams) randomScalar() *big.Int {
 	r, _ := rand.Int(rand.Reader, p.Order)
 	return r
 }
+
+type fixedBaseCircuit struct {
+	curveID twistededwards.ID
+	Base    Point
+	P       Point
+	S1, S2  frontend.Variable
+}
+
+func (circuit *fixedBaseCircuit) Define(api frontend.API) error {
+	curve, err := NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+
+	// the constant base point, and the same point as a witness
+	base := Point{X: curve.Params().Base[0], Y: curve.Params().Base[1]}
+
+	{
+		// scalar mul fixed
+		res := curve.ScalarMul(base, circuit.S1)
+		expected := curve.ScalarMul(circuit.Base, circuit.S1)
+		api.AssertIsEqual(res.X, expected.X)
+		api.AssertIsEqual(res.Y, expected.Y)
+	}
+
+	{
+		// double scalar mul, first point fixed
+		res := curve.DoubleBaseScalarMul(base, circuit.P, circuit.S1, circuit.S2)
+		expected := curve.DoubleBaseScalarMul(circuit.Base, circuit.P, circuit.S1, circuit.S2)
+		api.AssertIsEqual(res.X, expected.X)
+		api.AssertIsEqual(res.Y, expected.Y)
+	}
+
+	{
+		// double scalar mul, second point fixed
+		res := curve.DoubleBaseScalarMul(circuit.P, base, circuit.S2, circuit.S1)
+		expected := curve.DoubleBaseScalarMul(circuit.P, circuit.Base, circuit.S2, circuit.S1)
+		api.AssertIsEqual(res.X, expected.X)
+		api.AssertIsEqual(res.Y, expected.Y)
+	}
+
+	return nil
+}
+
+func TestFixedBaseScalarMul(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// the circuits must have distinct addresses, as the compiled circuits are
+	// cached by address and several twisted Edwards curves share a snark curve
+	circuits := make([]fixedBaseCircuit, len(curves))
+
+	for i, curve := range curves {
+		circuit := &circuits[i]
+		circuit.curveID = curve
+
+		snarkCurve, err := GetSnarkCurve(curve)
+		assert.NoError(err)
+		params, err := GetCurveParams(curve)
+		assert.NoError(err)
+
+		var witness fixedBaseCircuit
+		witness.P, _, _, _, _, _, _, _, _ = testData(params, curve)
+		witness.Base = Point{X: params.Base[0], Y: params.Base[1]}
+
+		// the scalars span the whole native field, not only [0, Order)
+		s1, err := rand.Int(rand.Reader, snarkCurve.Info().Fr.Modulus())
+		assert.NoError(err)
+		witness.S1 = s1
+		witness.S2 = params.randomScalar()
+
+		assert.SolvingSucceeded(circuit, &witness, test.WithCurves(snarkCurve))
+
+		witness.Base.X = params.randomScalar()
+		assert.SolvingFailed(circuit, &witness, test.WithCurves(snarkCurve))
+	}
+}
+
+func TestFixedBaseScalarMulConstraints(t *testing.T) {
+	// the scalar multiplication of the base point is cheaper when the point is
+	// constant
+	for _, curve := range curves {
+		snarkCurve, err := GetSnarkCurve(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixed, err := frontend.Compile(snarkCurve, r1cs.NewBuilder, &scalarMulCircuit{curveID: curve, fixed: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		variable, err := frontend.Compile(snarkCurve, r1cs.NewBuilder, &scalarMulCircuit{curveID: curve})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fixed.GetNbConstraints() >= variable.GetNbConstraints() {
+			t.Fatalf("fixed base scalar multiplication: %d constraints, variable base: %d", fixed.GetNbConstraints(), variable.GetNbConstraints())
+		}
+	}
+}
+
+type scalarMulCircuit struct {
+	curveID twistededwards.ID
+	fixed   bool
+	Base, R Point
+	S       frontend.Variable
+}
+
+func (circuit *scalarMulCircuit) Define(api frontend.API) error {
+	curve, err := NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	// the witness point is constrained in both cases
+	curve.AssertIsOnCurve(circuit.Base)
+	base := circuit.Base
+	if circuit.fixed {
+		base = Point{X: curve.Params().Base[0], Y: curve.Params().Base[1]}
+	}
+	res := curve.ScalarMul(base, circuit.S)
+	api.AssertIsEqual(res.X, circuit.R.X)
+	api.AssertIsEqual(res.Y, circuit.R.Y)
+	return nil
+}
+
+type offCurveCircuit struct {
+	curveID twistededwards.ID
+	P       Point
+}
+
+func (circuit *offCurveCircuit) Define(api frontend.API) error {
+	curve, err := NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	res := curve.ScalarMul(circuit.P, 2)
+	expected := curve.Double(circuit.P)
+	api.AssertIsEqual(res.X, expected.X)
+	api.AssertIsEqual(res.Y, expected.Y)
+	return nil
+}
+
+func TestScalarMulOffCurveConstant(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// the test engine sees the witness point as a constant, which must not be
+	// given to the fixed base scalar multiplication as it is off the curve
+	circuits := make([]offCurveCircuit, len(curves))
+
+	for i, curve := range curves {
+		circuit := &circuits[i]
+		circuit.curveID = curve
+
+		snarkCurve, err := GetSnarkCurve(curve)
+		assert.NoError(err)
+		params, err := GetCurveParams(curve)
+		assert.NoError(err)
+
+		witness := offCurveCircuit{P: Point{X: params.Base[0], Y: params.randomScalar()}}
+		assert.SolvingSucceeded(circuit, &witness, test.WithCurves(snarkCurve))
+	}
+}
+
+func TestNativeAddOffCurve(t *testing.T) {
+	for _, curve := range curves {
+		native, err := NewNativeCurve(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		modulus := native.Modulus()
+
+		// 1 + d·x1·x2·y1·y2 = 0 for (1, 1) and (1, -1/d)
+		y := new(big.Int).ModInverse(native.Params().D, modulus)
+		y.Neg(y).Mod(y, modulus)
+		p1 := [2]*big.Int{big.NewInt(1), big.NewInt(1)}
+		p2 := [2]*big.Int{big.NewInt(1), y}
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected Add to panic on points off the curve")
+				}
+			}()
+			native.Add(p1, p2)
+		}()
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package twistededwards
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// constantPoint returns the coordinates of p1 if they are both constant and p1
+// is on the curve. The addition law being complete on the curve, the
+// precomputations on such a point never divide by 0. Other constant points, for
+// instance off-curve witness values in the test engine, are treated as
+// variables.
+func constantPoint(api frontend.API, p1 *Point, curve *CurveParams) ([2]*big.Int, bool) {
+	x, okX := api.Compiler().ConstantValue(p1.X)
+	y, okY := api.Compiler().ConstantValue(p1.Y)
+	if !okX || !okY {
+		return [2]*big.Int{}, false
+	}
+	native := NativeCurve{params: curve, modulus: api.Compiler().Curve().Info().Fr.Modulus()}
+	base := [2]*big.Int{x, y}
+	return base, native.IsOnCurve(base)
+}
+
+// fixedBaseScalarMul computes the scalar multiplication of a constant point
+// base: base point (coordinates)
+// curve: parameters of the Edwards curve
+// scal: scalar as a SNARK constraint
+// The scalar is cut in windows of 2 bits; for the i-th window, the multiples
+// [k·4ⁱ]base (k = 0, 1, 2, 3) are precomputed out of circuit and one of them is
+// selected with a Lookup2. The result is the sum of the selected points, hence
+// no doublings are needed.
+func (p *Point) fixedBaseScalarMul(api frontend.API, base [2]*big.Int, scalar frontend.Variable, curve *CurveParams) *Point {
+	// first unpack the scalar
+	b := api.ToBinary(scalar)
+
+	native := NativeCurve{params: curve, modulus: api.Compiler().Curve().Info().Fr.Modulus()}
+
+	res := Point{}
+	tmp := Point{}
+
+	// acc = [4ⁱ]base
+	acc := base
+	for i := 0; i < len(b); i += 2 {
+		acc2 := native.Double(acc)
+		acc3 := native.Add(acc2, acc)
+
+		if i+1 < len(b) {
+			tmp.X = api.Lookup2(b[i], b[i+1], 0, acc[0], acc2[0], acc3[0])
+			tmp.Y = api.Lookup2(b[i], b[i+1], 1, acc[1], acc2[1], acc3[1])
+		} else {
+			tmp.X = api.Select(b[i], acc[0], 0)
+			tmp.Y = api.Select(b[i], acc[1], 1)
+		}
+
+		if i == 0 {
+			res = tmp
+		} else {
+			res.add(api, &res, &tmp, curve)
+		}
+
+		acc = native.Double(acc2)
+	}
+
+	p.X = res.X
+	p.Y = res.Y
+
+	return p
+}
@@ -0,0 +1,142 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package twistededwards
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+)
+
+var errAddNotOnCurve = errors.New("twistededwards: addition of points not on the curve")
+
+// NativeCurve implements the arithmetic of a twisted Edwards curve out of
+// circuit, on big.Int coordinates modulo the scalar field of the snark curve.
+// Points are represented as CurveParams.Base. It is meant for the
+// precomputation of constants and for witness generation.
+type NativeCurve struct {
+	params  *CurveParams
+	modulus *big.Int
+}
+
+// NewNativeCurve returns the native arithmetic of the twisted Edwards curve id
+func NewNativeCurve(id twistededwards.ID) (*NativeCurve, error) {
+	params, err := GetCurveParams(id)
+	if err != nil {
+		return nil, err
+	}
+	snarkCurve, err := GetSnarkCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	return &NativeCurve{params: params, modulus: snarkCurve.Info().Fr.Modulus()}, nil
+}
+
+// Params returns the parameters of the curve
+func (c *NativeCurve) Params() *CurveParams {
+	return c.params
+}
+
+// Modulus returns the modulus of the field of definition of the curve
+func (c *NativeCurve) Modulus() *big.Int {
+	return c.modulus
+}
+
+// Add returns p1+p2. The addition law is complete on the curve; Add panics if
+// a denominator is 0, which only happens for points off the curve.
+func (c *NativeCurve) Add(p1, p2 [2]*big.Int) [2]*big.Int {
+	// x3 = (x1y2 + y1x2) / (1 + dx1x2y1y2)
+	// y3 = (y1y2 - ax1x2) / (1 - dx1x2y1y2)
+	var x1y2, y1x2, x1x2, y1y2, dxy, tmp big.Int
+	x1y2.Mul(p1[0], p2[1])
+	y1x2.Mul(p1[1], p2[0])
+	x1x2.Mul(p1[0], p2[0])
+	y1y2.Mul(p1[1], p2[1])
+	dxy.Mul(&x1x2, &y1y2).Mul(&dxy, c.params.D).Mod(&dxy, c.modulus)
+
+	x3 := new(big.Int).Add(&x1y2, &y1x2)
+	tmp.Add(big.NewInt(1), &dxy).Mod(&tmp, c.modulus)
+	if tmp.ModInverse(&tmp, c.modulus) == nil {
+		panic(errAddNotOnCurve)
+	}
+	x3.Mul(x3, &tmp).Mod(x3, c.modulus)
+
+	y3 := new(big.Int).Mul(c.params.A, &x1x2)
+	y3.Sub(&y1y2, y3)
+	tmp.Sub(big.NewInt(1), &dxy).Mod(&tmp, c.modulus)
+	if tmp.ModInverse(&tmp, c.modulus) == nil {
+		panic(errAddNotOnCurve)
+	}
+	y3.Mul(y3, &tmp).Mod(y3, c.modulus)
+
+	return [2]*big.Int{x3, y3}
+}
+
+// Double returns [2]p1
+func (c *NativeCurve) Double(p1 [2]*big.Int) [2]*big.Int {
+	return c.Add(p1, p1)
+}
+
+// Neg returns -p1
+func (c *NativeCurve) Neg(p1 [2]*big.Int) [2]*big.Int {
+	x := new(big.Int).Neg(p1[0])
+	x.Mod(x, c.modulus)
+	return [2]*big.Int{x, new(big.Int).Set(p1[1])}
+}
+
+// ScalarMul returns [scalar]p1, for a non negative scalar
+func (c *NativeCurve) ScalarMul(p1 [2]*big.Int, scalar *big.Int) [2]*big.Int {
+	res := [2]*big.Int{big.NewInt(0), big.NewInt(1)}
+	for i := scalar.BitLen() - 1; i >= 0; i-- {
+		res = c.Double(res)
+		if scalar.Bit(i) == 1 {
+			res = c.Add(res, p1)
+		}
+	}
+	return res
+}
+
+// IsOnCurve returns true if ax²+y² = 1+dx²y²
+func (c *NativeCurve) IsOnCurve(p1 [2]*big.Int) bool {
+	var xx, yy, lhs, rhs big.Int
+	xx.Mul(p1[0], p1[0])
+	yy.Mul(p1[1], p1[1])
+	lhs.Mul(c.params.A, &xx).Add(&lhs, &yy).Mod(&lhs, c.modulus)
+	rhs.Mul(&xx, &yy).Mul(&rhs, c.params.D).Add(&rhs, big.NewInt(1)).Mod(&rhs, c.modulus)
+	return lhs.Cmp(&rhs) == 0
+}
+
+// Compress returns the compressed encoding of p1, as Compress in a circuit: Y
+// in little endian, with the most significant bit of the last byte set if X is
+// lexicographically largest
+func (c *NativeCurve) Compress(p1 [2]*big.Int) []byte {
+	nbBytes := (c.modulus.BitLen() + 7) / 8
+	var x, y big.Int
+	x.Mod(p1[0], c.modulus)
+	y.Mod(p1[1], c.modulus)
+
+	res := make([]byte, nbBytes)
+	y.FillBytes(res)
+	for i, j := 0, nbBytes-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	if x.Cmp(new(big.Int).Rsh(c.modulus, 1)) > 0 {
+		res[nbBytes-1] |= 0x80
+	}
+	return res
+}
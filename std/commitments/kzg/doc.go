@@ -0,0 +1,25 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kzg provides ZKP-circuit functions to verify KZG polynomial
+// commitment opening proofs over the inner curves of the 2-chains:
+//
+//   - bls12377 verifies BLS12_377 openings inside a BW6_761 circuit
+//   - bls24315 verifies BLS24_315 openings inside a BW6_633 circuit
+//
+// The sub-packages are generated from the same templates by
+// internal/generator/std.
+package kzg
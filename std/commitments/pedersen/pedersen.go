@@ -0,0 +1,98 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pedersen provides a ZKP-circuit function to compute Pedersen vector
+// commitments on a twisted Edwards curve.
+//
+// The commitment to the values v₀, ..., v_{n-1} with the randomness r is
+// C = ∑ [vᵢ]Gᵢ + [r]H. The generators are derived from a domain string as the
+// ones of a Pedersen hash (see std/hash/pedersen.Generator): H has the index 0
+// and Gᵢ the index i+1.
+//
+// The generators being constants of the circuit, the scalar multiplications
+// use precomputed multiples of the generators, and no doublings (see
+// twistededwards.Curve.ScalarMul).
+package pedersen
+
+import (
+	"errors"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash/pedersen"
+)
+
+// Key stores the generators of a Pedersen vector commitment
+type Key struct {
+	ID tedwards.ID
+	G  [][2]*big.Int // generators of the values
+	H  [2]*big.Int   // generator of the randomness
+}
+
+// NewKey derives the key to commit to vectors of n values on the twisted
+// Edwards curve id, from the domain
+func NewKey(id tedwards.ID, domain string, n int) (Key, error) {
+	var err error
+	key := Key{ID: id, G: make([][2]*big.Int, n)}
+	if key.H, err = pedersen.Generator(id, domain, 0); err != nil {
+		return Key{}, err
+	}
+	for i := range key.G {
+		if key.G[i], err = pedersen.Generator(id, domain, i+1); err != nil {
+			return Key{}, err
+		}
+	}
+	return key, nil
+}
+
+// Commit returns the commitment to the values with the randomness, computed
+// out of circuit. The values and the randomness are reduced modulo the snark
+// field, as the variables of a circuit.
+func (key *Key) Commit(values []*big.Int, randomness *big.Int) ([2]*big.Int, error) {
+	if len(values) != len(key.G) {
+		return [2]*big.Int{}, errors.New("the numbers of values and generators differ")
+	}
+	curve, err := twistededwards.NewNativeCurve(key.ID)
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+
+	var s big.Int
+	res := curve.ScalarMul(key.H, s.Mod(randomness, curve.Modulus()))
+	for i := range values {
+		res = curve.Add(res, curve.ScalarMul(key.G[i], s.Mod(values[i], curve.Modulus())))
+	}
+	return res, nil
+}
+
+// Commit returns the commitment to the values with the randomness
+func Commit(api frontend.API, key Key, values []frontend.Variable, randomness frontend.Variable) (twistededwards.Point, error) {
+	if len(values) != len(key.G) {
+		return twistededwards.Point{}, errors.New("the numbers of values and generators differ")
+	}
+	curve, err := twistededwards.NewEdCurve(api, key.ID)
+	if err != nil {
+		return twistededwards.Point{}, err
+	}
+
+	res := curve.ScalarMul(twistededwards.Point{X: key.H[0], Y: key.H[1]}, randomness)
+	for i := range values {
+		res = curve.Add(res, curve.ScalarMul(twistededwards.Point{X: key.G[i][0], Y: key.G[i][1]}, values[i]))
+	}
+	return res, nil
+}
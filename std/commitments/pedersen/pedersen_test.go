@@ -0,0 +1,94 @@
+package pedersen
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+var curves = []tedwards.ID{tedwards.BN254, tedwards.BLS12_377, tedwards.BLS12_381, tedwards.BLS12_381_BANDERSNATCH, tedwards.BW6_761, tedwards.BW6_633, tedwards.BLS24_315}
+
+const (
+	domain   = "gnark_pedersen_commitment_test"
+	nbValues = 3
+)
+
+type commitCircuit struct {
+	key        Key
+	Values     [nbValues]frontend.Variable
+	Randomness frontend.Variable
+	C          twistededwards.Point
+}
+
+func (circuit *commitCircuit) Define(api frontend.API) error {
+	c, err := Commit(api, circuit.key, circuit.Values[:], circuit.Randomness)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(c.X, circuit.C.X)
+	api.AssertIsEqual(c.Y, circuit.C.Y)
+	return nil
+}
+
+func TestCommit(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	circuits := make([]commitCircuit, len(curves))
+	for i, id := range curves {
+		snarkCurve, err := twistededwards.GetSnarkCurve(id)
+		assert.NoError(err)
+		modulus := snarkCurve.Info().Fr.Modulus()
+
+		key, err := NewKey(id, domain, nbValues)
+		assert.NoError(err)
+
+		values := make([]*big.Int, nbValues)
+		var witness commitCircuit
+		for j := range values {
+			values[j], err = rand.Int(rand.Reader, modulus)
+			assert.NoError(err)
+			witness.Values[j] = values[j]
+		}
+		// a value of a single chunk
+		values[1].SetInt64(5)
+		witness.Values[1] = values[1]
+		randomness, err := rand.Int(rand.Reader, modulus)
+		assert.NoError(err)
+		witness.Randomness = randomness
+
+		c, err := key.Commit(values, randomness)
+		assert.NoError(err)
+		witness.C.X, witness.C.Y = c[0], c[1]
+
+		circuits[i].key = key
+		assert.SolvingSucceeded(&circuits[i], &witness, test.WithCurves(snarkCurve))
+
+		witness.Randomness = new(big.Int).Add(randomness, big.NewInt(1))
+		assert.SolvingFailed(&circuits[i], &witness, test.WithCurves(snarkCurve))
+	}
+}
+
+func TestCommitHomomorphism(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	key, err := NewKey(tedwards.BN254, domain, 2)
+	assert.NoError(err)
+	curve, err := twistededwards.NewNativeCurve(tedwards.BN254)
+	assert.NoError(err)
+
+	// Commit(a, r) + Commit(b, s) = Commit(a+b, r+s)
+	c1, err := key.Commit([]*big.Int{big.NewInt(1), big.NewInt(2)}, big.NewInt(3))
+	assert.NoError(err)
+	c2, err := key.Commit([]*big.Int{big.NewInt(10), big.NewInt(20)}, big.NewInt(30))
+	assert.NoError(err)
+	c3, err := key.Commit([]*big.Int{big.NewInt(11), big.NewInt(22)}, big.NewInt(33))
+	assert.NoError(err)
+
+	sum := curve.Add(c1, c2)
+	assert.True(sum[0].Cmp(c3[0]) == 0 && sum[1].Cmp(c3[1]) == 0, "commitments are not additively homomorphic")
+}
@@ -0,0 +1,104 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package elgamal provides ZKP-circuit functions for the ElGamal encryption on
+// twisted Edwards curves.
+//
+// The message m is encoded as the point [m]G ("exponential" ElGamal), so that
+// the ciphertexts are additively homomorphic: the sum of the encryptions of m₁
+// and m₂ is an encryption of m₁+m₂. With the private key s and the public key
+// A = [s]G, the encryption of m with the randomness r is
+//
+//	(C₁, C₂) = ([r]G, [m]G + [r]A)
+//
+// and the decryption recovers [m]G = C₂ - [s]C₁. Finding m from [m]G is a
+// discrete logarithm: the messages must be small (votes, tallies, ...).
+//
+// The points given as inputs (public keys, ciphertexts) are not checked to be
+// on the curve: the caller must check them if they are not trusted.
+package elgamal
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// PublicKey stores an ElGamal public key (to be used in gnark circuit)
+type PublicKey struct {
+	A twistededwards.Point
+}
+
+// Ciphertext stores an ElGamal ciphertext (to be used in gnark circuit)
+type Ciphertext struct {
+	C1, C2 twistededwards.Point
+}
+
+// Encrypt returns the encryption of msg under pubKey with the randomness
+func Encrypt(curve twistededwards.Curve, pubKey PublicKey, msg, randomness frontend.Variable) Ciphertext {
+	base := twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+
+	// ([r]G, [m]G + [r]A)
+	return Ciphertext{
+		C1: curve.ScalarMul(base, randomness),
+		C2: curve.DoubleBaseScalarMul(base, pubKey.A, msg, randomness),
+	}
+}
+
+// Add returns the sum of two ciphertexts, an encryption of the sum of their
+// messages
+func Add(curve twistededwards.Curve, c1, c2 Ciphertext) Ciphertext {
+	return Ciphertext{
+		C1: curve.Add(c1.C1, c2.C1),
+		C2: curve.Add(c1.C2, c2.C2),
+	}
+}
+
+// VerifyDecryption checks that ct decrypts to msg under the private key
+// matching pubKey: [privateKey]G = A and C₂ = [msg]G + [privateKey]C₁. The
+// private key is meant to be a secret input of the circuit.
+func VerifyDecryption(curve twistededwards.Curve, pubKey PublicKey, ct Ciphertext, msg, privateKey frontend.Variable) {
+	api := curve.API()
+
+	base := twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+
+	// [s]G = A
+	A := curve.ScalarMul(base, privateKey)
+	api.AssertIsEqual(A.X, pubKey.A.X)
+	api.AssertIsEqual(A.Y, pubKey.A.Y)
+
+	// [m]G + [s]C₁ = C₂
+	C2 := curve.DoubleBaseScalarMul(base, ct.C1, msg, privateKey)
+	api.AssertIsEqual(C2.X, ct.C2.X)
+	api.AssertIsEqual(C2.Y, ct.C2.Y)
+}
+
+// Assign sets the in-circuit public key from a native one
+func (p *PublicKey) Assign(pk NativePublicKey) {
+	p.A.X = pk.A[0]
+	p.A.Y = pk.A[1]
+}
+
+// Assign sets the in-circuit ciphertext from a native one
+func (c *Ciphertext) Assign(ct NativeCiphertext) {
+	c.C1.X, c.C1.Y = ct.C1[0], ct.C1[1]
+	c.C2.X, c.C2.Y = ct.C2[0], ct.C2[1]
+}
@@ -0,0 +1,134 @@
+package elgamal
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+var curves = []tedwards.ID{tedwards.BN254, tedwards.BLS12_377, tedwards.BLS12_381, tedwards.BLS12_381_BANDERSNATCH, tedwards.BW6_761, tedwards.BW6_633, tedwards.BLS24_315}
+
+const nbVotes = 3
+
+// votingCircuit checks that each ballot encrypts a vote in {0, 1}, and that the
+// sum of the ballots decrypts to the tally
+type votingCircuit struct {
+	curveID    tedwards.ID
+	PublicKey  PublicKey           `gnark:",public"`
+	Ballots    [nbVotes]Ciphertext `gnark:",public"`
+	Tally      frontend.Variable   `gnark:",public"`
+	Votes      [nbVotes]frontend.Variable
+	Randomness [nbVotes]frontend.Variable
+	PrivateKey frontend.Variable
+}
+
+func (circuit *votingCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+
+	var sum Ciphertext
+	for i := range circuit.Votes {
+		api.AssertIsBoolean(circuit.Votes[i])
+		ct := Encrypt(curve, circuit.PublicKey, circuit.Votes[i], circuit.Randomness[i])
+		api.AssertIsEqual(ct.C1.X, circuit.Ballots[i].C1.X)
+		api.AssertIsEqual(ct.C1.Y, circuit.Ballots[i].C1.Y)
+		api.AssertIsEqual(ct.C2.X, circuit.Ballots[i].C2.X)
+		api.AssertIsEqual(ct.C2.Y, circuit.Ballots[i].C2.Y)
+
+		if i == 0 {
+			sum = circuit.Ballots[i]
+		} else {
+			sum = Add(curve, sum, circuit.Ballots[i])
+		}
+	}
+
+	VerifyDecryption(curve, circuit.PublicKey, sum, circuit.Tally, circuit.PrivateKey)
+
+	return nil
+}
+
+func TestVoting(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	circuits := make([]votingCircuit, len(curves))
+	for i, id := range curves {
+		snarkCurve, err := twistededwards.GetSnarkCurve(id)
+		assert.NoError(err)
+
+		sk, err := GenerateKey(id, rand.Reader)
+		assert.NoError(err)
+
+		var witness votingCircuit
+		witness.PublicKey.Assign(sk.PublicKey)
+		witness.PrivateKey = sk.Scalar()
+
+		votes := []int64{1, 0, 1}
+		var tally NativeCiphertext
+		for j := range votes {
+			r, err := rand.Int(rand.Reader, snarkCurve.Info().Fr.Modulus())
+			assert.NoError(err)
+			ct, err := sk.PublicKey.Encrypt(big.NewInt(votes[j]), r)
+			assert.NoError(err)
+			witness.Votes[j] = votes[j]
+			witness.Randomness[j] = r
+			witness.Ballots[j].Assign(ct)
+
+			if j == 0 {
+				tally = ct
+			} else {
+				tally, err = sk.PublicKey.Add(tally, ct)
+				assert.NoError(err)
+			}
+		}
+
+		// native decryption of the homomorphic tally
+		m, err := sk.Decrypt(tally, nbVotes)
+		assert.NoError(err)
+		assert.Equal(int64(2), m.Int64())
+		witness.Tally = m
+
+		circuits[i].curveID = id
+		assert.SolvingSucceeded(&circuits[i], &witness, test.WithCurves(snarkCurve))
+
+		// wrong tally
+		witness.Tally = 1
+		assert.SolvingFailed(&circuits[i], &witness, test.WithCurves(snarkCurve))
+
+		// invalid vote
+		witness.Tally = m
+		witness.Votes[1] = 2
+		ct, err := sk.PublicKey.Encrypt(big.NewInt(2), witness.Randomness[1].(*big.Int))
+		assert.NoError(err)
+		witness.Ballots[1].Assign(ct)
+		assert.SolvingFailed(&circuits[i], &witness, test.WithCurves(snarkCurve))
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	sk, err := GenerateKey(tedwards.BN254, rand.Reader)
+	assert.NoError(err)
+	ct, err := sk.PublicKey.Encrypt(big.NewInt(42), big.NewInt(7))
+	assert.NoError(err)
+
+	m, err := sk.Decrypt(ct, 100)
+	assert.NoError(err)
+	assert.Equal(int64(42), m.Int64())
+
+	_, err = sk.Decrypt(ct, 41)
+	assert.Error(err)
+
+	// another key
+	other, err := GenerateKey(tedwards.BN254, rand.Reader)
+	assert.NoError(err)
+	_, err = other.Decrypt(ct, 100)
+	assert.Error(err)
+}
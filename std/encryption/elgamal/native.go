@@ -0,0 +1,126 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elgamal
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// NativePublicKey is an ElGamal public key, out of circuit
+type NativePublicKey struct {
+	ID tedwards.ID
+	A  [2]*big.Int
+}
+
+// PrivateKey is an ElGamal private key, out of circuit
+type PrivateKey struct {
+	PublicKey NativePublicKey
+	scalar    *big.Int
+}
+
+// NativeCiphertext is an ElGamal ciphertext, out of circuit
+type NativeCiphertext struct {
+	C1, C2 [2]*big.Int
+}
+
+// GenerateKey returns a new private key on the twisted Edwards curve id, the
+// randomness being read from r
+func GenerateKey(id tedwards.ID, r io.Reader) (*PrivateKey, error) {
+	curve, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// s in [1, order)
+	max := new(big.Int).Sub(curve.Params().Order, big.NewInt(1))
+	s, err := rand.Int(r, max)
+	if err != nil {
+		return nil, err
+	}
+	s.Add(s, big.NewInt(1))
+
+	return &PrivateKey{
+		PublicKey: NativePublicKey{ID: id, A: curve.ScalarMul(curve.Params().Base, s)},
+		scalar:    s,
+	}, nil
+}
+
+// Scalar returns the secret scalar of the private key, to be assigned to the
+// private key input of VerifyDecryption
+func (sk *PrivateKey) Scalar() *big.Int {
+	return new(big.Int).Set(sk.scalar)
+}
+
+// Encrypt returns the encryption of msg with the randomness, as Encrypt in a
+// circuit: msg and randomness are reduced modulo the snark field.
+func (pk *NativePublicKey) Encrypt(msg, randomness *big.Int) (NativeCiphertext, error) {
+	curve, err := twistededwards.NewNativeCurve(pk.ID)
+	if err != nil {
+		return NativeCiphertext{}, err
+	}
+	var m, r big.Int
+	m.Mod(msg, curve.Modulus())
+	r.Mod(randomness, curve.Modulus())
+
+	base := curve.Params().Base
+	return NativeCiphertext{
+		C1: curve.ScalarMul(base, &r),
+		C2: curve.Add(curve.ScalarMul(base, &m), curve.ScalarMul(pk.A, &r)),
+	}, nil
+}
+
+// Add returns the sum of two ciphertexts on the curve of the key, an
+// encryption of the sum of their messages
+func (pk *NativePublicKey) Add(c1, c2 NativeCiphertext) (NativeCiphertext, error) {
+	curve, err := twistededwards.NewNativeCurve(pk.ID)
+	if err != nil {
+		return NativeCiphertext{}, err
+	}
+	return NativeCiphertext{
+		C1: curve.Add(c1.C1, c2.C1),
+		C2: curve.Add(c1.C2, c2.C2),
+	}, nil
+}
+
+// Decrypt returns the message m of ct, searched in [0, max]: the decryption
+// gives [m]G, and m is found by trying all the candidates.
+func (sk *PrivateKey) Decrypt(ct NativeCiphertext, max uint64) (*big.Int, error) {
+	curve, err := twistededwards.NewNativeCurve(sk.PublicKey.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// [m]G = C₂ - [s]C₁
+	M := curve.Add(ct.C2, curve.Neg(curve.ScalarMul(ct.C1, sk.scalar)))
+
+	acc := [2]*big.Int{big.NewInt(0), big.NewInt(1)}
+	for m := uint64(0); ; m++ {
+		if acc[0].Cmp(M[0]) == 0 && acc[1].Cmp(M[1]) == 0 {
+			return new(big.Int).SetUint64(m), nil
+		}
+		if m == max {
+			return nil, errors.New("message not found")
+		}
+		acc = curve.Add(acc, curve.Params().Base)
+	}
+}
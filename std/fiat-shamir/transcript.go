@@ -93,6 +93,10 @@ func (t *Transcript) Bind(challengeID string, values []frontend.Variable) error
 // The resulting variable is:
 // * H(name ∥ previous_challenge ∥ binded_values...) if the challenge is not the first one
 // * H(name ∥ binded_values... ) if it's is the first challenge
+//
+// If the hash function is a hash.BinaryHasher, the binded values must be bytes,
+// and the challenges are the same as the ones of gnark-crypto's fiat-shamir
+// package using the same hash function.
 func (t *Transcript) ComputeChallenge(challengeID string) (frontend.Variable, error) {
 
 	challenge, ok := t.challenges[challengeID]
@@ -117,7 +121,12 @@ func (t *Transcript) ComputeChallenge(challengeID string) (frontend.Variable, er
 		if t.previous == nil || (t.previous.position != challenge.position-1) {
 			return nil, errPreviousChallengeNotComputed
 		}
-		t.h.Write(t.previous.value)
+		if h, ok := t.h.(hash.BinaryHasher); ok {
+			// the previous challenge is written as bytes, as in gnark-crypto
+			t.h.Write(t.toBytes(t.previous.value, h.Size())...)
+		} else {
+			t.h.Write(t.previous.value)
+		}
 	}
 
 	// write the binded values in the order they were added
@@ -135,3 +144,13 @@ func (t *Transcript) ComputeChallenge(challengeID string) (frontend.Variable, er
 	return challenge.value, nil
 
 }
+
+// toBytes returns the size big endian bytes of v
+func (t *Transcript) toBytes(v frontend.Variable, size int) []frontend.Variable {
+	bits := t.api.ToBinary(v, 8*size)
+	res := make([]frontend.Variable, size)
+	for i := range res {
+		res[size-1-i] = t.api.FromBinary(bits[8*i : 8*(i+1)]...)
+	}
+	return res
+}
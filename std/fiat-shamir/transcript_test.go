@@ -17,15 +17,18 @@ limitations under the License.
 package fiatshamir
 
 import (
+	sha256native "crypto/sha256"
 	"math/big"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
 	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/scs"
 	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash/sha256"
 	"github.com/consensys/gnark/test"
 )
 
@@ -160,3 +163,47 @@ func BenchmarkCompile(b *testing.B) {
 	}
 	b.Log(ccs.GetNbConstraints())
 }
+
+type sha256Circuit struct {
+	Bindings   [2][3]frontend.Variable
+	Challenges [2]frontend.Variable `gnark:",public"`
+}
+
+func (circuit *sha256Circuit) Define(api frontend.API) error {
+	ts := NewTranscript(api, sha256.New(api), "alpha", "beta")
+	if err := ts.Bind("alpha", circuit.Bindings[0][:]); err != nil {
+		return err
+	}
+	if err := ts.Bind("beta", circuit.Bindings[1][:]); err != nil {
+		return err
+	}
+	for i, id := range []string{"alpha", "beta"} {
+		c, err := ts.ComputeChallenge(id)
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(c, circuit.Challenges[i])
+	}
+	return nil
+}
+
+func TestFiatShamirSHA256(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	ts := fiatshamir.NewTranscript(sha256native.New(), "alpha", "beta")
+	var witness sha256Circuit
+	for i, id := range []string{"alpha", "beta"} {
+		for j := 0; j < 3; j++ {
+			b := byte(10*i + j)
+			assert.NoError(ts.Bind(id, []byte{b}))
+			witness.Bindings[i][j] = b
+		}
+	}
+	for i, id := range []string{"alpha", "beta"} {
+		c, err := ts.ComputeChallenge(id)
+		assert.NoError(err)
+		witness.Challenges[i] = c
+	}
+
+	assert.SolvingSucceeded(&sha256Circuit{}, &witness, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+}
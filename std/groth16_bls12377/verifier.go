@@ -18,14 +18,22 @@ limitations under the License.
 package groth16_bls12377
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
 	"reflect"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
 	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/frontend"
 	groth16_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/groth16"
+	bls12377witness "github.com/consensys/gnark/internal/backend/bls12-377/witness"
 	"github.com/consensys/gnark/std/algebra/fields_bls12377"
 	"github.com/consensys/gnark/std/algebra/sw_bls12377"
+	"github.com/consensys/gnark/std/hash/mimc"
 )
 
 // Proof represents a Groth16 proof
@@ -61,24 +69,234 @@ func Verify(api frontend.API, vk VerifyingKey, proof Proof, publicInputs []front
 	}
 
 	// compute kSum = Σx.[Kvk(t)]1
+	kSum := computeKSum(api, vk.G1.K, publicInputs)
+
+	// compute e(Σx.[Kvk(t)]1, -[γ]2) * e(Krs,δ) * e(Ar,Bs)
+	ml, _ := sw_bls12377.MillerLoop(api, []sw_bls12377.G1Affine{kSum, proof.Krs, proof.Ar}, []sw_bls12377.G2Affine{vk.G2.GammaNeg, vk.G2.DeltaNeg, proof.Bs})
+	pairing := sw_bls12377.FinalExponentiation(api, ml)
+
+	// vk.E must be equal to pairing
+	vk.E.AssertIsEqual(api, pairing)
+}
+
+// FixedVerifyingKey is a Groth16 verifying key known when the circuit is
+// compiled. Its values are constants of the circuit, and the Miller loops in
+// -[γ]2 and -[δ]2 use precomputed lines. It must be built in the circuit
+// definition, or stored in an unexported field of the circuit.
+type FixedVerifyingKey struct {
+	// e(α, β)
+	E fields_bls12377.E12
+
+	// lines of the Miller loops in -[γ]2, -[δ]2
+	G2 struct {
+		GammaNeg, DeltaNeg sw_bls12377.PrecomputedLines
+	}
+
+	// [Kvk]1
+	G1 struct {
+		K []sw_bls12377.G1Affine // The indexes correspond to the public wires
+	}
+}
+
+// VerifyFixed is Verify for a verifying key fixed in the circuit. Only the
+// Miller loop in the proof's Bs is computed from a variable G2 point.
+// publicInputs do NOT contain the ONE_WIRE
+func VerifyFixed(api frontend.API, vk FixedVerifyingKey, proof Proof, publicInputs []frontend.Variable) error {
+	if len(vk.G1.K) != len(publicInputs)+1 {
+		return fmt.Errorf("expected %d public inputs, got %d", len(vk.G1.K)-1, len(publicInputs))
+	}
+
+	// compute kSum = Σx.[Kvk(t)]1
+	kSum := computeKSum(api, vk.G1.K, publicInputs)
+
+	// compute e(Σx.[Kvk(t)]1, -[γ]2) * e(Krs,δ) * e(Ar,Bs)
+	ml, err := sw_bls12377.MillerLoopFixedQ(api, []sw_bls12377.G1Affine{kSum, proof.Krs}, []sw_bls12377.PrecomputedLines{vk.G2.GammaNeg, vk.G2.DeltaNeg})
+	if err != nil {
+		return err
+	}
+	mlBs, err := sw_bls12377.MillerLoop(api, []sw_bls12377.G1Affine{proof.Ar}, []sw_bls12377.G2Affine{proof.Bs})
+	if err != nil {
+		return err
+	}
+	ml.Mul(api, ml, mlBs)
+	pairing := sw_bls12377.FinalExponentiation(api, ml)
+
+	// vk.E must be equal to pairing
+	vk.E.AssertIsEqual(api, pairing)
+
+	return nil
+}
+
+// BatchVerify verifies several Groth16 proofs with a single multi Miller loop
+// and a single final exponentiation. vks holds either one VerifyingKey shared
+// by all the proofs, or one VerifyingKey per proof. publicInputs[i] are the
+// public inputs of proofs[i] and do NOT contain the ONE_WIRE.
+//
+// The pairing equations are combined with random coefficients r₀ = 1, r₁, ...
+// derived from a MiMC hash of the inputs:
+//
+//	∏ᵢ e(rᵢ·Arᵢ, Bsᵢ)·e(rᵢ·Σx.[Kvk(t)]1ᵢ, -[γ]2ᵢ)·e(rᵢ·Krsᵢ, -[δ]2ᵢ) == ∏ᵢ e(α, β)ᵢ^rᵢ
+//
+// With a shared VerifyingKey, the terms in γ and δ are merged in a single pair
+// each, and the right-hand side is e(α, β)^Σrᵢ.
+func BatchVerify(api frontend.API, vks []VerifyingKey, proofs []Proof, publicInputs [][]frontend.Variable) error {
+	if len(proofs) == 0 {
+		return errors.New("no proof to verify")
+	}
+	if len(publicInputs) != len(proofs) {
+		return errors.New("the number of proofs and public inputs must be the same")
+	}
+	if len(vks) != 1 && len(vks) != len(proofs) {
+		return errors.New("expected one verifying key, or one per proof")
+	}
+	for i := range vks {
+		if len(vks[i].G1.K) == 0 {
+			return errors.New("inner verifying key needs at least one point; VerifyingKey.G1 must be initialized before compiling circuit")
+		}
+	}
+	shared := len(vks) == 1
+	vk := func(i int) VerifyingKey {
+		if shared {
+			return vks[0]
+		}
+		return vks[i]
+	}
+
+	r, err := deriveRandomCoefficients(api, vks, proofs, publicInputs)
+	if err != nil {
+		return err
+	}
+
+	P := make([]sw_bls12377.G1Affine, 0, 3*len(proofs))
+	Q := make([]sw_bls12377.G2Affine, 0, 3*len(proofs))
+	var kSumAcc, krsAcc sw_bls12377.G1Affine
+	for i := range proofs {
+		kSum := computeKSum(api, vk(i).G1.K, publicInputs[i])
+		ar, krs := proofs[i].Ar, proofs[i].Krs
+		if i > 0 {
+			kSum.ScalarMul(api, kSum, r[i-1])
+			ar.ScalarMul(api, ar, r[i-1])
+			krs.ScalarMul(api, krs, r[i-1])
+		}
+		P = append(P, ar)
+		Q = append(Q, proofs[i].Bs)
+		if !shared {
+			P = append(P, kSum, krs)
+			Q = append(Q, vks[i].G2.GammaNeg, vks[i].G2.DeltaNeg)
+			continue
+		}
+		if i == 0 {
+			kSumAcc, krsAcc = kSum, krs
+			continue
+		}
+		kSumAcc.AddAssign(api, kSum)
+		krsAcc.AddAssign(api, krs)
+	}
+	if shared {
+		P = append(P, kSumAcc, krsAcc)
+		Q = append(Q, vks[0].G2.GammaNeg, vks[0].G2.DeltaNeg)
+	}
+
+	ml, err := sw_bls12377.MillerLoop(api, P, Q)
+	if err != nil {
+		return err
+	}
+	pairing := sw_bls12377.FinalExponentiation(api, ml)
+
+	// ∏ᵢ e(α, β)ᵢ^rᵢ
+	var expected fields_bls12377.E12
+	if shared {
+		sum := frontend.Variable(1)
+		for i := range r {
+			sum = api.Add(sum, r[i])
+		}
+		nbBits := nbRandomBits + bits.Len(uint(len(proofs)))
+		expected = exp(api, vks[0].E, api.ToBinary(sum, nbBits))
+	} else {
+		expected = vks[0].E
+		for i := 1; i < len(proofs); i++ {
+			e := exp(api, vks[i].E, api.ToBinary(r[i-1], nbRandomBits))
+			expected.Mul(api, expected, e)
+		}
+	}
+	expected.AssertIsEqual(api, pairing)
+
+	return nil
+}
+
+// nbRandomBits is the number of bits of the random coefficients of BatchVerify
+const nbRandomBits = 128
+
+// computeKSum returns Σx.[Kvk(t)]1, assuming ONE_WIRE is at position 0
+func computeKSum(api frontend.API, K []sw_bls12377.G1Affine, publicInputs []frontend.Variable) sw_bls12377.G1Affine {
 	var kSum sw_bls12377.G1Affine
 
 	// kSum = Kvk[0] (assumes ONE_WIRE is at position 0)
-	kSum.X = vk.G1.K[0].X
-	kSum.Y = vk.G1.K[0].Y
+	kSum.X = K[0].X
+	kSum.Y = K[0].Y
 
 	for k, v := range publicInputs {
 		var ki sw_bls12377.G1Affine
-		ki.ScalarMul(api, vk.G1.K[k+1], v)
+		ki.ScalarMul(api, K[k+1], v)
 		kSum.AddAssign(api, ki)
 	}
+	return kSum
+}
 
-	// compute e(Σx.[Kvk(t)]1, -[γ]2) * e(Krs,δ) * e(Ar,Bs)
-	ml, _ := sw_bls12377.MillerLoop(api, []sw_bls12377.G1Affine{kSum, proof.Krs, proof.Ar}, []sw_bls12377.G2Affine{vk.G2.GammaNeg, vk.G2.DeltaNeg, proof.Bs})
-	pairing := sw_bls12377.FinalExponentiation(api, ml)
+// deriveRandomCoefficients returns the nbRandomBits bits coefficients r₁, ..., rₙ₋₁ (r₀ = 1)
+// used to combine the pairing equations
+func deriveRandomCoefficients(api frontend.API, vks []VerifyingKey, proofs []Proof, publicInputs [][]frontend.Variable) ([]frontend.Variable, error) {
+	if len(proofs) == 1 {
+		return nil, nil
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return nil, err
+	}
+	writeE2 := func(e fields_bls12377.E2) {
+		h.Write(e.A0, e.A1)
+	}
+	for i := range vks {
+		for _, e := range []fields_bls12377.E6{vks[i].E.C0, vks[i].E.C1} {
+			writeE2(e.B0)
+			writeE2(e.B1)
+			writeE2(e.B2)
+		}
+		for _, q := range []sw_bls12377.G2Affine{vks[i].G2.GammaNeg, vks[i].G2.DeltaNeg} {
+			writeE2(q.X)
+			writeE2(q.Y)
+		}
+		for _, k := range vks[i].G1.K {
+			h.Write(k.X, k.Y)
+		}
+	}
+	for i := range proofs {
+		h.Write(proofs[i].Ar.X, proofs[i].Ar.Y, proofs[i].Krs.X, proofs[i].Krs.Y)
+		writeE2(proofs[i].Bs.X)
+		writeE2(proofs[i].Bs.Y)
+		h.Write(publicInputs[i]...)
+	}
+	seed := h.Sum()
 
-	// vk.E must be equal to pairing
-	vk.E.AssertIsEqual(api, pairing)
+	res := make([]frontend.Variable, len(proofs)-1)
+	for i := range res {
+		h.Reset()
+		h.Write(seed, i)
+		res[i] = api.FromBinary(api.ToBinary(h.Sum())[:nbRandomBits]...)
+	}
+	return res, nil
+}
+
+// exp returns e^s, s being given by its bits in little endian
+func exp(api frontend.API, e fields_bls12377.E12, sBits []frontend.Variable) fields_bls12377.E12 {
+	var res, tmp fields_bls12377.E12
+	res.SetOne()
+	for i := len(sBits) - 1; i >= 0; i-- {
+		res.Square(api, res)
+		tmp.Mul(api, res, e)
+		res.Select(api, sBits[i], tmp, res)
+	}
+	return res
 }
 
 // Assign values to the "in-circuit" VerifyingKey from a "out-of-circuit" VerifyingKey
@@ -104,3 +322,65 @@ func (vk *VerifyingKey) Assign(_ovk groth16.VerifyingKey) {
 	vk.G2.DeltaNeg.Assign(&deltaNeg)
 	vk.G2.GammaNeg.Assign(&gammaNeg)
 }
+
+// NewFixedVerifyingKey returns the "in-circuit" FixedVerifyingKey of a
+// "out-of-circuit" VerifyingKey
+func NewFixedVerifyingKey(_ovk groth16.VerifyingKey) FixedVerifyingKey {
+	ovk, ok := _ovk.(*groth16_bls12377.VerifyingKey)
+	if !ok {
+		panic("expected *groth16_bls12377.VerifyingKey, got " + reflect.TypeOf(_ovk).String())
+	}
+
+	var vk FixedVerifyingKey
+	e, err := bls12377.Pair([]bls12377.G1Affine{ovk.G1.Alpha}, []bls12377.G2Affine{ovk.G2.Beta})
+	if err != nil {
+		panic(err)
+	}
+	vk.E.Assign(&e)
+
+	vk.G1.K = make([]sw_bls12377.G1Affine, len(ovk.G1.K))
+	for i := 0; i < len(ovk.G1.K); i++ {
+		vk.G1.K[i].Assign(&ovk.G1.K[i])
+	}
+	var deltaNeg, gammaNeg bls12377.G2Affine
+	deltaNeg.Neg(&ovk.G2.Delta)
+	gammaNeg.Neg(&ovk.G2.Gamma)
+	vk.G2.DeltaNeg = sw_bls12377.PrecomputeLines(deltaNeg)
+	vk.G2.GammaNeg = sw_bls12377.PrecomputeLines(gammaNeg)
+	return vk
+}
+
+// Assign values to the "in-circuit" Proof from a "out-of-circuit" Proof
+func (proof *Proof) Assign(_oproof groth16.Proof) {
+	oproof, ok := _oproof.(*groth16_bls12377.Proof)
+	if !ok {
+		panic("expected *groth16_bls12377.Proof, got " + reflect.TypeOf(_oproof).String())
+	}
+	proof.Ar.Assign(&oproof.Ar)
+	proof.Krs.Assign(&oproof.Krs)
+	proof.Bs.Assign(&oproof.Bs)
+}
+
+// PublicInputsFromWitness returns the "in-circuit" public inputs of Verify from
+// a "out-of-circuit" witness of the inner circuit. If w is a full witness with
+// a schema, only its public part is kept.
+func PublicInputsFromWitness(w *witness.Witness) ([]frontend.Variable, error) {
+	if w.CurveID != ecc.BLS12_377 {
+		return nil, fmt.Errorf("expected a witness on %s, got %s", ecc.BLS12_377, w.CurveID)
+	}
+	v, ok := w.Vector.(*bls12377witness.Witness)
+	if !ok {
+		return nil, fmt.Errorf("expected *witness.Witness, got %T", w.Vector)
+	}
+	values := *v
+	if w.Schema != nil && len(values) > w.Schema.NbPublic {
+		values = values[:w.Schema.NbPublic]
+	}
+	res := make([]frontend.Variable, len(values))
+	for i := range values {
+		var b big.Int
+		values[i].ToBigIntRegular(&b)
+		res[i] = &b
+	}
+	return res, nil
+}
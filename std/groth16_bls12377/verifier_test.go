@@ -17,16 +17,20 @@ limitations under the License.
 package groth16_bls12377
 
 import (
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 	backend_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/cs"
 	groth16_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/groth16"
 	"github.com/consensys/gnark/internal/backend/bls12-377/witness"
+	"github.com/consensys/gnark/internal/groth16test"
 	"github.com/consensys/gnark/std/algebra/sw_bls12377"
 	"github.com/consensys/gnark/std/hash/mimc"
 	"github.com/consensys/gnark/test"
@@ -139,6 +143,191 @@ func TestVerifier(t *testing.T) {
 	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_761))
 }
 
+// generateBls12377InnerProofs returns nbProofs proofs of the mimc circuit, sharing
+// the same verifying key
+func generateBls12377InnerProofs(t *testing.T, nbProofs int) (groth16.VerifyingKey, []*groth16_bls12377.Proof) {
+	ccs, err := frontend.Compile(ecc.BLS12_377, r1cs.NewBuilder, &mimcCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness, err := frontend.NewWitness(&mimcCircuit{PreImage: preImage, Hash: publicHash}, ecc.BLS12_377)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proofs := make([]*groth16_bls12377.Proof, nbProofs)
+	for i := range proofs {
+		proof, err := groth16.Prove(ccs, pk, witness)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proofs[i] = proof.(*groth16_bls12377.Proof)
+	}
+	return vk, proofs
+}
+
+type batchVerifierCircuit struct {
+	InnerProofs [2]Proof
+	InnerVks    []VerifyingKey
+	Hashes      [2]frontend.Variable
+}
+
+func (circuit *batchVerifierCircuit) Define(api frontend.API) error {
+	publicInputs := [][]frontend.Variable{{circuit.Hashes[0]}, {circuit.Hashes[1]}}
+	return BatchVerify(api, circuit.InnerVks, circuit.InnerProofs[:], publicInputs)
+}
+
+func TestBatchVerifier(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping batch verification in short mode")
+	}
+	assert := test.NewAssert(t)
+
+	// two proofs under the same verifying key, and a proof under another one
+	vk0, proofs0 := generateBls12377InnerProofs(t, 2)
+	vk1, proofs1 := generateBls12377InnerProofs(t, 1)
+
+	for _, tc := range []struct {
+		name   string
+		vks    []groth16.VerifyingKey
+		proofs []*groth16_bls12377.Proof
+	}{
+		{"shared", []groth16.VerifyingKey{vk0}, proofs0},
+		{"distinct", []groth16.VerifyingKey{vk0, vk1}, []*groth16_bls12377.Proof{proofs0[0], proofs1[0]}},
+	} {
+		assert.Run(func(assert *test.Assert) {
+			var circuit, witness batchVerifierCircuit
+			circuit.InnerVks = make([]VerifyingKey, len(tc.vks))
+			witness.InnerVks = make([]VerifyingKey, len(tc.vks))
+			for i := range tc.vks {
+				witness.InnerVks[i].Assign(tc.vks[i])
+				circuit.InnerVks[i].G1.K = make([]sw_bls12377.G1Affine, len(witness.InnerVks[i].G1.K))
+			}
+			for i := range tc.proofs {
+				witness.InnerProofs[i].Assign(tc.proofs[i])
+				witness.Hashes[i] = publicHash
+			}
+			assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+
+			// wrong public input for the second proof
+			witness.Hashes[1] = preImage
+			assert.SolvingFailed(&circuit, &witness, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+		}, tc.name)
+	}
+}
+
+// outerCircuit verifies an inner proof whose public inputs are the public
+// inputs of the outer circuit
+type outerCircuit struct {
+	InnerProof   Proof
+	InnerVk      VerifyingKey
+	PublicInputs []frontend.Variable `gnark:",public"`
+}
+
+func (circuit *outerCircuit) Define(api frontend.API) error {
+	Verify(api, circuit.InnerVk, circuit.InnerProof, circuit.PublicInputs)
+	return nil
+}
+
+// newOuterAssignment proves innerAssignment for innerCircuit, and returns the
+// outer circuit sized for the inner one with its full assignment
+func newOuterAssignment(t *testing.T, innerCircuit, innerAssignment frontend.Circuit) (circuit, assignment *outerCircuit) {
+	proof, vk, publicWitness, err := groth16test.Prove(ecc.BLS12_377, innerCircuit, innerAssignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment = &outerCircuit{}
+	assignment.InnerProof.Assign(proof)
+	assignment.InnerVk.Assign(vk)
+	if assignment.PublicInputs, err = PublicInputsFromWitness(publicWitness); err != nil {
+		t.Fatal(err)
+	}
+
+	circuit = &outerCircuit{PublicInputs: make([]frontend.Variable, len(assignment.PublicInputs))}
+	circuit.InnerVk.G1.K = make([]sw_bls12377.G1Affine, len(assignment.InnerVk.G1.K))
+	return circuit, assignment
+}
+
+func TestVerifierFromWitness(t *testing.T) {
+	circuit, assignment := newOuterAssignment(t, &mimcCircuit{}, &mimcCircuit{PreImage: preImage, Hash: publicHash})
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(circuit, assignment, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+
+	// wrong public input
+	assignment.PublicInputs[0] = preImage
+	assert.SolvingFailed(circuit, assignment, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+}
+
+// fixedVerifierCircuit verifies an inner proof under a verifying key fixed in
+// the circuit
+type fixedVerifierCircuit struct {
+	InnerProof Proof
+	Hash       frontend.Variable `gnark:",public"`
+	innerVk    FixedVerifyingKey
+}
+
+func (circuit *fixedVerifierCircuit) Define(api frontend.API) error {
+	return VerifyFixed(api, circuit.innerVk, circuit.InnerProof, []frontend.Variable{circuit.Hash})
+}
+
+func TestVerifierFixed(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BLS12_377, r1cs.NewBuilder, &mimcCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness, err := frontend.NewWitness(&mimcCircuit{PreImage: preImage, Hash: publicHash}, ecc.BLS12_377)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(ccs, pk, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var assignment fixedVerifierCircuit
+	assignment.InnerProof.Assign(proof)
+	assignment.Hash = publicHash
+
+	assert := test.NewAssert(t)
+	circuit := fixedVerifierCircuit{innerVk: NewFixedVerifyingKey(vk)}
+	assert.SolvingSucceeded(&circuit, &assignment, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+
+	// lines of a wrong fixed -[δ]2
+	_, _, _, g2 := bls12377.Generators()
+	wrongVk := circuit.innerVk
+	wrongVk.G2.DeltaNeg = sw_bls12377.PrecomputeLines(g2)
+	assert.SolvingFailed(&fixedVerifierCircuit{innerVk: wrongVk}, &assignment, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+
+	// wrong public input
+	assignment.Hash = preImage
+	assert.SolvingFailed(&circuit, &assignment, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+}
+
+func TestPublicInputsFromWitness(t *testing.T) {
+	assert := test.NewAssert(t)
+	w, err := frontend.NewWitness(&mimcCircuit{PreImage: preImage, Hash: publicHash}, ecc.BLS12_377)
+	assert.NoError(err)
+
+	// the secret part of a full witness is dropped
+	publicInputs, err := PublicInputsFromWitness(w)
+	assert.NoError(err)
+	assert.Equal(1, len(publicInputs))
+	assert.Equal(publicHash, publicInputs[0].(*big.Int).String())
+
+	w, err = frontend.NewWitness(&mimcCircuit{PreImage: preImage, Hash: publicHash}, ecc.BN254)
+	assert.NoError(err)
+	_, err = PublicInputsFromWitness(w)
+	assert.Error(err)
+}
+
 func BenchmarkCompile(b *testing.B) {
 	// get the data
 	var innerVk groth16_bls12377.VerifyingKey
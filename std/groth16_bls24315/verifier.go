@@ -18,12 +18,17 @@ limitations under the License.
 package groth16_bls24315
 
 import (
+	"fmt"
+	"math/big"
 	"reflect"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
 	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/frontend"
 	groth16_bls24315 "github.com/consensys/gnark/internal/backend/bls24-315/groth16"
+	bls24315witness "github.com/consensys/gnark/internal/backend/bls24-315/witness"
 	"github.com/consensys/gnark/std/algebra/fields_bls24315"
 	"github.com/consensys/gnark/std/algebra/sw_bls24315"
 )
@@ -61,25 +66,79 @@ func Verify(api frontend.API, vk VerifyingKey, proof Proof, publicInputs []front
 	}
 
 	// compute kSum = Σx.[Kvk(t)]1
-	var kSum sw_bls24315.G1Affine
+	kSum := computeKSum(api, vk.G1.K, publicInputs)
 
-	// kSum = Kvk[0] (assumes ONE_WIRE is at position 0)
-	kSum.X = vk.G1.K[0].X
-	kSum.Y = vk.G1.K[0].Y
+	// compute e(Σx.[Kvk(t)]1, -[γ]2) * e(Krs,δ) * e(Ar,Bs)
+	ml, _ := sw_bls24315.MillerLoop(api, []sw_bls24315.G1Affine{kSum, proof.Krs, proof.Ar}, []sw_bls24315.G2Affine{vk.G2.GammaNeg, vk.G2.DeltaNeg, proof.Bs})
+	pairing := sw_bls24315.FinalExponentiation(api, ml)
 
-	for k, v := range publicInputs {
-		var ki sw_bls24315.G1Affine
-		ki.ScalarMul(api, vk.G1.K[k+1], v)
-		kSum.AddAssign(api, ki)
+	// vk.E must be equal to pairing
+	vk.E.AssertIsEqual(api, pairing)
+
+}
+
+// FixedVerifyingKey is a Groth16 verifying key known when the circuit is
+// compiled. Its values are constants of the circuit, and the Miller loops in
+// -[γ]2 and -[δ]2 use precomputed lines. It must be built in the circuit
+// definition, or stored in an unexported field of the circuit.
+type FixedVerifyingKey struct {
+	// e(α, β)
+	E fields_bls24315.E24
+
+	// lines of the Miller loops in -[γ]2, -[δ]2
+	G2 struct {
+		GammaNeg, DeltaNeg sw_bls24315.PrecomputedLines
 	}
 
+	// [Kvk]1
+	G1 struct {
+		K []sw_bls24315.G1Affine // The indexes correspond to the public wires
+	}
+}
+
+// VerifyFixed is Verify for a verifying key fixed in the circuit. Only the
+// Miller loop in the proof's Bs is computed from a variable G2 point.
+// publicInputs do NOT contain the ONE_WIRE
+func VerifyFixed(api frontend.API, vk FixedVerifyingKey, proof Proof, publicInputs []frontend.Variable) error {
+	if len(vk.G1.K) != len(publicInputs)+1 {
+		return fmt.Errorf("expected %d public inputs, got %d", len(vk.G1.K)-1, len(publicInputs))
+	}
+
+	// compute kSum = Σx.[Kvk(t)]1
+	kSum := computeKSum(api, vk.G1.K, publicInputs)
+
 	// compute e(Σx.[Kvk(t)]1, -[γ]2) * e(Krs,δ) * e(Ar,Bs)
-	ml, _ := sw_bls24315.MillerLoop(api, []sw_bls24315.G1Affine{kSum, proof.Krs, proof.Ar}, []sw_bls24315.G2Affine{vk.G2.GammaNeg, vk.G2.DeltaNeg, proof.Bs})
+	ml, err := sw_bls24315.MillerLoopFixedQ(api, []sw_bls24315.G1Affine{kSum, proof.Krs}, []sw_bls24315.PrecomputedLines{vk.G2.GammaNeg, vk.G2.DeltaNeg})
+	if err != nil {
+		return err
+	}
+	mlBs, err := sw_bls24315.MillerLoop(api, []sw_bls24315.G1Affine{proof.Ar}, []sw_bls24315.G2Affine{proof.Bs})
+	if err != nil {
+		return err
+	}
+	ml.Mul(api, ml, mlBs)
 	pairing := sw_bls24315.FinalExponentiation(api, ml)
 
 	// vk.E must be equal to pairing
 	vk.E.AssertIsEqual(api, pairing)
 
+	return nil
+}
+
+// computeKSum returns Σx.[Kvk(t)]1, assuming ONE_WIRE is at position 0
+func computeKSum(api frontend.API, K []sw_bls24315.G1Affine, publicInputs []frontend.Variable) sw_bls24315.G1Affine {
+	var kSum sw_bls24315.G1Affine
+
+	// kSum = Kvk[0] (assumes ONE_WIRE is at position 0)
+	kSum.X = K[0].X
+	kSum.Y = K[0].Y
+
+	for k, v := range publicInputs {
+		var ki sw_bls24315.G1Affine
+		ki.ScalarMul(api, K[k+1], v)
+		kSum.AddAssign(api, ki)
+	}
+	return kSum
 }
 
 // Assign values to the "in-circuit" VerifyingKey from a "out-of-circuit" VerifyingKey
@@ -105,3 +164,65 @@ func (vk *VerifyingKey) Assign(_ovk groth16.VerifyingKey) {
 	vk.G2.DeltaNeg.Assign(&deltaNeg)
 	vk.G2.GammaNeg.Assign(&gammaNeg)
 }
+
+// NewFixedVerifyingKey returns the "in-circuit" FixedVerifyingKey of a
+// "out-of-circuit" VerifyingKey
+func NewFixedVerifyingKey(_ovk groth16.VerifyingKey) FixedVerifyingKey {
+	ovk, ok := _ovk.(*groth16_bls24315.VerifyingKey)
+	if !ok {
+		panic("expected *groth16_bls24315.VerifyingKey, got " + reflect.TypeOf(_ovk).String())
+	}
+
+	var vk FixedVerifyingKey
+	e, err := bls24315.Pair([]bls24315.G1Affine{ovk.G1.Alpha}, []bls24315.G2Affine{ovk.G2.Beta})
+	if err != nil {
+		panic(err)
+	}
+	vk.E.Assign(&e)
+
+	vk.G1.K = make([]sw_bls24315.G1Affine, len(ovk.G1.K))
+	for i := 0; i < len(ovk.G1.K); i++ {
+		vk.G1.K[i].Assign(&ovk.G1.K[i])
+	}
+	var deltaNeg, gammaNeg bls24315.G2Affine
+	deltaNeg.Neg(&ovk.G2.Delta)
+	gammaNeg.Neg(&ovk.G2.Gamma)
+	vk.G2.DeltaNeg = sw_bls24315.PrecomputeLines(deltaNeg)
+	vk.G2.GammaNeg = sw_bls24315.PrecomputeLines(gammaNeg)
+	return vk
+}
+
+// Assign values to the "in-circuit" Proof from a "out-of-circuit" Proof
+func (proof *Proof) Assign(_oproof groth16.Proof) {
+	oproof, ok := _oproof.(*groth16_bls24315.Proof)
+	if !ok {
+		panic("expected *groth16_bls24315.Proof, got " + reflect.TypeOf(_oproof).String())
+	}
+	proof.Ar.Assign(&oproof.Ar)
+	proof.Krs.Assign(&oproof.Krs)
+	proof.Bs.Assign(&oproof.Bs)
+}
+
+// PublicInputsFromWitness returns the "in-circuit" public inputs of Verify from
+// a "out-of-circuit" witness of the inner circuit. If w is a full witness with
+// a schema, only its public part is kept.
+func PublicInputsFromWitness(w *witness.Witness) ([]frontend.Variable, error) {
+	if w.CurveID != ecc.BLS24_315 {
+		return nil, fmt.Errorf("expected a witness on %s, got %s", ecc.BLS24_315, w.CurveID)
+	}
+	v, ok := w.Vector.(*bls24315witness.Witness)
+	if !ok {
+		return nil, fmt.Errorf("expected *witness.Witness, got %T", w.Vector)
+	}
+	values := *v
+	if w.Schema != nil && len(values) > w.Schema.NbPublic {
+		values = values[:w.Schema.NbPublic]
+	}
+	res := make([]frontend.Variable, len(values))
+	for i := range values {
+		var b big.Int
+		values[i].ToBigIntRegular(&b)
+		res[i] = &b
+	}
+	return res, nil
+}
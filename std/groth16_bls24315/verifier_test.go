@@ -17,16 +17,20 @@ limitations under the License.
 package groth16_bls24315
 
 import (
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 	backend_bls24315 "github.com/consensys/gnark/internal/backend/bls24-315/cs"
 	groth16_bls24315 "github.com/consensys/gnark/internal/backend/bls24-315/groth16"
 	"github.com/consensys/gnark/internal/backend/bls24-315/witness"
+	"github.com/consensys/gnark/internal/groth16test"
 	"github.com/consensys/gnark/std/algebra/sw_bls24315"
 	"github.com/consensys/gnark/std/hash/mimc"
 	"github.com/consensys/gnark/test"
@@ -142,6 +146,115 @@ func TestVerifier(t *testing.T) {
 
 }
 
+// outerCircuit verifies an inner proof whose public inputs are the public
+// inputs of the outer circuit
+type outerCircuit struct {
+	InnerProof   Proof
+	InnerVk      VerifyingKey
+	PublicInputs []frontend.Variable `gnark:",public"`
+}
+
+func (circuit *outerCircuit) Define(api frontend.API) error {
+	Verify(api, circuit.InnerVk, circuit.InnerProof, circuit.PublicInputs)
+	return nil
+}
+
+// newOuterAssignment proves innerAssignment for innerCircuit, and returns the
+// outer circuit sized for the inner one with its full assignment
+func newOuterAssignment(t *testing.T, innerCircuit, innerAssignment frontend.Circuit) (circuit, assignment *outerCircuit) {
+	proof, vk, publicWitness, err := groth16test.Prove(ecc.BLS24_315, innerCircuit, innerAssignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment = &outerCircuit{}
+	assignment.InnerProof.Assign(proof)
+	assignment.InnerVk.Assign(vk)
+	if assignment.PublicInputs, err = PublicInputsFromWitness(publicWitness); err != nil {
+		t.Fatal(err)
+	}
+
+	circuit = &outerCircuit{PublicInputs: make([]frontend.Variable, len(assignment.PublicInputs))}
+	circuit.InnerVk.G1.K = make([]sw_bls24315.G1Affine, len(assignment.InnerVk.G1.K))
+	return circuit, assignment
+}
+
+func TestVerifierFromWitness(t *testing.T) {
+	circuit, assignment := newOuterAssignment(t, &mimcCircuit{}, &mimcCircuit{PreImage: preImage, Hash: publicHash})
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(circuit, assignment, test.WithCurves(ecc.BW6_633), test.WithBackends(backend.GROTH16))
+
+	// wrong public input
+	assignment.PublicInputs[0] = preImage
+	assert.SolvingFailed(circuit, assignment, test.WithCurves(ecc.BW6_633), test.WithBackends(backend.GROTH16))
+}
+
+// fixedVerifierCircuit verifies an inner proof under a verifying key fixed in
+// the circuit
+type fixedVerifierCircuit struct {
+	InnerProof Proof
+	Hash       frontend.Variable `gnark:",public"`
+	innerVk    FixedVerifyingKey
+}
+
+func (circuit *fixedVerifierCircuit) Define(api frontend.API) error {
+	return VerifyFixed(api, circuit.innerVk, circuit.InnerProof, []frontend.Variable{circuit.Hash})
+}
+
+func TestVerifierFixed(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BLS24_315, r1cs.NewBuilder, &mimcCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness, err := frontend.NewWitness(&mimcCircuit{PreImage: preImage, Hash: publicHash}, ecc.BLS24_315)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(ccs, pk, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var assignment fixedVerifierCircuit
+	assignment.InnerProof.Assign(proof)
+	assignment.Hash = publicHash
+
+	assert := test.NewAssert(t)
+	circuit := fixedVerifierCircuit{innerVk: NewFixedVerifyingKey(vk)}
+	assert.SolvingSucceeded(&circuit, &assignment, test.WithCurves(ecc.BW6_633), test.WithBackends(backend.GROTH16))
+
+	// lines of a wrong fixed -[δ]2
+	_, _, _, g2 := bls24315.Generators()
+	wrongVk := circuit.innerVk
+	wrongVk.G2.DeltaNeg = sw_bls24315.PrecomputeLines(g2)
+	assert.SolvingFailed(&fixedVerifierCircuit{innerVk: wrongVk}, &assignment, test.WithCurves(ecc.BW6_633), test.WithBackends(backend.GROTH16))
+
+	// wrong public input
+	assignment.Hash = preImage
+	assert.SolvingFailed(&circuit, &assignment, test.WithCurves(ecc.BW6_633), test.WithBackends(backend.GROTH16))
+}
+
+func TestPublicInputsFromWitness(t *testing.T) {
+	assert := test.NewAssert(t)
+	w, err := frontend.NewWitness(&mimcCircuit{PreImage: preImage, Hash: publicHash}, ecc.BLS24_315)
+	assert.NoError(err)
+
+	// the secret part of a full witness is dropped
+	publicInputs, err := PublicInputsFromWitness(w)
+	assert.NoError(err)
+	assert.Equal(1, len(publicInputs))
+	assert.Equal(publicHash, publicInputs[0].(*big.Int).String())
+
+	w, err = frontend.NewWitness(&mimcCircuit{PreImage: preImage, Hash: publicHash}, ecc.BN254)
+	assert.NoError(err)
+	_, err = PublicInputsFromWitness(w)
+	assert.Error(err)
+}
+
 func BenchmarkCompile(b *testing.B) {
 	// get the data
 	var innerVk groth16_bls24315.VerifyingKey
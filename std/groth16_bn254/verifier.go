@@ -0,0 +1,219 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package groth16_bn254 provides a ZKP-circuit function to verify BN254 Groth16
+// proofs inside a circuit defined over any curve, using emulated arithmetic
+// (see std/algebra/emulated/sw_bn254).
+//
+// The public inputs are elements of the scalar field of BN254 given as native
+// variables, hence the scalar field of the outer curve must be at least as
+// large (for instance BN254 itself). The verification costs several millions
+// of constraints.
+package groth16_bn254
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	groth16_bn254 "github.com/consensys/gnark/internal/backend/bn254/groth16"
+	bn254witness "github.com/consensys/gnark/internal/backend/bn254/witness"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bn254"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Proof represents a Groth16 proof
+// Notation follows Figure 4. in DIZK paper https://eprint.iacr.org/2018/691.pdf
+type Proof struct {
+	Ar, Krs sw_bn254.G1Affine
+	Bs      sw_bn254.G2Affine
+}
+
+// VerifyingKey represents a Groth16 verifying key
+// Notation follows Figure 4. in DIZK paper https://eprint.iacr.org/2018/691.pdf
+type VerifyingKey struct {
+	// e(α, β)
+	E fields_bn254.E12
+
+	// -[γ]2, -[δ]2
+	G2 struct {
+		GammaNeg, DeltaNeg sw_bn254.G2Affine
+	}
+
+	// [Kvk]1
+	G1 struct {
+		K []sw_bn254.G1Affine // The indexes correspond to the public wires
+	}
+}
+
+// PlaceholderProof returns a Proof whose limbs are allocated, to be used in a
+// circuit definition
+func PlaceholderProof() Proof {
+	return Proof{
+		Ar:  sw_bn254.G1AffinePlaceholder(),
+		Krs: sw_bn254.G1AffinePlaceholder(),
+		Bs:  sw_bn254.G2AffinePlaceholder(),
+	}
+}
+
+// PlaceholderVerifyingKey returns a VerifyingKey for nbPublicInputs public
+// inputs (ONE_WIRE excluded) whose limbs are allocated, to be used in a circuit
+// definition
+func PlaceholderVerifyingKey(nbPublicInputs int) VerifyingKey {
+	var vk VerifyingKey
+	vk.E = fields_bn254.E12Placeholder()
+	vk.G2.GammaNeg = sw_bn254.G2AffinePlaceholder()
+	vk.G2.DeltaNeg = sw_bn254.G2AffinePlaceholder()
+	vk.G1.K = make([]sw_bn254.G1Affine, nbPublicInputs+1)
+	for i := range vk.G1.K {
+		vk.G1.K[i] = sw_bn254.G1AffinePlaceholder()
+	}
+	return vk
+}
+
+// Verify implements the verification function of Groth16.
+// Notation follows Figure 4. in DIZK paper https://eprint.iacr.org/2018/691.pdf
+// publicInputs do NOT contain the ONE_WIRE
+func Verify(api frontend.API, vk VerifyingKey, proof Proof, publicInputs []frontend.Variable) error {
+	if len(vk.G1.K) == 0 {
+		return errors.New("inner verifying key needs at least one point; VerifyingKey.G1 must be initialized before compiling circuit")
+	}
+	if len(vk.G1.K) != len(publicInputs)+1 {
+		return errors.New("invalid number of public inputs")
+	}
+	fp, err := emulated.NewField(api, emulated.BN254Fp)
+	if err != nil {
+		return err
+	}
+
+	// compute kSum = Σx.[Kvk(t)]1
+	kSum := multiScalarMul(api, fp, vk.G1.K, publicInputs)
+
+	// compute e(Σx.[Kvk(t)]1, -[γ]2) * e(Krs,δ) * e(Ar,Bs)
+	ml, err := sw_bn254.MillerLoop(fp, []sw_bn254.G1Affine{kSum, proof.Krs, proof.Ar}, []sw_bn254.G2Affine{vk.G2.GammaNeg, vk.G2.DeltaNeg, proof.Bs})
+	if err != nil {
+		return err
+	}
+	pairing := sw_bn254.FinalExponentiation(fp, ml)
+
+	// vk.E must be equal to pairing
+	vk.E.AssertIsEqual(fp, pairing)
+
+	return nil
+}
+
+// multiScalarMul returns K[0] + Σ scalars[i]·K[i+1].
+//
+// The scalars are decomposed in bits and the points are accumulated with a
+// shared double-and-add, starting from the generator R of BN254 so that the
+// incomplete affine formulas never meet the point at infinity; 2ⁿ·R is
+// subtracted in the end.
+func multiScalarMul(api frontend.API, fp *emulated.Field, K []sw_bn254.G1Affine, scalars []frontend.Variable) sw_bn254.G1Affine {
+	nbBits := fr.Modulus().BitLen()
+	bits := make([][]frontend.Variable, len(scalars))
+	for i := range scalars {
+		bits[i] = api.ToBinary(scalars[i], nbBits)
+	}
+
+	_, _, g1, _ := bn254.Generators()
+	var offset bn254.G1Affine
+	offset.ScalarMultiplication(&g1, new(big.Int).Lsh(big.NewInt(1), uint(nbBits)))
+	offset.Neg(&offset)
+
+	var acc, tmp sw_bn254.G1Affine
+	acc.Assign(&g1)
+	for j := nbBits - 1; j >= 0; j-- {
+		acc.Double(fp, acc)
+		for i := range scalars {
+			tmp = acc
+			tmp.AddAssign(fp, K[i+1])
+			acc.Select(fp, bits[i][j], tmp, acc)
+		}
+	}
+
+	// K[0] - 2ⁿ·R
+	tmp.Assign(&offset)
+	tmp.AddAssign(fp, K[0])
+	acc.AddAssign(fp, tmp)
+	return acc
+}
+
+// Assign values to the "in-circuit" VerifyingKey from a "out-of-circuit" VerifyingKey
+func (vk *VerifyingKey) Assign(_ovk groth16.VerifyingKey) {
+	ovk, ok := _ovk.(*groth16_bn254.VerifyingKey)
+	if !ok {
+		panic("expected *groth16_bn254.VerifyingKey, got " + reflect.TypeOf(_ovk).String())
+	}
+
+	e, err := bn254.Pair([]bn254.G1Affine{ovk.G1.Alpha}, []bn254.G2Affine{ovk.G2.Beta})
+	if err != nil {
+		panic(err)
+	}
+	vk.E.Assign(&e)
+
+	vk.G1.K = make([]sw_bn254.G1Affine, len(ovk.G1.K))
+	for i := 0; i < len(ovk.G1.K); i++ {
+		vk.G1.K[i].Assign(&ovk.G1.K[i])
+	}
+	var deltaNeg, gammaNeg bn254.G2Affine
+	deltaNeg.Neg(&ovk.G2.Delta)
+	gammaNeg.Neg(&ovk.G2.Gamma)
+	vk.G2.DeltaNeg.Assign(&deltaNeg)
+	vk.G2.GammaNeg.Assign(&gammaNeg)
+}
+
+// Assign values to the "in-circuit" Proof from a "out-of-circuit" Proof
+func (proof *Proof) Assign(_oproof groth16.Proof) {
+	oproof, ok := _oproof.(*groth16_bn254.Proof)
+	if !ok {
+		panic("expected *groth16_bn254.Proof, got " + reflect.TypeOf(_oproof).String())
+	}
+	proof.Ar.Assign(&oproof.Ar)
+	proof.Krs.Assign(&oproof.Krs)
+	proof.Bs.Assign(&oproof.Bs)
+}
+
+// PublicInputsFromWitness returns the "in-circuit" public inputs of Verify from
+// a "out-of-circuit" witness of the inner circuit. If w is a full witness with
+// a schema, only its public part is kept.
+func PublicInputsFromWitness(w *witness.Witness) ([]frontend.Variable, error) {
+	if w.CurveID != ecc.BN254 {
+		return nil, fmt.Errorf("expected a witness on %s, got %s", ecc.BN254, w.CurveID)
+	}
+	v, ok := w.Vector.(*bn254witness.Witness)
+	if !ok {
+		return nil, fmt.Errorf("expected *witness.Witness, got %T", w.Vector)
+	}
+	values := *v
+	if w.Schema != nil && len(values) > w.Schema.NbPublic {
+		values = values[:w.Schema.NbPublic]
+	}
+	res := make([]frontend.Variable, len(values))
+	for i := range values {
+		var b big.Int
+		values[i].ToBigIntRegular(&b)
+		res[i] = &b
+	}
+	return res, nil
+}
@@ -0,0 +1,108 @@
+package groth16_bn254
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/groth16test"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type mimcCircuit struct {
+	PreImage frontend.Variable
+	Hash     frontend.Variable `gnark:",public"`
+}
+
+func (circuit *mimcCircuit) Define(api frontend.API) error {
+	mimc, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	mimc.Write(circuit.PreImage)
+	api.AssertIsEqual(mimc.Sum(), circuit.Hash)
+	return nil
+}
+
+// outerCircuit verifies an inner proof whose public inputs are the public
+// inputs of the outer circuit
+type outerCircuit struct {
+	InnerProof   Proof
+	InnerVk      VerifyingKey
+	PublicInputs []frontend.Variable `gnark:",public"`
+}
+
+func (circuit *outerCircuit) Define(api frontend.API) error {
+	return Verify(api, circuit.InnerVk, circuit.InnerProof, circuit.PublicInputs)
+}
+
+// newOuterAssignment proves innerAssignment for innerCircuit, and returns the
+// outer circuit sized for the inner one with its full assignment
+func newOuterAssignment(t *testing.T, innerCircuit, innerAssignment frontend.Circuit) (circuit, assignment *outerCircuit) {
+	proof, vk, publicWitness, err := groth16test.Prove(ecc.BN254, innerCircuit, innerAssignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment = &outerCircuit{}
+	assignment.InnerProof.Assign(proof)
+	assignment.InnerVk.Assign(vk)
+	if assignment.PublicInputs, err = PublicInputsFromWitness(publicWitness); err != nil {
+		t.Fatal(err)
+	}
+
+	circuit = &outerCircuit{
+		InnerProof:   PlaceholderProof(),
+		InnerVk:      PlaceholderVerifyingKey(len(assignment.PublicInputs)),
+		PublicInputs: make([]frontend.Variable, len(assignment.PublicInputs)),
+	}
+	return circuit, assignment
+}
+
+func TestVerifier(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping emulated Groth16 verification in short mode")
+	}
+	const (
+		preImage   = "4992816046196248432836492760315135318126925090839638585255611512962528270024"
+		publicHash = "15956918251515969911618340290271547916259395317667522197223663882723883490042"
+	)
+	circuit, assignment := newOuterAssignment(t, &mimcCircuit{}, &mimcCircuit{PreImage: preImage, Hash: publicHash})
+
+	// the circuit is too large to be compiled in a test, only the solver is run
+	if err := test.IsSolved(circuit, assignment, ecc.BN254, backend.UNKNOWN); err != nil {
+		t.Fatal(err)
+	}
+
+	// wrong public input
+	assignment.PublicInputs[0] = 42
+	if err := test.IsSolved(circuit, assignment, ecc.BN254, backend.UNKNOWN); err == nil {
+		t.Fatal("expected the verification to fail")
+	}
+}
+
+func TestPublicInputsFromWitness(t *testing.T) {
+	assignment := mimcCircuit{PreImage: 3, Hash: 42}
+	w, err := frontend.NewWitness(&assignment, ecc.BN254)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the full witness is truncated to its public part
+	publicInputs, err := PublicInputsFromWitness(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(publicInputs) != 1 || publicInputs[0].(*big.Int).Cmp(big.NewInt(42)) != 0 {
+		t.Fatal("unexpected public inputs", publicInputs)
+	}
+
+	w, err = frontend.NewWitness(&assignment, ecc.BLS12_381)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PublicInputsFromWitness(w); err == nil {
+		t.Fatal("expected an error on a witness of another curve")
+	}
+}
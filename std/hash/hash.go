@@ -30,3 +30,13 @@ type Hash interface {
 	// Reset empty the internal state and put the intermediate state to zero.
 	Reset()
 }
+
+// BinaryHasher is a Hash operating on bytes rather than on field elements
+// (for example SHA-256): each variable written must be a byte, and Sum returns
+// the digest as a big endian number.
+type BinaryHasher interface {
+	Hash
+
+	// Size returns the number of bytes of the digest.
+	Size() int
+}
@@ -0,0 +1,272 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hashtocurve
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields_bls12377"
+	"github.com/consensys/gnark/std/algebra/sw_bls12377"
+)
+
+func init() {
+	hint.Register(sswuSqrtHint)
+	hint.Register(sswuSqrtE2Hint)
+}
+
+// HashToG1 hashes the bytes msg to G1 with the domain separation tag dst. The
+// result is the same as gnark-crypto's bls12377.HashToCurveG1SSWU.
+func HashToG1(api frontend.API, msg []frontend.Variable, dst []byte) (sw_bls12377.G1Affine, error) {
+	u, err := hashToFp(api, msg, dst, 2)
+	if err != nil {
+		return sw_bls12377.G1Affine{}, err
+	}
+	Q0 := mapToIsogenousG1(api, u[0])
+	Q1 := mapToIsogenousG1(api, u[1])
+	Q0.AddAssign(api, Q1)
+
+	var res sw_bls12377.G1Affine
+	res.ClearCofactor(api, Q0)
+	return res, nil
+}
+
+// MapToG1 maps the element u of Fp to G1. The result is the same as the
+// map-to-curve and cofactor clearing steps of gnark-crypto's
+// bls12377.EncodeToCurveG1SSWU.
+func MapToG1(api frontend.API, u frontend.Variable) sw_bls12377.G1Affine {
+	var res sw_bls12377.G1Affine
+	res.ClearCofactor(api, mapToIsogenousG1(api, u))
+	return res
+}
+
+// HashToG2 hashes the bytes msg to G2 with the domain separation tag dst. The
+// result is the same as gnark-crypto's bls12377.HashToCurveG2SSWU.
+func HashToG2(api frontend.API, msg []frontend.Variable, dst []byte) (sw_bls12377.G2Affine, error) {
+	u, err := hashToFp(api, msg, dst, 4)
+	if err != nil {
+		return sw_bls12377.G2Affine{}, err
+	}
+	Q0 := mapToIsogenousG2(api, fields_bls12377.E2{A0: u[0], A1: u[1]})
+	Q1 := mapToIsogenousG2(api, fields_bls12377.E2{A0: u[2], A1: u[3]})
+	Q0.AddAssign(api, Q1)
+
+	var res sw_bls12377.G2Affine
+	res.ClearCofactor(api, Q0)
+	return res, nil
+}
+
+// MapToG2 maps the element u of Fp² to G2. The result is the same as the
+// map-to-curve and cofactor clearing steps of gnark-crypto's
+// bls12377.EncodeToCurveG2SSWU.
+func MapToG2(api frontend.API, u fields_bls12377.E2) sw_bls12377.G2Affine {
+	var res sw_bls12377.G2Affine
+	res.ClearCofactor(api, mapToIsogenousG2(api, u))
+	return res
+}
+
+// mapToIsogenousG1 maps u to E'₁ with the simplified SWU map, then to E₁ with
+// the isogeny
+func mapToIsogenousG1(api frontend.API, u frontend.Variable) sw_bls12377.G1Affine {
+	// the candidates are x₁ = B'(tv2+1)/(A'·tv4) and x₂ = Z·u²·x₁ where
+	// tv2 = Z²u⁴+Zu² and tv4 = -tv2, or Z if tv2 = 0
+	tv1 := api.Mul(g1SswuZ, u, u)
+	tv2 := api.Add(api.Mul(tv1, tv1), tv1)
+	tv3 := api.Mul(g1SswuB, api.Add(tv2, 1))
+	tv4 := api.Mul(g1SswuA, api.Select(api.IsZero(tv2), g1SswuZ, api.Neg(tv2)))
+	x1 := api.DivUnchecked(tv3, tv4)
+	x2 := api.Mul(tv1, x1)
+	gx1 := g1SswuRHS(api, x1)
+	gx2 := g1SswuRHS(api, x2)
+
+	// exactly one of g(x₁), g(x₂) = Z³u⁶·g(x₁) is a square: the hint tells
+	// which one, and its square root y such that sgn0(y) = sgn0(u)
+	res, err := api.Compiler().NewHint(sswuSqrtHint, 2, gx1, gx2, u)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	isSquare, y := res[0], res[1]
+	api.AssertIsBoolean(isSquare)
+	x := api.Select(isSquare, x1, x2)
+	api.AssertIsEqual(api.Mul(y, y), api.Select(isSquare, gx1, gx2))
+	api.AssertIsEqual(sgn0(api, y), sgn0(api, u))
+
+	// isogeny
+	var p sw_bls12377.G1Affine
+	p.X = api.DivUnchecked(evalPolynomial(api, g1IsogenyXNum, false, x), evalPolynomial(api, g1IsogenyXDen, true, x))
+	p.Y = api.DivUnchecked(api.Mul(y, evalPolynomial(api, g1IsogenyYNum, false, x)), evalPolynomial(api, g1IsogenyYDen, true, x))
+	return p
+}
+
+// g1SswuRHS returns x³ + A'x + B'
+func g1SswuRHS(api frontend.API, x frontend.Variable) frontend.Variable {
+	return api.Add(api.Mul(x, x, x), api.Mul(g1SswuA, x), g1SswuB)
+}
+
+// evalPolynomial returns Σ cᵢxⁱ, plus xⁿ if monic
+func evalPolynomial(api frontend.API, coefficients []*big.Int, monic bool, x frontend.Variable) frontend.Variable {
+	var res frontend.Variable = coefficients[len(coefficients)-1]
+	if monic {
+		res = api.Add(res, x)
+	}
+	for i := len(coefficients) - 2; i >= 0; i-- {
+		res = api.Add(api.Mul(res, x), coefficients[i])
+	}
+	return res
+}
+
+// mapToIsogenousG2 maps u to E'₂ with the simplified SWU map, then to E₂ with
+// the isogeny
+func mapToIsogenousG2(api frontend.API, u fields_bls12377.E2) sw_bls12377.G2Affine {
+	// see mapToIsogenousG1
+	var tv1, tv2, tv3, tv4, x1, x2, gx1, gx2, one, t fields_bls12377.E2
+	one.SetOne()
+	tv1.Square(api, u).Mul(api, tv1, g2SswuZ)
+	tv2.Square(api, tv1).Add(api, tv2, tv1)
+	tv3.Add(api, tv2, one).Mul(api, tv3, g2SswuB)
+	t.Neg(api, tv2)
+	tv4.Select(api, api.And(api.IsZero(tv2.A0), api.IsZero(tv2.A1)), g2SswuZ, t).Mul(api, tv4, g2SswuA)
+	x1.DivUnchecked(api, tv3, tv4)
+	x2.Mul(api, tv1, x1)
+	gx1 = g2SswuRHS(api, x1)
+	gx2 = g2SswuRHS(api, x2)
+
+	res, err := api.Compiler().NewHint(sswuSqrtE2Hint, 3, gx1.A0, gx1.A1, gx2.A0, gx2.A1, u.A0, u.A1)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	isSquare := res[0]
+	y := fields_bls12377.E2{A0: res[1], A1: res[2]}
+	api.AssertIsBoolean(isSquare)
+	var x, yy, gx fields_bls12377.E2
+	x.Select(api, isSquare, x1, x2)
+	yy.Square(api, y)
+	gx.Select(api, isSquare, gx1, gx2)
+	yy.AssertIsEqual(api, gx)
+	api.AssertIsEqual(sgn0E2(api, y), sgn0E2(api, u))
+
+	// isogeny
+	var p sw_bls12377.G2Affine
+	var num, den fields_bls12377.E2
+	num = evalPolynomialE2(api, g2IsogenyXNum, false, x)
+	den = evalPolynomialE2(api, g2IsogenyXDen, true, x)
+	p.X.DivUnchecked(api, num, den)
+	num = evalPolynomialE2(api, g2IsogenyYNum, false, x)
+	num.Mul(api, num, y)
+	den = evalPolynomialE2(api, g2IsogenyYDen, true, x)
+	p.Y.DivUnchecked(api, num, den)
+	return p
+}
+
+// g2SswuRHS returns x³ + A'x + B'
+func g2SswuRHS(api frontend.API, x fields_bls12377.E2) fields_bls12377.E2 {
+	var res, t fields_bls12377.E2
+	res.Square(api, x).Mul(api, res, x)
+	t.Mul(api, x, g2SswuA)
+	res.Add(api, res, t).Add(api, res, g2SswuB)
+	return res
+}
+
+// evalPolynomialE2 returns Σ cᵢxⁱ, plus xⁿ if monic
+func evalPolynomialE2(api frontend.API, coefficients []fields_bls12377.E2, monic bool, x fields_bls12377.E2) fields_bls12377.E2 {
+	res := coefficients[len(coefficients)-1]
+	if monic {
+		res.Add(api, res, x)
+	}
+	for i := len(coefficients) - 2; i >= 0; i-- {
+		res.Mul(api, res, x).Add(api, res, coefficients[i])
+	}
+	return res
+}
+
+// sgn0E2 returns sgn0(v.A0) if v.A0 ≠ 0, sgn0(v.A1) otherwise
+func sgn0E2(api frontend.API, v fields_bls12377.E2) frontend.Variable {
+	return api.Or(sgn0(api, v.A0), api.And(api.IsZero(v.A0), sgn0(api, v.A1)))
+}
+
+// hashToFp hashes msg to count elements of Fp, which must be the native field
+// (the result is the same as gnark-crypto's hashToFp)
+func hashToFp(api frontend.API, msg []frontend.Variable, dst []byte, count int) ([]frontend.Variable, error) {
+	if api.Compiler().Curve() != ecc.BW6_761 {
+		return nil, errors.New("hash to BLS12-377 curves is only supported in a BW6-761 circuit")
+	}
+	return HashToField(api, msg, dst, count)
+}
+
+// sswuSqrtHint returns 1 and a square root of gx1 if it is a square, 0 and a
+// square root of gx2 otherwise. The sign of the root is the sign of u.
+func sswuSqrtHint(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	var gx, y fp.Element
+	gx.SetBigInt(inputs[0])
+	results[0].SetUint64(1)
+	if gx.Legendre() == -1 {
+		gx.SetBigInt(inputs[1])
+		results[0].SetUint64(0)
+	}
+	if y.Sqrt(&gx) == nil {
+		return errors.New("no square root")
+	}
+	y.ToBigIntRegular(results[1])
+	if results[1].Bit(0) != inputs[2].Bit(0) {
+		y.Neg(&y)
+		y.ToBigIntRegular(results[1])
+	}
+	return nil
+}
+
+// sswuSqrtE2Hint is sswuSqrtHint in Fp²
+func sswuSqrtE2Hint(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	var gx, y bls12377.E2
+	gx.A0.SetBigInt(inputs[0])
+	gx.A1.SetBigInt(inputs[1])
+	results[0].SetUint64(1)
+	if gx.Legendre() == -1 {
+		gx.A0.SetBigInt(inputs[2])
+		gx.A1.SetBigInt(inputs[3])
+		results[0].SetUint64(0)
+	}
+	if gx.Legendre() == -1 {
+		return errors.New("no square root")
+	}
+	y.Sqrt(&gx)
+	if sgn0Native(&y.A0, &y.A1) != sgn0Big(inputs[4], inputs[5]) {
+		y.Neg(&y)
+	}
+	y.A0.ToBigIntRegular(results[1])
+	y.A1.ToBigIntRegular(results[2])
+	return nil
+}
+
+func sgn0Native(a0, a1 *fp.Element) uint {
+	var b0, b1 big.Int
+	return sgn0Big(a0.ToBigIntRegular(&b0), a1.ToBigIntRegular(&b1))
+}
+
+// sgn0Big returns the sign of a0 + a1·u, given by canonical representatives
+func sgn0Big(a0, a1 *big.Int) uint {
+	if a0.Sign() != 0 {
+		return a0.Bit(0)
+	}
+	return a1.Bit(0)
+}
@@ -0,0 +1,141 @@
+package hashtocurve
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields_bls12377"
+	"github.com/consensys/gnark/std/algebra/sw_bls12377"
+	"github.com/consensys/gnark/test"
+)
+
+var blsDST = []byte("BLS_SIG_BLS12377G1_XMD:SHA-256_SSWU_RO_POP_")
+
+// nativeHashToFp is gnark-crypto's hash to field, with regular big.Int outputs
+func nativeHashToFp(t *testing.T, msg []byte, count int) []*big.Int {
+	const L = 64
+	uniformBytes, err := ecc.ExpandMsgXmd(msg, blsDST, count*L)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := make([]*big.Int, count)
+	for i := range res {
+		var e fp.Element
+		e.SetBytes(uniformBytes[i*L : (i+1)*L])
+		res[i] = new(big.Int)
+		e.ToBigIntRegular(res[i])
+	}
+	return res
+}
+
+type mapToG1Circuit struct {
+	U frontend.Variable
+	P sw_bls12377.G1Affine
+}
+
+func (circuit *mapToG1Circuit) Define(api frontend.API) error {
+	res := MapToG1(api, circuit.U)
+	res.AssertIsEqual(api, circuit.P)
+	return nil
+}
+
+func TestMapToG1(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	msg := randomMessage(t)
+	p, err := bls12377.EncodeToCurveG1SSWU(msg, blsDST)
+	assert.NoError(err)
+
+	var witness mapToG1Circuit
+	witness.U = nativeHashToFp(t, msg, 1)[0]
+	witness.P.Assign(&p)
+	assert.SolvingSucceeded(&mapToG1Circuit{}, &witness, test.WithCurves(ecc.BW6_761))
+
+	p.Neg(&p)
+	witness.P.Assign(&p)
+	assert.SolvingFailed(&mapToG1Circuit{}, &witness, test.WithCurves(ecc.BW6_761))
+}
+
+type mapToG2Circuit struct {
+	U fields_bls12377.E2
+	P sw_bls12377.G2Affine
+}
+
+func (circuit *mapToG2Circuit) Define(api frontend.API) error {
+	res := MapToG2(api, circuit.U)
+	res.AssertIsEqual(api, circuit.P)
+	return nil
+}
+
+func TestMapToG2(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	msg := randomMessage(t)
+	p, err := bls12377.EncodeToCurveG2SSWU(msg, blsDST)
+	assert.NoError(err)
+
+	var witness mapToG2Circuit
+	u := nativeHashToFp(t, msg, 2)
+	witness.U.A0 = u[0]
+	witness.U.A1 = u[1]
+	witness.P.Assign(&p)
+	assert.SolvingSucceeded(&mapToG2Circuit{}, &witness, test.WithCurves(ecc.BW6_761))
+
+	p.Neg(&p)
+	witness.P.Assign(&p)
+	assert.SolvingFailed(&mapToG2Circuit{}, &witness, test.WithCurves(ecc.BW6_761))
+}
+
+type hashToG1G2Circuit struct {
+	Msg []frontend.Variable
+	P1  sw_bls12377.G1Affine
+	P2  sw_bls12377.G2Affine
+}
+
+func (circuit *hashToG1G2Circuit) Define(api frontend.API) error {
+	p1, err := HashToG1(api, circuit.Msg, blsDST)
+	if err != nil {
+		return err
+	}
+	p1.AssertIsEqual(api, circuit.P1)
+	p2, err := HashToG2(api, circuit.Msg, blsDST)
+	if err != nil {
+		return err
+	}
+	p2.AssertIsEqual(api, circuit.P2)
+	return nil
+}
+
+func TestHashToG1G2(t *testing.T) {
+	msg := randomMessage(t)
+	p1, err := bls12377.HashToCurveG1SSWU(msg, blsDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := bls12377.HashToCurveG2SSWU(msg, blsDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var witness hashToG1G2Circuit
+	witness.Msg = assignMessage(msg)
+	witness.P1.Assign(&p1)
+	witness.P2.Assign(&p2)
+
+	// the SHA-256 compressions make the circuit large, only the solver is run
+	circuit := hashToG1G2Circuit{Msg: make([]frontend.Variable, msgLen)}
+	if err := test.IsSolved(&circuit, &witness, ecc.BW6_761, backend.UNKNOWN); err != nil {
+		t.Fatal(err)
+	}
+
+	msg[0] ^= 1
+	witness.Msg = assignMessage(msg)
+	if err := test.IsSolved(&circuit, &witness, ecc.BW6_761, backend.UNKNOWN); err == nil {
+		t.Fatal("expected the hash of another message to differ")
+	}
+}
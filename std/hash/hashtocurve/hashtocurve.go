@@ -0,0 +1,144 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hashtocurve provides ZKP-circuit functions to hash bytes to elliptic
+// curve points, following RFC 9380 (https://www.rfc-editor.org/rfc/rfc9380).
+//
+// The messages are hashed to field elements with expand_message_xmd over an
+// in-circuit SHA-256 (ExpandMsgXmd, HashToField), then mapped to the curve:
+//
+//   - BLS12-377 G1 and G2, in a BW6-761 circuit, with the simplified SWU map
+//     (HashToG1, HashToG2). The results are the ones of gnark-crypto's
+//     bls12377.HashToCurveG1SSWU and bls12377.HashToCurveG2SSWU.
+//   - the twisted Edwards curves of std/algebra/twistededwards, in the circuit
+//     of their snark curve, with the Elligator 2 map (HashToTwistedEdwards).
+//     gnark-crypto has no native counterpart: the native functions of this
+//     package (NativeHashToTwistedEdwards, ...) compute the same points out of
+//     circuit.
+//
+// The messages are slices of bytes: each variable must be a byte, which is
+// checked by the SHA-256 gadget.
+package hashtocurve
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha256"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// securityLevel is the security parameter k of hash_to_field, in bits
+const securityLevel = 128
+
+// HashToField hashes msg to count elements of the native field with the domain
+// separation tag dst (RFC 9380, section 5.2, with expand_message_xmd and
+// SHA-256). The result is the same as NativeHashToField with the modulus of
+// the native field.
+func HashToField(api frontend.API, msg []frontend.Variable, dst []byte, count int) ([]frontend.Variable, error) {
+	L := fieldElementLength(api.Compiler().Curve().Info().Fr.Modulus())
+	uniformBytes, err := ExpandMsgXmd(api, msg, dst, count*L)
+	if err != nil {
+		return nil, err
+	}
+
+	// the big endian numbers are reduced modulo p by the native field
+	res := make([]frontend.Variable, count)
+	for i := 0; i < count; i++ {
+		res[i] = fromBytes(api, uniformBytes[i*L:(i+1)*L])
+	}
+	return res, nil
+}
+
+// fieldElementLength returns the number of bytes hashed for each element of
+// the field of modulus p: L = ceil((ceil(log2(p)) + k) / 8)
+func fieldElementLength(p *big.Int) int {
+	return (p.BitLen() + securityLevel + 7) / 8
+}
+
+// ExpandMsgXmd expands msg into lenInBytes pseudo random bytes with SHA-256
+// (RFC 9380, section 5.3.1). The result is the same as gnark-crypto's
+// ecc.ExpandMsgXmd.
+func ExpandMsgXmd(api frontend.API, msg []frontend.Variable, dst []byte, lenInBytes int) ([]frontend.Variable, error) {
+	h := sha256.New(api)
+	ell := (lenInBytes + h.Size() - 1) / h.Size()
+	if ell > 255 {
+		return nil, errors.New("invalid lenInBytes")
+	}
+	if len(dst) > 255 {
+		return nil, errors.New("invalid domain size (>255 bytes)")
+	}
+	dstPrime := append(append([]byte{}, dst...), uint8(len(dst)))
+
+	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
+	h.Write(make([]byte, 64))
+	h.Write(msg...)
+	h.Write([]byte{uint8(lenInBytes >> 8), uint8(lenInBytes), 0}, dstPrime)
+	b0 := h.SumBits()
+
+	// b_1 = H(b_0 || I2OSP(1, 1) || DST_prime)
+	h.Reset()
+	h.Write(digestBytes(api, b0)...)
+	h.Write([]byte{1}, dstPrime)
+	bi := h.SumBits()
+
+	res := make([]frontend.Variable, 0, ell*h.Size())
+	res = append(res, digestBytes(api, bi)...)
+	for i := 2; i <= ell; i++ {
+		// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
+		strxor := make([]frontend.Variable, len(b0))
+		for j := range b0 {
+			strxor[j] = api.Xor(b0[j], bi[j])
+		}
+		h.Reset()
+		h.Write(digestBytes(api, strxor)...)
+		h.Write([]byte{uint8(i)}, dstPrime)
+		bi = h.SumBits()
+		res = append(res, digestBytes(api, bi)...)
+	}
+	return res[:lenInBytes], nil
+}
+
+// digestBytes returns the bytes of a digest given by sha256.Digest.SumBits
+func digestBytes(api frontend.API, bits []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(bits)/8)
+	for i := range res {
+		// the first bit is the least significant bit of the last byte
+		j := len(res) - 1 - i
+		var b frontend.Variable = 0
+		for k := 7; k >= 0; k-- {
+			b = api.Add(api.Mul(b, 2), bits[8*j+k])
+		}
+		res[i] = b
+	}
+	return res
+}
+
+// fromBytes returns the big endian number given by bytes
+func fromBytes(api frontend.API, bytes []frontend.Variable) frontend.Variable {
+	var res frontend.Variable = 0
+	for i := range bytes {
+		res = api.Add(api.Mul(res, 256), bytes[i])
+	}
+	return res
+}
+
+// sgn0 returns the parity of the canonical representative of v (RFC 9380,
+// section 4.1)
+func sgn0(api frontend.API, v frontend.Variable) frontend.Variable {
+	return bits.ToCanonicalBinary(api, v)[0]
+}
@@ -0,0 +1,136 @@
+package hashtocurve
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+var dst = []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+
+const msgLen = 32
+
+func randomMessage(t *testing.T) []byte {
+	msg := make([]byte, msgLen)
+	if _, err := rand.Read(msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func assignMessage(msg []byte) []frontend.Variable {
+	res := make([]frontend.Variable, len(msg))
+	for i := range msg {
+		res[i] = msg[i]
+	}
+	return res
+}
+
+type expandMsgXmdCircuit struct {
+	Msg          []frontend.Variable
+	UniformBytes []frontend.Variable
+}
+
+func (circuit *expandMsgXmdCircuit) Define(api frontend.API) error {
+	res, err := ExpandMsgXmd(api, circuit.Msg, dst, len(circuit.UniformBytes))
+	if err != nil {
+		return err
+	}
+	for i := range res {
+		api.AssertIsEqual(res[i], circuit.UniformBytes[i])
+	}
+	return nil
+}
+
+func TestExpandMsgXmd(t *testing.T) {
+	// 3 blocks of SHA-256 (gnark-crypto only supports multiples of 32)
+	const lenInBytes = 96
+
+	msg := randomMessage(t)
+	uniformBytes, err := ecc.ExpandMsgXmd(msg, dst, lenInBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	native, err := NativeExpandMsgXmd(msg, dst, lenInBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(native, uniformBytes) {
+		t.Fatal("native expansion differs from gnark-crypto")
+	}
+
+	witness := expandMsgXmdCircuit{Msg: assignMessage(msg), UniformBytes: assignMessage(uniformBytes)}
+
+	// the SHA-256 compressions make the circuit large, only the solver is run
+	circuit := expandMsgXmdCircuit{Msg: make([]frontend.Variable, msgLen), UniformBytes: make([]frontend.Variable, lenInBytes)}
+	if err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN); err != nil {
+		t.Fatal(err)
+	}
+
+	uniformBytes[lenInBytes-1] ^= 1
+	witness.UniformBytes = assignMessage(uniformBytes)
+	if err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN); err == nil {
+		t.Fatal("expected the expansion to differ")
+	}
+}
+
+type hashToFieldCircuit struct {
+	Msg []frontend.Variable
+	U   []frontend.Variable
+}
+
+func (circuit *hashToFieldCircuit) Define(api frontend.API) error {
+	res, err := HashToField(api, circuit.Msg, dst, len(circuit.U))
+	if err != nil {
+		return err
+	}
+	for i := range res {
+		api.AssertIsEqual(res[i], circuit.U[i])
+	}
+	return nil
+}
+
+func TestHashToField(t *testing.T) {
+	const count = 2
+
+	// gnark-crypto's reduction of the uniform bytes in fr (L = 48)
+	msg := randomMessage(t)
+	uniformBytes, err := ecc.ExpandMsgXmd(msg, dst, count*48)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := make([]frontend.Variable, count)
+	for i := range u {
+		var e fr.Element
+		e.SetBytes(uniformBytes[i*48 : (i+1)*48])
+		u[i] = e.ToBigIntRegular(new(big.Int))
+	}
+
+	native, err := NativeHashToField(fr.Modulus(), msg, dst, count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range native {
+		if native[i].Cmp(u[i].(*big.Int)) != 0 {
+			t.Fatal("native hash to field differs from gnark-crypto")
+		}
+	}
+
+	witness := hashToFieldCircuit{Msg: assignMessage(msg), U: u}
+	circuit := hashToFieldCircuit{Msg: make([]frontend.Variable, msgLen), U: make([]frontend.Variable, count)}
+	if err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN); err != nil {
+		t.Fatal(err)
+	}
+
+	witness.U[0], witness.U[1] = u[1], u[0]
+	if err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN); err == nil {
+		t.Fatal("expected the hash to differ")
+	}
+}
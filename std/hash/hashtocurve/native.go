@@ -0,0 +1,186 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hashtocurve
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// NativeHashToField hashes msg to count elements of the field of modulus p
+// with the domain separation tag dst, as HashToField in a circuit over this
+// field
+func NativeHashToField(p *big.Int, msg, dst []byte, count int) ([]*big.Int, error) {
+	L := fieldElementLength(p)
+	uniformBytes, err := NativeExpandMsgXmd(msg, dst, count*L)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*big.Int, count)
+	for i := range res {
+		res[i] = new(big.Int).SetBytes(uniformBytes[i*L : (i+1)*L])
+		res[i].Mod(res[i], p)
+	}
+	return res, nil
+}
+
+// NativeExpandMsgXmd expands msg into lenInBytes pseudo random bytes with
+// SHA-256, as ExpandMsgXmd. The result is the same as gnark-crypto's
+// ecc.ExpandMsgXmd, which only supports multiples of the digest size.
+func NativeExpandMsgXmd(msg, dst []byte, lenInBytes int) ([]byte, error) {
+	h := sha256.New()
+	ell := (lenInBytes + h.Size() - 1) / h.Size()
+	if ell > 255 {
+		return nil, errors.New("invalid lenInBytes")
+	}
+	if len(dst) > 255 {
+		return nil, errors.New("invalid domain size (>255 bytes)")
+	}
+	dstPrime := append(append([]byte{}, dst...), uint8(len(dst)))
+
+	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
+	h.Write(make([]byte, 64))
+	h.Write(msg)
+	h.Write([]byte{uint8(lenInBytes >> 8), uint8(lenInBytes), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	// b_1 = H(b_0 || I2OSP(1, 1) || DST_prime)
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+
+	res := make([]byte, 0, ell*h.Size())
+	res = append(res, bi...)
+	for i := 2; i <= ell; i++ {
+		// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{uint8(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		res = append(res, bi...)
+	}
+	return res[:lenInBytes], nil
+}
+
+// NativeHashToTwistedEdwards hashes msg to the subgroup of the twisted Edwards
+// curve id with the domain separation tag dst, as HashToTwistedEdwards
+func NativeHashToTwistedEdwards(id tedwards.ID, msg, dst []byte) ([2]*big.Int, error) {
+	curve, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	u, err := NativeHashToField(curve.Modulus(), msg, dst, 2)
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	Q0, err := nativeElligator2(curve, u[0])
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	Q1, err := nativeElligator2(curve, u[1])
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	return curve.ScalarMul(curve.Add(Q0, Q1), curve.Params().Cofactor), nil
+}
+
+// NativeEncodeToTwistedEdwards hashes msg to the subgroup of the twisted
+// Edwards curve id with the domain separation tag dst, as
+// EncodeToTwistedEdwards
+func NativeEncodeToTwistedEdwards(id tedwards.ID, msg, dst []byte) ([2]*big.Int, error) {
+	curve, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	u, err := NativeHashToField(curve.Modulus(), msg, dst, 1)
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	return NativeMapToTwistedEdwards(id, u[0])
+}
+
+// NativeMapToTwistedEdwards maps the element u of the base field to the
+// subgroup of the twisted Edwards curve id, as MapToTwistedEdwards
+func NativeMapToTwistedEdwards(id tedwards.ID, u *big.Int) ([2]*big.Int, error) {
+	curve, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	p, err := nativeElligator2(curve, u)
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	return curve.ScalarMul(p, curve.Params().Cofactor), nil
+}
+
+// nativeElligator2 maps u to the curve, as elligator2
+func nativeElligator2(curve *twistededwards.NativeCurve, u *big.Int) ([2]*big.Int, error) {
+	q := curve.Modulus()
+	c := newElligator2Constants(curve.Params(), q)
+
+	// x₁ = -(J/K)/(1+Zu²), or -(J/K) if 1+Zu² = 0, and x₂ = -x₁-J/K
+	var tv, x1, x2 big.Int
+	tv.Mul(u, u).Mul(&tv, c.z).Add(&tv, big.NewInt(1)).Mod(&tv, q)
+	if tv.Sign() == 0 {
+		tv.SetUint64(1)
+	}
+	x1.ModInverse(&tv, q).Mul(&x1, c.jOverK).Neg(&x1).Mod(&x1, q)
+	x2.Add(&x1, c.jOverK).Neg(&x2).Mod(&x2, q)
+
+	x := &x1
+	isSquare, y := elligator2Sqrt(nativeElligator2RHS(c, &x1, q), nativeElligator2RHS(c, &x2, q), q)
+	if y == nil {
+		return [2]*big.Int{}, errors.New("no square root")
+	}
+	if isSquare == 0 {
+		x = &x2
+	}
+
+	// (s, t) = (xK, yK) on the Montgomery curve, mapped to (s/t, (s-1)/(s+1)),
+	// or to (0, 1) if t(s+1) = 0
+	var s, t, sPlusOne big.Int
+	s.Mul(x, c.k).Mod(&s, q)
+	t.Mul(y, c.k).Mod(&t, q)
+	sPlusOne.Add(&s, big.NewInt(1)).Mod(&sPlusOne, q)
+	if t.Sign() == 0 || sPlusOne.Sign() == 0 {
+		return [2]*big.Int{big.NewInt(0), big.NewInt(1)}, nil
+	}
+	v := new(big.Int).ModInverse(&t, q)
+	v.Mul(v, &s).Mod(v, q)
+	w := new(big.Int).ModInverse(&sPlusOne, q)
+	w.Mul(w, s.Sub(&s, big.NewInt(1))).Mod(w, q)
+	return [2]*big.Int{v, w}, nil
+}
+
+// nativeElligator2RHS returns x³ + (J/K)x² + x/K²
+func nativeElligator2RHS(c elligator2Constants, x, q *big.Int) *big.Int {
+	res := new(big.Int).Mul(c.jOverK, x)
+	res.Add(res, new(big.Int).Mul(x, x)).Add(res, c.invKSquare)
+	res.Mul(res, x).Mod(res, q)
+	return res
+}
@@ -0,0 +1,200 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hashtocurve
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// The twisted Edwards curve ax²+y² = 1+dx²y² is birationally equivalent to the
+// Montgomery curve Kt² = s³+Js²+s with J = 2(a+d)/(a-d) and K = 4/(a-d), and
+// the points are mapped to the Montgomery curve with Elligator 2 (RFC 9380,
+// section 6.7.1), then to the twisted Edwards curve with the rational map of
+// appendix D.1.
+
+func init() {
+	hint.Register(elligator2SqrtHint)
+}
+
+// HashToTwistedEdwards hashes the bytes msg to the subgroup of the curve with
+// the domain separation tag dst (hash_to_curve of RFC 9380, section 3). The
+// result is the same as NativeHashToTwistedEdwards.
+func HashToTwistedEdwards(curve twistededwards.Curve, msg []frontend.Variable, dst []byte) (twistededwards.Point, error) {
+	u, err := HashToField(curve.API(), msg, dst, 2)
+	if err != nil {
+		return twistededwards.Point{}, err
+	}
+	Q0 := elligator2(curve, u[0])
+	Q1 := elligator2(curve, u[1])
+	return clearCofactor(curve, curve.Add(Q0, Q1)), nil
+}
+
+// EncodeToTwistedEdwards hashes the bytes msg to the subgroup of the curve
+// with the domain separation tag dst (encode_to_curve of RFC 9380, section 3:
+// the distribution of the result is not uniform). The result is the same as
+// NativeEncodeToTwistedEdwards.
+func EncodeToTwistedEdwards(curve twistededwards.Curve, msg []frontend.Variable, dst []byte) (twistededwards.Point, error) {
+	u, err := HashToField(curve.API(), msg, dst, 1)
+	if err != nil {
+		return twistededwards.Point{}, err
+	}
+	return MapToTwistedEdwards(curve, u[0]), nil
+}
+
+// MapToTwistedEdwards maps the element u of the base field to the subgroup of
+// the curve, with the Elligator 2 map followed by the cofactor clearing. The
+// result is the same as NativeMapToTwistedEdwards.
+func MapToTwistedEdwards(curve twistededwards.Curve, u frontend.Variable) twistededwards.Point {
+	return clearCofactor(curve, elligator2(curve, u))
+}
+
+// elligator2Constants are the constants of the Elligator 2 map of a curve
+type elligator2Constants struct {
+	jOverK     *big.Int // J/K
+	invKSquare *big.Int // 1/K²
+	k          *big.Int // K
+	z          *big.Int // Z, a non-square
+}
+
+// newElligator2Constants returns the constants of the map to the twisted
+// Edwards curve of parameters params, over the field of modulus q
+func newElligator2Constants(params *twistededwards.CurveParams, q *big.Int) elligator2Constants {
+	a := new(big.Int).Mod(params.A, q)
+	d := new(big.Int).Mod(params.D, q)
+	aMinusD := new(big.Int).Sub(a, d)
+	aMinusD.Mod(aMinusD, q)
+	inv16 := new(big.Int).ModInverse(big.NewInt(16), q)
+
+	var c elligator2Constants
+
+	// J/K = (a+d)/2, 1/K² = (a-d)²/16, K = 4/(a-d)
+	c.jOverK = new(big.Int).Add(a, d)
+	c.jOverK.Mul(c.jOverK, new(big.Int).ModInverse(big.NewInt(2), q)).Mod(c.jOverK, q)
+	c.invKSquare = new(big.Int).Mul(aMinusD, aMinusD)
+	c.invKSquare.Mul(c.invKSquare, inv16).Mod(c.invKSquare, q)
+	c.k = new(big.Int).ModInverse(aMinusD, q)
+	c.k.Lsh(c.k, 2).Mod(c.k, q)
+
+	// the first non-square in 1, -1, 2, -2, ... (find_z_ell2 of RFC 9380,
+	// appendix H.3)
+	for i := int64(1); ; i++ {
+		if z := big.NewInt(i); big.Jacobi(z, q) == -1 {
+			c.z = z
+			break
+		}
+		if z := new(big.Int).Sub(q, big.NewInt(i)); big.Jacobi(z, q) == -1 {
+			c.z = z
+			break
+		}
+	}
+	return c
+}
+
+// elligator2 maps u to the curve (map_to_curve of RFC 9380)
+func elligator2(curve twistededwards.Curve, u frontend.Variable) twistededwards.Point {
+	api := curve.API()
+	c := newElligator2Constants(curve.Params(), api.Compiler().Curve().Info().Fr.Modulus())
+
+	// x₁ = -(J/K)/(1+Zu²), or -(J/K) if 1+Zu² = 0, and x₂ = -x₁-J/K
+	tv := api.Add(api.Mul(c.z, u, u), 1)
+	x1 := api.DivUnchecked(api.Neg(c.jOverK), api.Select(api.IsZero(tv), 1, tv))
+	x2 := api.Sub(api.Neg(c.jOverK), x1)
+	gx1 := elligator2RHS(api, c, x1)
+	gx2 := elligator2RHS(api, c, x2)
+
+	// g(x₂) = Zu²·g(x₁): when u·g(x₁) ≠ 0, exactly one of them is a square.
+	// The hint tells which one, and its square root y, odd for g(x₁) and even
+	// for g(x₂) (or 0).
+	res, err := api.Compiler().NewHint(elligator2SqrtHint, 2, gx1, gx2)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	isSquare, y := res[0], res[1]
+	api.AssertIsBoolean(isSquare)
+	x := api.Select(isSquare, x1, x2)
+	api.AssertIsEqual(api.Mul(y, y), api.Select(isSquare, gx1, gx2))
+	api.AssertIsEqual(sgn0(api, y), api.Mul(isSquare, api.Sub(1, api.IsZero(y))))
+
+	// (s, t) = (xK, yK) on the Montgomery curve, mapped to (s/t, (s-1)/(s+1)),
+	// or to (0, 1) if t(s+1) = 0
+	s := api.Mul(x, c.k)
+	t := api.Mul(y, c.k)
+	sPlusOne := api.Add(s, 1)
+	exceptional := api.IsZero(api.Mul(t, sPlusOne))
+	v := api.DivUnchecked(s, api.Select(exceptional, 1, t))
+	w := api.DivUnchecked(api.Sub(s, 1), api.Select(exceptional, 1, sPlusOne))
+	return twistededwards.Point{
+		X: api.Select(exceptional, 0, v),
+		Y: api.Select(exceptional, 1, w),
+	}
+}
+
+// elligator2RHS returns x³ + (J/K)x² + x/K²
+func elligator2RHS(api frontend.API, c elligator2Constants, x frontend.Variable) frontend.Variable {
+	return api.Mul(x, api.Add(api.Mul(x, x), api.Mul(c.jOverK, x), c.invKSquare))
+}
+
+// clearCofactor returns [h]p, h being the cofactor of the curve
+func clearCofactor(curve twistededwards.Curve, p twistededwards.Point) twistededwards.Point {
+	h := curve.Params().Cofactor
+	if h.BitLen()-1 != int(h.TrailingZeroBits()) {
+		return curve.ScalarMul(p, h)
+	}
+	for i := 1; i < h.BitLen(); i++ {
+		p = curve.Double(p)
+	}
+	return p
+}
+
+// elligator2SqrtHint returns 1 and the odd square root of gx1 if it is a
+// square, 0 and the even square root of gx2 otherwise
+func elligator2SqrtHint(curve ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	q := curve.Info().Fr.Modulus()
+	isSquare, y := elligator2Sqrt(inputs[0], inputs[1], q)
+	if y == nil {
+		return errors.New("no square root")
+	}
+	results[0].SetUint64(uint64(isSquare))
+	results[1].Set(y)
+	return nil
+}
+
+// elligator2Sqrt returns 1 and the odd square root of gx1 if it is a square,
+// 0 and the even square root of gx2 otherwise (nil if gx2 is not a square)
+func elligator2Sqrt(gx1, gx2, q *big.Int) (uint, *big.Int) {
+	var isSquare uint = 1
+	gx := gx1
+	if big.Jacobi(gx1, q) == -1 {
+		isSquare = 0
+		gx = gx2
+	}
+	y := new(big.Int).ModSqrt(gx, q)
+	if y == nil {
+		return 0, nil
+	}
+	if y.Bit(0) != isSquare {
+		y.Sub(q, y).Mod(y, q)
+	}
+	return isSquare, y
+}
@@ -0,0 +1,149 @@
+package hashtocurve
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+var curves = []tedwards.ID{tedwards.BN254, tedwards.BLS12_377, tedwards.BLS12_381, tedwards.BLS12_381_BANDERSNATCH, tedwards.BW6_761, tedwards.BW6_633, tedwards.BLS24_315}
+
+func TestNativeElligator2(t *testing.T) {
+	for _, id := range curves {
+		curve, err := twistededwards.NewNativeCurve(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 10; i++ {
+			u, err := rand.Int(rand.Reader, curve.Modulus())
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, err := nativeElligator2(curve, u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !curve.IsOnCurve(p) {
+				t.Fatal("point not on the curve")
+			}
+
+			// the result of the map is in the subgroup
+			p, err = NativeMapToTwistedEdwards(id, u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			q := curve.ScalarMul(p, curve.Params().Order)
+			if q[0].Sign() != 0 || q[1].Cmp(big.NewInt(1)) != 0 {
+				t.Fatal("point not in the subgroup")
+			}
+		}
+	}
+}
+
+type mapToTwistedEdwardsCircuit struct {
+	curveID tedwards.ID
+	U       frontend.Variable
+	P       twistededwards.Point
+}
+
+func (circuit *mapToTwistedEdwardsCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	res := MapToTwistedEdwards(curve, circuit.U)
+	api.AssertIsEqual(res.X, circuit.P.X)
+	api.AssertIsEqual(res.Y, circuit.P.Y)
+	return nil
+}
+
+func TestMapToTwistedEdwards(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	circuits := make([]mapToTwistedEdwardsCircuit, len(curves))
+	for i, id := range curves {
+		snarkCurve, err := twistededwards.GetSnarkCurve(id)
+		assert.NoError(err)
+		circuits[i].curveID = id
+
+		u, err := rand.Int(rand.Reader, snarkCurve.Info().Fr.Modulus())
+		assert.NoError(err)
+		for _, u := range []*big.Int{u, big.NewInt(0)} {
+			p, err := NativeMapToTwistedEdwards(id, u)
+			assert.NoError(err)
+
+			witness := mapToTwistedEdwardsCircuit{U: u, P: twistededwards.Point{X: p[0], Y: p[1]}}
+			assert.SolvingSucceeded(&circuits[i], &witness, test.WithCurves(snarkCurve))
+			if p[0].Sign() == 0 {
+				continue
+			}
+
+			// -p
+			witness.P.X = new(big.Int).Sub(snarkCurve.Info().Fr.Modulus(), p[0])
+			assert.SolvingFailed(&circuits[i], &witness, test.WithCurves(snarkCurve))
+		}
+	}
+}
+
+type hashToTwistedEdwardsCircuit struct {
+	curveID tedwards.ID
+	Msg     []frontend.Variable
+	P1, P2  twistededwards.Point
+}
+
+func (circuit *hashToTwistedEdwardsCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	p1, err := HashToTwistedEdwards(curve, circuit.Msg, dst)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(p1.X, circuit.P1.X)
+	api.AssertIsEqual(p1.Y, circuit.P1.Y)
+	p2, err := EncodeToTwistedEdwards(curve, circuit.Msg, dst)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(p2.X, circuit.P2.X)
+	api.AssertIsEqual(p2.Y, circuit.P2.Y)
+	return nil
+}
+
+func TestHashToTwistedEdwards(t *testing.T) {
+	msg := randomMessage(t)
+	p1, err := NativeHashToTwistedEdwards(tedwards.BN254, msg, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := NativeEncodeToTwistedEdwards(tedwards.BN254, msg, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	witness := hashToTwistedEdwardsCircuit{
+		Msg: assignMessage(msg),
+		P1:  twistededwards.Point{X: p1[0], Y: p1[1]},
+		P2:  twistededwards.Point{X: p2[0], Y: p2[1]},
+	}
+
+	// the SHA-256 compressions make the circuit large, only the solver is run
+	circuit := hashToTwistedEdwardsCircuit{curveID: tedwards.BN254, Msg: make([]frontend.Variable, msgLen)}
+	if err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN); err != nil {
+		t.Fatal(err)
+	}
+
+	msg[0] ^= 1
+	witness.Msg = assignMessage(msg)
+	if err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN); err == nil {
+		t.Fatal("expected the hash of another message to differ")
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// Generator returns the index-th generator derived from the personalization,
+// a point of the prime order subgroup of the curve id whose discrete logarithm
+// is unknown.
+//
+// The generator is found by try-and-increment: y = SHA-256(personalization ‖
+// index ‖ counter) mod p (index and counter as 4 bytes big endian), for the
+// first counter such that there is an x with (x, y) on the curve and such that
+// [cofactor](x, y) is not of small order. x is the even square root, and the
+// generator is [cofactor](x, y).
+func Generator(id tedwards.ID, personalization string, index int) ([2]*big.Int, error) {
+	curve, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	return generator(curve, personalization, index)
+}
+
+func generator(curve *twistededwards.NativeCurve, personalization string, index int) ([2]*big.Int, error) {
+	if index < 0 || index > math.MaxUint32 {
+		return [2]*big.Int{}, errors.New("generator index out of range")
+	}
+	params := curve.Params()
+	p := curve.Modulus()
+
+	buf := make([]byte, len(personalization)+8)
+	copy(buf, personalization)
+	binary.BigEndian.PutUint32(buf[len(personalization):], uint32(index))
+
+	one := big.NewInt(1)
+	var yy, num, den big.Int
+	for counter := uint64(0); counter <= math.MaxUint32; counter++ {
+		binary.BigEndian.PutUint32(buf[len(personalization)+4:], uint32(counter))
+		digest := sha256.Sum256(buf)
+		y := new(big.Int).SetBytes(digest[:])
+		y.Mod(y, p)
+
+		// x² = (1 - y²) / (a - dy²)
+		yy.Mul(y, y).Mod(&yy, p)
+		num.Sub(one, &yy).Mod(&num, p)
+		den.Mul(params.D, &yy).Sub(params.A, &den).Mod(&den, p)
+		if den.Sign() == 0 {
+			continue
+		}
+		den.ModInverse(&den, p)
+		num.Mul(&num, &den).Mod(&num, p)
+		x := new(big.Int).ModSqrt(&num, p)
+		if x == nil {
+			continue
+		}
+		if x.Bit(0) == 1 {
+			x.Sub(p, x)
+		}
+
+		// the points of order 1 and 2 are the ones with x = 0
+		g := curve.ScalarMul([2]*big.Int{x, y}, params.Cofactor)
+		if g[0].Sign() == 0 {
+			continue
+		}
+		return g, nil
+	}
+	return [2]*big.Int{}, errors.New("no generator found")
+}
+
+// generators holds the generators of the segments of a Pedersen hash, and the
+// multiples of them selected by the chunks of the messages
+type generators struct {
+	curve           *twistededwards.NativeCurve
+	personalization string
+	nbChunks        int // number of chunks per segment
+
+	// tables[j][i] are [m·16ⁱ]G_j for m = 1, 2, 3, 4, where G_j is the
+	// generator of the j-th segment
+	tables [][][4][2]*big.Int
+}
+
+func newGenerators(id tedwards.ID, personalization string) (*generators, error) {
+	curve, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	return &generators{
+		curve:           curve,
+		personalization: personalization,
+		nbChunks:        nbChunksPerSegment(curve.Params().Order),
+	}, nil
+}
+
+// nbChunksPerSegment returns the largest number of chunks c such that the
+// encodings of the segments, in [-4·(16ᶜ-1)/15, 4·(16ᶜ-1)/15] \ {0}, are distinct
+// modulo the order of the subgroup
+func nbChunksPerSegment(order *big.Int) int {
+	bound := new(big.Int).Sub(order, big.NewInt(1))
+	bound.Rsh(bound, 1)
+
+	c := 0
+	var max, pow big.Int
+	for {
+		// 4·(16^(c+1)-1)/15
+		pow.Lsh(big.NewInt(1), uint(4*(c+1)))
+		max.Sub(&pow, big.NewInt(1)).Div(&max, big.NewInt(15)).Lsh(&max, 2)
+		if max.Cmp(bound) > 0 {
+			return c
+		}
+		c++
+	}
+}
+
+// generator returns the generator of the j-th segment
+func (g *generators) generator(j int) ([2]*big.Int, error) {
+	return generator(g.curve, g.personalization, j)
+}
+
+// table returns the multiples [m·16ⁱ]G_j (m = 1, 2, 3, 4) selected by the k-th
+// chunk of a message, with j = k / nbChunks and i = k % nbChunks
+func (g *generators) table(k int) ([4][2]*big.Int, error) {
+	j, i := k/g.nbChunks, k%g.nbChunks
+	for len(g.tables) <= j {
+		gen, err := g.generator(len(g.tables))
+		if err != nil {
+			return [4][2]*big.Int{}, err
+		}
+		tables := make([][4][2]*big.Int, g.nbChunks)
+		acc := gen
+		for i := range tables {
+			tables[i][0] = acc
+			tables[i][1] = g.curve.Double(acc)
+			tables[i][2] = g.curve.Add(tables[i][1], acc)
+			tables[i][3] = g.curve.Double(tables[i][1])
+			acc = g.curve.Double(g.curve.Double(tables[i][3]))
+		}
+		g.tables = append(g.tables, tables)
+	}
+	return g.tables[j][i], nil
+}
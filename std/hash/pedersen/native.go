@@ -0,0 +1,103 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// HashBits returns the Pedersen hash of bits on the curve id, computed out of
+// circuit. It matches the Hasher's SumPoint after WriteBits(bits...).
+func HashBits(id tedwards.ID, personalization string, bits []bool) ([2]*big.Int, error) {
+	gens, err := newGenerators(id, personalization)
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	curve := gens.curve
+	nbBits := 3 * gens.nbChunks
+
+	res := [2]*big.Int{big.NewInt(0), big.NewInt(1)}
+	for j := 0; j*nbBits < len(bits); j++ {
+		end := (j + 1) * nbBits
+		if end > len(bits) {
+			end = len(bits)
+		}
+
+		// ⟨Mⱼ⟩ = ∑ enc(mᵢ)·2^(4i)
+		segment := bits[j*nbBits : end]
+		var scalar, enc big.Int
+		for i := 0; 3*i < len(segment); i++ {
+			var s [3]bool
+			copy(s[:], segment[3*i:])
+			enc.SetInt64(1 + bit(s[0]) + 2*bit(s[1]))
+			if s[2] {
+				enc.Neg(&enc)
+			}
+			scalar.Add(&scalar, enc.Lsh(&enc, uint(4*i)))
+		}
+
+		g, err := gens.generator(j)
+		if err != nil {
+			return [2]*big.Int{}, err
+		}
+		if scalar.Sign() < 0 {
+			g = curve.Neg(g)
+			scalar.Neg(&scalar)
+		}
+		res = curve.Add(res, curve.ScalarMul(g, &scalar))
+	}
+
+	return res, nil
+}
+
+// Sum returns the x coordinate of the Pedersen hash of data on the curve id,
+// computed out of circuit: each element is reduced modulo the snark field and
+// hashed as its little endian bits. It matches the Hasher's Sum after
+// Write(data...).
+func Sum(id tedwards.ID, personalization string, data ...*big.Int) (*big.Int, error) {
+	curve, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	modulus := curve.Modulus()
+	nbBits := modulus.BitLen()
+
+	bits := make([]bool, 0, len(data)*nbBits)
+	var v big.Int
+	for i := range data {
+		v.Mod(data[i], modulus)
+		for j := 0; j < nbBits; j++ {
+			bits = append(bits, v.Bit(j) == 1)
+		}
+	}
+
+	res, err := HashBits(id, personalization, bits)
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+func bit(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pedersen provides a ZKP-circuit function to compute a Pedersen hash
+// on a twisted Edwards curve, as in the Zcash Sapling protocol.
+//
+// The message, a string of bits, is padded with zeros to a multiple of 3 bits
+// and cut in segments of c chunks of 3 bits, where c depends on the order of
+// the curve. The chunk (s₀, s₁, s₂) is encoded as enc = (1-2s₂)·(1+s₀+2s₁), the
+// segment Mⱼ = (m₀, ..., m_{c-1}) as ⟨Mⱼ⟩ = ∑ enc(mᵢ)·2^(4i), and the hash of
+// the message is the point ∑ [⟨Mⱼ⟩]Gⱼ, where the generators Gⱼ are derived
+// from a personalization string (see Generator).
+//
+// The points [k·16ⁱ]Gⱼ are constants of the circuit: each chunk costs a lookup,
+// a conditional negation and an addition.
+//
+// The generators are not the ones of Zcash (which are derived with BLAKE2s on
+// Jubjub) and the personalization is not prepended to the message, hence the
+// hashes differ from Zcash's. The functions HashBits and Sum compute the same
+// hashes out of circuit, for witness generation.
+package pedersen
+
+import (
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// Hasher computes Pedersen hashes in a circuit. It implements hash.Hash.
+//
+// As in Zcash Sapling, the length of the message is not hashed, and the padding
+// of the last chunk collides: bit strings which differ only by trailing zeros
+// and have the same number of chunks of 3 bits have the same hash (for instance
+// 1011 and 101100). The caller must write the length if it varies.
+type Hasher struct {
+	curve twistededwards.Curve
+	gens  *generators
+	data  []frontend.Variable // bits of the data written since the last Reset
+}
+
+// NewPedersen returns a Pedersen Hasher on the twisted Edwards curve id (which
+// must match the snark curve), with generators derived from the personalization
+func NewPedersen(api frontend.API, id tedwards.ID, personalization string) (Hasher, error) {
+	curve, err := twistededwards.NewEdCurve(api, id)
+	if err != nil {
+		return Hasher{}, err
+	}
+	gens, err := newGenerators(id, personalization)
+	if err != nil {
+		return Hasher{}, err
+	}
+	return Hasher{curve: curve, gens: gens}, nil
+}
+
+// Write adds more data to the running hash. Each variable is absorbed as the
+// little endian bits of a field element.
+func (h *Hasher) Write(data ...frontend.Variable) {
+	api := h.curve.API()
+	nbBits := api.Compiler().Curve().Info().Fr.Bits
+	for _, v := range data {
+		h.data = append(h.data, api.ToBinary(v, nbBits)...)
+	}
+}
+
+// WriteBits adds bits to the running hash. The caller must ensure they are
+// boolean.
+func (h *Hasher) WriteBits(bits ...frontend.Variable) {
+	h.data = append(h.data, bits...)
+}
+
+// Reset resets the Hash to its initial state.
+func (h *Hasher) Reset() {
+	h.data = nil
+}
+
+// Sum returns the x coordinate of the hash of the data written since the last
+// Reset. The data is kept, and each call to Sum hashes it all.
+func (h *Hasher) Sum() frontend.Variable {
+	return h.SumPoint().X
+}
+
+// SumPoint returns the hash (a point of the curve) of the data written since
+// the last Reset. The hash of no data is the neutral element (0, 1).
+func (h *Hasher) SumPoint() twistededwards.Point {
+	api := h.curve.API()
+
+	res := twistededwards.Point{X: 0, Y: 1}
+	for k := 0; 3*k < len(h.data); k++ {
+		s := [3]frontend.Variable{0, 0, 0}
+		copy(s[:], h.data[3*k:])
+
+		t, err := h.gens.table(k)
+		if err != nil {
+			panic(err)
+		}
+
+		// ±[1+s₀+2s₁]P
+		var p twistededwards.Point
+		p.X = api.Lookup2(s[0], s[1], t[0][0], t[1][0], t[2][0], t[3][0])
+		p.Y = api.Lookup2(s[0], s[1], t[0][1], t[1][1], t[2][1], t[3][1])
+		p.X = api.Select(s[2], api.Neg(p.X), p.X)
+
+		if k == 0 {
+			res = p
+		} else {
+			res = h.curve.Add(res, p)
+		}
+	}
+
+	return res
+}
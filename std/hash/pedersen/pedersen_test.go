@@ -0,0 +1,143 @@
+package pedersen
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+var curves = []tedwards.ID{tedwards.BN254, tedwards.BLS12_377, tedwards.BLS12_381, tedwards.BLS12_381_BANDERSNATCH, tedwards.BW6_761, tedwards.BW6_633, tedwards.BLS24_315}
+
+const personalization = "gnark_pedersen_test"
+
+func TestGenerator(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, id := range curves {
+		curve, err := twistededwards.NewNativeCurve(id)
+		assert.NoError(err)
+
+		g0, err := Generator(id, personalization, 0)
+		assert.NoError(err)
+		g1, err := Generator(id, personalization, 1)
+		assert.NoError(err)
+		g, err := Generator(id, personalization, 0)
+		assert.NoError(err)
+
+		assert.True(curve.IsOnCurve(g0), "generator not on the curve")
+		assert.True(g0[0].Cmp(g[0]) == 0 && g0[1].Cmp(g[1]) == 0, "derivation not deterministic")
+		assert.False(g0[0].Cmp(g1[0]) == 0 && g0[1].Cmp(g1[1]) == 0, "generators of distinct indexes are equal")
+
+		// [order]G = (0, 1)
+		o := curve.ScalarMul(g0, curve.Params().Order)
+		assert.True(o[0].Sign() == 0 && o[1].Cmp(big.NewInt(1)) == 0, "generator not in the prime order subgroup")
+	}
+}
+
+type hashCircuit struct {
+	curveID tedwards.ID
+	Data    [2]frontend.Variable
+	Bits    [7]frontend.Variable
+	Sum     frontend.Variable
+	Point   twistededwards.Point
+}
+
+func (circuit *hashCircuit) Define(api frontend.API) error {
+	h, err := NewPedersen(api, circuit.curveID, personalization)
+	if err != nil {
+		return err
+	}
+
+	h.Write(circuit.Data[:]...)
+	api.AssertIsEqual(h.Sum(), circuit.Sum)
+
+	h.Reset()
+	for _, b := range circuit.Bits {
+		api.AssertIsBoolean(b)
+	}
+	h.WriteBits(circuit.Bits[:]...)
+	p := h.SumPoint()
+	api.AssertIsEqual(p.X, circuit.Point.X)
+	api.AssertIsEqual(p.Y, circuit.Point.Y)
+
+	return nil
+}
+
+func TestHash(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	circuits := make([]hashCircuit, len(curves))
+	for i, id := range curves {
+		snarkCurve, err := twistededwards.GetSnarkCurve(id)
+		assert.NoError(err)
+		modulus := snarkCurve.Info().Fr.Modulus()
+
+		// two field elements span several segments
+		var witness hashCircuit
+		data := make([]*big.Int, len(witness.Data))
+		for j := range data {
+			data[j], err = rand.Int(rand.Reader, modulus)
+			assert.NoError(err)
+			witness.Data[j] = data[j]
+		}
+		witness.Sum, err = Sum(id, personalization, data...)
+		assert.NoError(err)
+
+		// a message which is not a multiple of 3 bits, with negative chunks
+		bits := []bool{true, false, true, true, true, true, false}
+		for j := range bits {
+			witness.Bits[j] = bit(bits[j])
+		}
+		p, err := HashBits(id, personalization, bits)
+		assert.NoError(err)
+		witness.Point.X, witness.Point.Y = p[0], p[1]
+
+		circuits[i].curveID = id
+		assert.SolvingSucceeded(&circuits[i], &witness, test.WithCurves(snarkCurve))
+
+		witness.Data[0] = new(big.Int).Add(data[0], big.NewInt(1))
+		assert.SolvingFailed(&circuits[i], &witness, test.WithCurves(snarkCurve))
+	}
+}
+
+func TestHashOtherPersonalization(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	data := big.NewInt(42)
+	h1, err := Sum(tedwards.BN254, "personalization_1", data)
+	assert.NoError(err)
+	h2, err := Sum(tedwards.BN254, "personalization_2", data)
+	assert.NoError(err)
+	assert.True(h1.Cmp(h2) != 0, "the personalization should change the hash")
+}
+
+func TestHashTrailingZeros(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// the padding of the last chunk collides (see Hasher)
+	bits := []bool{true, false, true, true}
+	h1, err := HashBits(tedwards.BN254, personalization, bits)
+	assert.NoError(err)
+	h2, err := HashBits(tedwards.BN254, personalization, append(bits, false, false))
+	assert.NoError(err)
+	assert.True(h1[0].Cmp(h2[0]) == 0 && h1[1].Cmp(h2[1]) == 0, "zeros in the last chunk should not change the hash")
+
+	// but a chunk of zeros is encoded as 1
+	h2, err = HashBits(tedwards.BN254, personalization, append(bits, false, false, false))
+	assert.NoError(err)
+	assert.True(h1[0].Cmp(h2[0]) != 0 || h1[1].Cmp(h2[1]) != 0, "a chunk of zeros should change the hash")
+}
+
+func TestNbChunksPerSegment(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// the Jubjub segments of Zcash Sapling have 63 chunks
+	params, err := twistededwards.GetCurveParams(tedwards.BLS12_381)
+	assert.NoError(err)
+	assert.Equal(63, nbChunksPerSegment(params.Order))
+}
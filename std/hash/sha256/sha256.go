@@ -0,0 +1,268 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sha256 provides a ZKP-circuit function to compute a SHA-256 hash
+// (FIPS 180-4), compatible with crypto/sha256.
+//
+// The hash operates on bytes: each variable written is a byte, which is range
+// checked in the circuit. The length of the message is fixed at compile time.
+// A compression costs about 27k constraints (R1CS).
+package sha256
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Size is the size of a SHA-256 digest in bytes
+const Size = 32
+
+// blockSize is the size of a SHA-256 block in bytes
+const blockSize = 64
+
+var iv = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a,
+	0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var k = [64]uint32{
+	0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+	0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+	0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+	0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+	0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+	0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+	0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+	0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+}
+
+// word is a 32 bits word, as little endian bits
+type word [32]frontend.Variable
+
+// Digest computes a SHA-256 hash in a circuit. It implements hash.BinaryHasher.
+type Digest struct {
+	api  frontend.API
+	data []frontend.Variable // bytes written since the last reset
+}
+
+// New returns a new SHA-256 hash.
+func New(api frontend.API) *Digest {
+	return &Digest{api: api}
+}
+
+// Write appends bytes to the message. Each variable must be a byte; a []byte
+// is interpreted as a sequence of constant bytes (and not as a big endian
+// number as for field elements hashes).
+func (d *Digest) Write(data ...frontend.Variable) {
+	for _, v := range data {
+		if b, ok := v.([]byte); ok {
+			for i := range b {
+				d.data = append(d.data, b[i])
+			}
+			continue
+		}
+		d.data = append(d.data, v)
+	}
+}
+
+// Reset empties the message.
+func (d *Digest) Reset() {
+	d.data = nil
+}
+
+// Size returns the number of bytes of the digest.
+func (d *Digest) Size() int {
+	return Size
+}
+
+// Sum returns the digest of the message, as a big endian number. The native
+// field must have more than 256 bits (see SumBits otherwise).
+func (d *Digest) Sum() frontend.Variable {
+	if bits := d.api.Compiler().Curve().Info().Fr.Bits; bits <= 8*Size {
+		panic(fmt.Sprintf("a %d bits native field can't hold a SHA-256 digest", bits))
+	}
+	return d.api.FromBinary(d.SumBits()...)
+}
+
+// SumBits returns the 256 bits of the digest of the message, as a big endian
+// number in little endian bits (i.e. the first bit is the least significant
+// bit of the last byte).
+func (d *Digest) SumBits() []frontend.Variable {
+	// pad the message with 0x80, zeros, and the bit length of the message
+	// on 64 bits, to a multiple of the block size
+	msg := make([]frontend.Variable, len(d.data), len(d.data)+2*blockSize)
+	copy(msg, d.data)
+	msg = append(msg, 0x80)
+	for len(msg)%blockSize != blockSize-8 {
+		msg = append(msg, 0)
+	}
+	var length [8]byte
+	bitLength := uint64(len(d.data)) * 8
+	for i := 0; i < 8; i++ {
+		length[7-i] = byte(bitLength >> (8 * i))
+	}
+	for i := range length {
+		msg = append(msg, length[i])
+	}
+
+	// big endian words of the message
+	words := make([]word, len(msg)/4)
+	for i := range words {
+		for j := 0; j < 4; j++ {
+			bits := d.api.ToBinary(msg[4*i+j], 8)
+			copy(words[i][8*(3-j):8*(4-j)], bits)
+		}
+	}
+
+	var state [8]word
+	for i := range state {
+		state[i] = constantWord(iv[i])
+	}
+	for i := 0; i < len(words); i += 16 {
+		var block [16]word
+		copy(block[:], words[i:i+16])
+		state = d.compress(state, block)
+	}
+
+	res := make([]frontend.Variable, 0, 8*Size)
+	for i := len(state) - 1; i >= 0; i-- {
+		res = append(res, state[i][:]...)
+	}
+	return res
+}
+
+// compress applies the SHA-256 compression function to a block
+func (d *Digest) compress(state [8]word, block [16]word) [8]word {
+	var w [64]word
+	copy(w[:], block[:])
+	for t := 16; t < 64; t++ {
+		// σ₀ = ROTR⁷ ⊕ ROTR¹⁸ ⊕ SHR³, σ₁ = ROTR¹⁷ ⊕ ROTR¹⁹ ⊕ SHR¹⁰
+		s0 := d.xor3(rotr(w[t-15], 7), rotr(w[t-15], 18), shr(w[t-15], 3))
+		s1 := d.xor3(rotr(w[t-2], 17), rotr(w[t-2], 19), shr(w[t-2], 10))
+		w[t] = d.add(s1, w[t-7], s0, w[t-16])
+	}
+
+	a, b, c, dd, e, f, g, h := state[0], state[1], state[2], state[3], state[4], state[5], state[6], state[7]
+	for t := 0; t < 64; t++ {
+		// Σ₁ = ROTR⁶ ⊕ ROTR¹¹ ⊕ ROTR²⁵, Σ₀ = ROTR² ⊕ ROTR¹³ ⊕ ROTR²²
+		s1 := d.xor3(rotr(e, 6), rotr(e, 11), rotr(e, 25))
+		ch := d.ch(e, f, g)
+		t1 := []word{h, s1, ch, constantWord(k[t]), w[t]}
+		s0 := d.xor3(rotr(a, 2), rotr(a, 13), rotr(a, 22))
+		maj := d.maj(a, b, c)
+
+		h, g, f = g, f, e
+		e = d.add(append(t1, dd)...)
+		dd, c, b = c, b, a
+		a = d.add(append(t1, s0, maj)...)
+	}
+
+	return [8]word{
+		d.add(state[0], a), d.add(state[1], b), d.add(state[2], c), d.add(state[3], dd),
+		d.add(state[4], e), d.add(state[5], f), d.add(state[6], g), d.add(state[7], h),
+	}
+}
+
+// add returns the sum of the words modulo 2³²
+func (d *Digest) add(words ...word) word {
+	var sum frontend.Variable = 0
+	for _, w := range words {
+		sum = d.api.Add(sum, d.toVariable(w))
+	}
+	// the sum has at most 32 + log₂(len(words)) bits, we keep the 32 lsb
+	nbBits := 32
+	for n := 1; n < len(words); n *= 2 {
+		nbBits++
+	}
+	var res word
+	copy(res[:], d.api.ToBinary(sum, nbBits))
+	return res
+}
+
+// toVariable returns the value of w
+func (d *Digest) toVariable(w word) frontend.Variable {
+	var res frontend.Variable = 0
+	c := big.NewInt(1)
+	for i := range w {
+		// we don't use api.FromBinary, as the bits are already constrained
+		res = d.api.Add(res, d.api.Mul(w[i], c))
+		c.Lsh(c, 1)
+	}
+	return res
+}
+
+// xor3 returns a ⊕ b ⊕ c
+func (d *Digest) xor3(a, b, c word) word {
+	var res word
+	for i := range res {
+		res[i] = d.xor(d.xor(a[i], b[i]), c[i])
+	}
+	return res
+}
+
+// xor returns a ⊕ b = a + b - 2ab, for bits a and b
+func (d *Digest) xor(a, b frontend.Variable) frontend.Variable {
+	return d.api.Sub(d.api.Add(a, b), d.api.Mul(2, a, b))
+}
+
+// ch returns (e ∧ f) ⊕ (¬e ∧ g) = g + e(f - g)
+func (d *Digest) ch(e, f, g word) word {
+	var res word
+	for i := range res {
+		res[i] = d.api.Add(g[i], d.api.Mul(e[i], d.api.Sub(f[i], g[i])))
+	}
+	return res
+}
+
+// maj returns (a ∧ b) ⊕ (a ∧ c) ⊕ (b ∧ c) = ab + c(a ⊕ b)
+func (d *Digest) maj(a, b, c word) word {
+	var res word
+	for i := range res {
+		ab := d.api.Mul(a[i], b[i])
+		res[i] = d.api.Add(ab, d.api.Mul(c[i], d.api.Sub(d.api.Add(a[i], b[i]), d.api.Mul(2, ab))))
+	}
+	return res
+}
+
+func rotr(w word, n int) word {
+	var res word
+	for i := range res {
+		res[i] = w[(i+n)%32]
+	}
+	return res
+}
+
+func shr(w word, n int) word {
+	var res word
+	for i := range res {
+		if i+n < 32 {
+			res[i] = w[i+n]
+		} else {
+			res[i] = 0
+		}
+	}
+	return res
+}
+
+func constantWord(v uint32) word {
+	var res word
+	for i := range res {
+		res[i] = (v >> i) & 1
+	}
+	return res
+}
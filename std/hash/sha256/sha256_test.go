@@ -0,0 +1,52 @@
+package sha256
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type sha256Circuit struct {
+	Message []frontend.Variable
+	Digest  frontend.Variable `gnark:",public"`
+}
+
+func (circuit *sha256Circuit) Define(api frontend.API) error {
+	h := New(api)
+	h.Write([]byte("gnark"))
+	h.Write(circuit.Message...)
+	api.AssertIsEqual(h.Sum(), circuit.Digest)
+	return nil
+}
+
+func TestSHA256(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// lengths around the padding boundaries: the 5 bytes prefix + len(msg)
+	for _, n := range []int{0, 50, 51, 59, 123, 200} {
+		msg := make([]byte, n)
+		for i := range msg {
+			msg[i] = byte(i * 7)
+		}
+		digest := sha256.Sum256(append([]byte("gnark"), msg...))
+
+		circuit := sha256Circuit{Message: make([]frontend.Variable, n)}
+		witness := sha256Circuit{Message: make([]frontend.Variable, n), Digest: new(big.Int).SetBytes(digest[:])}
+		for i := range msg {
+			witness.Message[i] = msg[i]
+		}
+		assert.Run(func(assert *test.Assert) {
+			assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+			if n > 0 {
+				witness.Message[0] = 256
+				assert.SolvingFailed(&circuit, &witness, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+			}
+		}, fmt.Sprintf("len=%d", n))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/consensys/gnark/std/algebra/sw_bls12377"
 	"github.com/consensys/gnark/std/algebra/sw_bls24315"
 	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
 )
 
 var registerOnce sync.Once
@@ -27,4 +28,7 @@ func registerHints() {
 	hint.Register(bits.NNAF)
 	hint.Register(bits.IthBit)
 	hint.Register(bits.NBits)
+	hint.Register(emulated.QuoRemHint)
+	hint.Register(emulated.DivHint)
+	hint.Register(emulated.CarryHint)
 }
@@ -0,0 +1,393 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bigint implements the arithmetic of arbitrary precision non-negative
+// integers in a circuit, with a modulus which may be a variable (for instance
+// an RSA modulus, see std/signature/rsa). For the arithmetic modulo a constant
+// prime, std/math/emulated is cheaper.
+//
+// An integer x is represented by limbs x₀, …, x_{k-1} of w bits in little
+// endian order, such that x = ∑ xᵢ·2^{w·i}. The number of limbs is the one of
+// the witness (see Params) and grows with the operations: the sum of integers
+// of k and l limbs has max(k, l)+1 limbs, their product k+l limbs, while the
+// remainder modulo n has as many limbs as n.
+//
+// The results of the operations are computed out-of-circuit by hints and
+// checked in the circuit: all their limbs are range checked, and the circuit
+// asserts that the integers on both sides of an equality (for instance a·b and
+// q·n + r, for a remainder r < n) are equal, by checking that the difference of
+// the limb-wise polynomial products evaluates to zero at 2^w, with hinted and
+// range checked carries.
+package bigint
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Int is an arbitrary precision non-negative integer in a circuit.
+//
+// The limbs of an integer obtained from an Arithmetic are range checked. The
+// limbs of an integer allocated in the circuit witness (see Params.Placeholder
+// and Params.ValueOf) are range checked the first time it is used by an
+// Arithmetic.
+type Int struct {
+	Limbs []frontend.Variable
+
+	// internal is true if the width of the limbs is enforced
+	internal bool
+}
+
+// Arithmetic performs the arithmetic of big integers in a circuit.
+type Arithmetic struct {
+	api         frontend.API
+	bitsPerLimb int
+
+	// checked records the witness integers whose limbs were range checked, by
+	// the address of their first limb, which is shared by the copies of an
+	// integer. We don't mark the integers themselves, as the circuit structure
+	// may be compiled several times.
+	checked map[*frontend.Variable]struct{}
+}
+
+// NewArithmetic returns an Arithmetic for the integers of params in the circuit
+// of api. The products of integers larger than params.NbLimbs limbs may exceed
+// the capacity of the native field, in which case the operations panic.
+func NewArithmetic(api frontend.API, params Params) (*Arithmetic, error) {
+	if params.NbLimbs <= 0 || params.BitsPerLimb <= 0 {
+		return nil, errors.New("invalid number of limbs")
+	}
+	f := &Arithmetic{
+		api:         api,
+		bitsPerLimb: params.BitsPerLimb,
+		checked:     make(map[*frontend.Variable]struct{}),
+	}
+	if f.productBits(params.NbLimbs, params.NbLimbs)+1 > f.maxBits() {
+		return nil, fmt.Errorf("limbs of %d bits are too large for the native field", params.BitsPerLimb)
+	}
+	return f, nil
+}
+
+// Constant returns the integer v, which must be non-negative.
+func (f *Arithmetic) Constant(v *big.Int) *Int {
+	if v.Sign() < 0 {
+		panic("negative constant")
+	}
+	nbLimbs := (v.BitLen() + f.bitsPerLimb - 1) / f.bitsPerLimb
+	if nbLimbs == 0 {
+		nbLimbs = 1
+	}
+	res := &Int{Limbs: make([]frontend.Variable, nbLimbs), internal: true}
+	for i, l := range decompose(v, f.bitsPerLimb, nbLimbs) {
+		res.Limbs[i] = l
+	}
+	return res
+}
+
+// FromBits returns the integer of little endian bits. The bits must be
+// constrained to be boolean.
+func (f *Arithmetic) FromBits(bs ...frontend.Variable) *Int {
+	w := f.bitsPerLimb
+	nbLimbs := (len(bs) + w - 1) / w
+	if nbLimbs == 0 {
+		return f.Constant(new(big.Int))
+	}
+	res := &Int{Limbs: make([]frontend.Variable, nbLimbs), internal: true}
+	for i := range res.Limbs {
+		end := (i + 1) * w
+		if end > len(bs) {
+			end = len(bs)
+		}
+		res.Limbs[i] = f.api.FromBinary(bs[i*w : end]...)
+	}
+	return res
+}
+
+// ToBits returns the little endian bits of a, BitsPerLimb·len(a.Limbs) of them.
+func (f *Arithmetic) ToBits(a *Int) []frontend.Variable {
+	f.enforceWidth(a)
+	res := make([]frontend.Variable, 0, len(a.Limbs)*f.bitsPerLimb)
+	for i := range a.Limbs {
+		res = append(res, f.api.ToBinary(a.Limbs[i], f.bitsPerLimb)...)
+	}
+	return res
+}
+
+// Add returns a + b.
+func (f *Arithmetic) Add(a, b *Int) *Int {
+	f.enforceWidth(a)
+	f.enforceWidth(b)
+	m := maxInt(len(a.Limbs), len(b.Limbs))
+	sum := make([]frontend.Variable, m)
+	for i := range sum {
+		sum[i] = f.api.Add(limb(a.Limbs, i), limb(b.Limbs, i))
+	}
+	return f.normalize(sum, m+1, f.bitsPerLimb+1)
+}
+
+// Mul returns a·b.
+func (f *Arithmetic) Mul(a, b *Int) *Int {
+	f.enforceWidth(a)
+	f.enforceWidth(b)
+	return f.normalize(f.mulLimbs(a.Limbs, b.Limbs), len(a.Limbs)+len(b.Limbs), f.productBits(len(a.Limbs), len(b.Limbs)))
+}
+
+// Mod returns a mod n.
+//
+// The most significant limb of n must be non-zero: otherwise the quotient may
+// not fit in the number of limbs allocated for it, and the circuit can't be
+// satisfied. Likewise the circuit can't be satisfied if n = 0.
+func (f *Arithmetic) Mod(a, n *Int) *Int {
+	f.enforceWidth(a)
+	return f.quoRem(a.Limbs, f.bitsPerLimb*len(a.Limbs), f.bitsPerLimb, n)
+}
+
+// ModMul returns a·b mod n, with the restrictions of Mod on n.
+func (f *Arithmetic) ModMul(a, b, n *Int) *Int {
+	f.enforceWidth(a)
+	f.enforceWidth(b)
+	nbBits := f.bitsPerLimb * (len(a.Limbs) + len(b.Limbs))
+	return f.quoRem(f.mulLimbs(a.Limbs, b.Limbs), nbBits, f.productBits(len(a.Limbs), len(b.Limbs)), n)
+}
+
+// ModExp returns aᵉ mod n, for a constant non-negative exponent e, with the
+// restrictions of Mod on n.
+func (f *Arithmetic) ModExp(a *Int, e *big.Int, n *Int) *Int {
+	if e.Sign() < 0 {
+		panic("negative exponent")
+	}
+	if e.Sign() == 0 {
+		return f.Mod(f.Constant(big.NewInt(1)), n)
+	}
+	base := f.Mod(a, n)
+	res := base
+	for i := e.BitLen() - 2; i >= 0; i-- {
+		res = f.ModMul(res, res, n)
+		if e.Bit(i) == 1 {
+			res = f.ModMul(res, base, n)
+		}
+	}
+	return res
+}
+
+// AssertIsEqual fails if a ≠ b.
+func (f *Arithmetic) AssertIsEqual(a, b *Int) {
+	f.enforceWidth(a)
+	f.enforceWidth(b)
+
+	// the limbs are range checked, hence the representations are unique
+	for i := 0; i < maxInt(len(a.Limbs), len(b.Limbs)); i++ {
+		f.api.AssertIsEqual(limb(a.Limbs, i), limb(b.Limbs, i))
+	}
+}
+
+// AssertIsLess fails if a ⩾ b.
+func (f *Arithmetic) AssertIsLess(a, b *Int) {
+	f.enforceWidth(a)
+	f.enforceWidth(b)
+
+	// a + 1 + d = b for a hinted d ⩾ 0
+	inputs := []frontend.Variable{f.bitsPerLimb, 1, len(a.Limbs)}
+	inputs = append(inputs, a.Limbs...)
+	inputs = append(inputs, b.Limbs...)
+	d, err := f.api.Compiler().NewHint(SubHint, len(b.Limbs), inputs...)
+	if err != nil {
+		panic(err)
+	}
+	f.rangeCheck(d)
+
+	left := make([]frontend.Variable, maxInt(len(a.Limbs), len(d)))
+	for i := range left {
+		left[i] = f.api.Add(limb(a.Limbs, i), limb(d, i))
+	}
+	left[0] = f.api.Add(left[0], 1)
+	f.checkZero(left, b.Limbs, f.bitsPerLimb+2)
+}
+
+// normalize returns the integer ∑ coefficients[i]·2^{w·i} with nbLimbs limbs.
+// The coefficients must be positive and smaller than 2^nbBits.
+func (f *Arithmetic) normalize(coefficients []frontend.Variable, nbLimbs, nbBits int) *Int {
+	inputs := []frontend.Variable{f.bitsPerLimb, 0, nbLimbs, 0}
+	inputs = append(inputs, coefficients...)
+	limbs, err := f.api.Compiler().NewHint(QuoRemHint, nbLimbs, inputs...)
+	if err != nil {
+		panic(err)
+	}
+	f.rangeCheck(limbs)
+	f.checkZero(coefficients, limbs, nbBits)
+	return &Int{Limbs: limbs, internal: true}
+}
+
+// quoRem returns the remainder of the euclidean division of the integer
+// ∑ coefficients[i]·2^{w·i} by n. The coefficients must be positive and
+// smaller than 2^nbBits, and the integer smaller than 2^valueBits.
+func (f *Arithmetic) quoRem(coefficients []frontend.Variable, valueBits, nbBits int, n *Int) *Int {
+	f.enforceWidth(n)
+	w := f.bitsPerLimb
+
+	// n ⩾ 2^{w·(len(n.Limbs)-1)}
+	nbQuotientLimbs := (valueBits - w*(len(n.Limbs)-1) + w - 1) / w
+	if nbQuotientLimbs < 1 {
+		nbQuotientLimbs = 1
+	}
+	inputs := []frontend.Variable{w, nbQuotientLimbs, len(n.Limbs), len(n.Limbs)}
+	inputs = append(inputs, n.Limbs...)
+	inputs = append(inputs, coefficients...)
+	res, err := f.api.Compiler().NewHint(QuoRemHint, nbQuotientLimbs+len(n.Limbs), inputs...)
+	if err != nil {
+		panic(err)
+	}
+	f.rangeCheck(res)
+	q, r := res[:nbQuotientLimbs], &Int{Limbs: res[nbQuotientLimbs:], internal: true}
+
+	right := f.mulLimbs(q, n.Limbs)
+	for i := range r.Limbs {
+		right[i] = f.api.Add(right[i], r.Limbs[i])
+	}
+	if rightBits := f.productBits(len(q), len(n.Limbs)) + 1; rightBits > nbBits {
+		nbBits = rightBits
+	}
+	f.checkZero(coefficients, right, nbBits)
+	f.AssertIsLess(r, n)
+
+	return r
+}
+
+// mulLimbs returns the coefficients of the product of the polynomials of
+// coefficients x and y
+func (f *Arithmetic) mulLimbs(x, y []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(x)+len(y)-1)
+	for i := range res {
+		res[i] = 0
+	}
+	for i := range x {
+		for j := range y {
+			res[i+j] = f.api.Add(res[i+j], f.api.Mul(x[i], y[j]))
+		}
+	}
+	return res
+}
+
+// productBits returns a bound on the number of bits of the coefficients of the
+// product of polynomials of k and l coefficients of w bits
+func (f *Arithmetic) productBits(k, l int) int {
+	return 2*f.bitsPerLimb + bits.Len(uint(minInt(k, l)))
+}
+
+// maxBits returns the maximal number of bits of the coefficients given to
+// checkZero, such that the carry equations don't wrap around the native
+// modulus
+func (f *Arithmetic) maxBits() int {
+	return f.api.Compiler().Curve().Info().Fr.Bits - 3
+}
+
+// checkZero asserts that the polynomials of coefficients left and right
+// evaluate to the same integer at 2^w, where w is the number of bits per limb.
+// All the coefficients must be positive and smaller than 2^nbBits.
+//
+// With dᵢ = leftᵢ - rightᵢ, the carries cᵢ (hinted and range checked) must
+// verify dᵢ + cᵢ₋₁ = cᵢ·2^w, and the last carry must be 0. As |dᵢ| < 2^nbBits,
+// the carries are bounded by 2^{nbBits-w+1}.
+func (f *Arithmetic) checkZero(left, right []frontend.Variable, nbBits int) {
+	if nbBits > f.maxBits() {
+		panic(fmt.Sprintf("coefficients of %d bits are too large for the native field", nbBits))
+	}
+	w := f.bitsPerLimb
+	m := maxInt(len(left), len(right))
+	if m == 1 {
+		f.api.AssertIsEqual(left[0], right[0])
+		return
+	}
+
+	carryBits := nbBits - w + 1
+	inputs := []frontend.Variable{w, carryBits, m}
+	for i := 0; i < m; i++ {
+		inputs = append(inputs, limb(left, i))
+	}
+	for i := 0; i < m; i++ {
+		inputs = append(inputs, limb(right, i))
+	}
+	carries, err := f.api.Compiler().NewHint(emulated.CarryHint, m-1, inputs...)
+	if err != nil {
+		panic(err)
+	}
+
+	// the hint returns the carries shifted by 2^carryBits
+	offset := new(big.Int).Lsh(big.NewInt(1), uint(carryBits))
+	base := new(big.Int).Lsh(big.NewInt(1), uint(w))
+	var previous frontend.Variable = 0
+	for i := 0; i < m; i++ {
+		d := f.api.Add(f.api.Sub(limb(left, i), limb(right, i)), previous)
+		if i == m-1 {
+			f.api.AssertIsEqual(d, 0)
+			break
+		}
+		f.api.ToBinary(carries[i], carryBits+1)
+		carry := f.api.Sub(carries[i], offset)
+		f.api.AssertIsEqual(d, f.api.Mul(carry, base))
+		previous = carry
+	}
+}
+
+// enforceWidth range checks the limbs of an integer which doesn't come from an
+// Arithmetic operation.
+func (f *Arithmetic) enforceWidth(a *Int) {
+	if a.internal {
+		return
+	}
+	if len(a.Limbs) == 0 {
+		panic("integer without limbs")
+	}
+	if _, ok := f.checked[&a.Limbs[0]]; ok {
+		return
+	}
+	f.rangeCheck(a.Limbs)
+	f.checked[&a.Limbs[0]] = struct{}{}
+}
+
+// rangeCheck asserts that the limbs have w bits
+func (f *Arithmetic) rangeCheck(limbs []frontend.Variable) {
+	for i := range limbs {
+		f.api.ToBinary(limbs[i], f.bitsPerLimb)
+	}
+}
+
+// limb returns the i-th limb of limbs, 0 if there are less limbs
+func limb(limbs []frontend.Variable, i int) frontend.Variable {
+	if i < len(limbs) {
+		return limbs[i]
+	}
+	return 0
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
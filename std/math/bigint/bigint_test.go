@@ -0,0 +1,114 @@
+package bigint
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+var (
+	testParams   = Params{BitsPerLimb: 32, NbLimbs: 4}
+	resultParams = Params{BitsPerLimb: 32, NbLimbs: 8}
+	testExponent = big.NewInt(65537)
+)
+
+type arithmeticCircuit struct {
+	A, B, N                     Int
+	Sum, Prod, Mod, ModMul, Exp Int
+	Bits                        []frontend.Variable
+}
+
+func (circuit *arithmeticCircuit) Define(api frontend.API) error {
+	f, err := NewArithmetic(api, testParams)
+	if err != nil {
+		return err
+	}
+	f.AssertIsEqual(f.Add(&circuit.A, &circuit.B), &circuit.Sum)
+	f.AssertIsEqual(f.Mul(&circuit.A, &circuit.B), &circuit.Prod)
+	f.AssertIsEqual(f.Mod(&circuit.Prod, &circuit.N), &circuit.Mod)
+	f.AssertIsEqual(f.ModMul(&circuit.A, &circuit.B, &circuit.N), &circuit.Mod)
+	f.AssertIsEqual(f.ModMul(f.Mul(&circuit.A, &circuit.A), &circuit.B, &circuit.N), &circuit.ModMul)
+	f.AssertIsEqual(f.ModExp(&circuit.A, testExponent, &circuit.N), &circuit.Exp)
+	f.AssertIsLess(&circuit.Mod, &circuit.N)
+
+	bits := f.ToBits(&circuit.A)
+	for i := range bits {
+		api.AssertIsEqual(bits[i], circuit.Bits[i])
+	}
+	f.AssertIsEqual(f.FromBits(bits...), &circuit.A)
+	f.AssertIsEqual(f.Add(f.Constant(big.NewInt(0)), f.Constant(big.NewInt(5))), f.Constant(big.NewInt(5)))
+	return nil
+}
+
+func newArithmeticAssignment(t *testing.T, a, b, n *big.Int) *arithmeticCircuit {
+	nbBits := testParams.BitsPerLimb * testParams.NbLimbs
+	sum := new(big.Int).Add(a, b)
+	prod := new(big.Int).Mul(a, b)
+	modMul := new(big.Int).Mul(prod, a)
+	w := arithmeticCircuit{
+		A:      testParams.ValueOf(a),
+		B:      testParams.ValueOf(b),
+		N:      testParams.ValueOf(n),
+		Sum:    resultParams.ValueOf(sum),
+		Prod:   resultParams.ValueOf(prod),
+		Mod:    resultParams.ValueOf(new(big.Int).Mod(prod, n)),
+		ModMul: resultParams.ValueOf(modMul.Mod(modMul, n)),
+		Exp:    resultParams.ValueOf(new(big.Int).Exp(a, testExponent, n)),
+		Bits:   make([]frontend.Variable, nbBits),
+	}
+	for i := range w.Bits {
+		w.Bits[i] = a.Bit(i)
+	}
+	return &w
+}
+
+func TestArithmetic(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	circuit := arithmeticCircuit{
+		A: testParams.Placeholder(), B: testParams.Placeholder(), N: testParams.Placeholder(),
+		Sum: resultParams.Placeholder(), Prod: resultParams.Placeholder(), Mod: resultParams.Placeholder(),
+		ModMul: resultParams.Placeholder(), Exp: resultParams.Placeholder(),
+		Bits: make([]frontend.Variable, testParams.BitsPerLimb*testParams.NbLimbs),
+	}
+
+	max := new(big.Int).Lsh(big.NewInt(1), uint(testParams.BitsPerLimb*testParams.NbLimbs))
+	a, err := rand.Int(rand.Reader, max)
+	assert.NoError(err)
+	b, err := rand.Int(rand.Reader, max)
+	assert.NoError(err)
+	n, err := rand.Int(rand.Reader, max)
+	assert.NoError(err)
+	n.SetBit(n, max.BitLen()-2, 1)
+
+	assert.SolvingSucceeded(&circuit, newArithmeticAssignment(t, a, b, n), test.WithCurves(ecc.BN254))
+
+	// a larger than n, and all the limbs saturated
+	allOnes := new(big.Int).Sub(max, big.NewInt(1))
+	assert.SolvingSucceeded(&circuit, newArithmeticAssignment(t, allOnes, allOnes, n), test.WithCurves(ecc.BN254))
+
+	// wrong product
+	w := newArithmeticAssignment(t, a, b, n)
+	w.Prod = resultParams.ValueOf(new(big.Int).Add(new(big.Int).Mul(a, b), n))
+	assert.SolvingFailed(&circuit, w, test.WithCurves(ecc.BN254))
+
+	// non-reduced remainder
+	w = newArithmeticAssignment(t, a, b, n)
+	w.Mod = resultParams.ValueOf(new(big.Int).Add(new(big.Int).Mod(new(big.Int).Mul(a, b), n), n))
+	assert.SolvingFailed(&circuit, w, test.WithCurves(ecc.BN254))
+
+	// wrong exponentiation
+	w = newArithmeticAssignment(t, a, b, n)
+	w.Exp = resultParams.ValueOf(new(big.Int).Exp(a, big.NewInt(65539), n))
+	assert.SolvingFailed(&circuit, w, test.WithCurves(ecc.BN254))
+
+	// non-canonical limb
+	w = newArithmeticAssignment(t, a, b, n)
+	w.A.Limbs[1] = new(big.Int).Add(w.A.Limbs[1].(*big.Int), new(big.Int).Lsh(big.NewInt(1), 32))
+	w.A.Limbs[2] = new(big.Int).Sub(w.A.Limbs[2].(*big.Int), big.NewInt(1))
+	assert.SolvingFailed(&circuit, w, test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bigint
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+)
+
+func init() {
+	hint.Register(QuoRemHint)
+	hint.Register(SubHint)
+}
+
+// QuoRemHint computes the limbs of the quotient and the remainder of the
+// euclidean division by a modulus of a value given as the coefficients of a
+// polynomial evaluated at 2^w. Without modulus, it computes the limbs of the
+// value (i.e. the remainder, the quotient having no limbs).
+//
+// The inputs are w, the number of limbs of the quotient and of the remainder,
+// the number of limbs k of the modulus, the k limbs of the modulus, and the
+// coefficients of the value.
+func QuoRemHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 4 || len(inputs) < 4+int(inputs[3].Uint64()) {
+		return errors.New("missing inputs")
+	}
+	nbBits := int(inputs[0].Uint64())
+	nbQuotientLimbs := int(inputs[1].Uint64())
+	nbRemainderLimbs := int(inputs[2].Uint64())
+	nbLimbs := int(inputs[3].Uint64())
+	if len(outputs) != nbQuotientLimbs+nbRemainderLimbs {
+		return errors.New("invalid number of outputs")
+	}
+	value := recompose(inputs[4+nbLimbs:], nbBits)
+
+	quotient, remainder := new(big.Int), value
+	if nbLimbs > 0 {
+		modulus := recompose(inputs[4:4+nbLimbs], nbBits)
+		if modulus.Sign() == 0 {
+			return errors.New("division by zero")
+		}
+		quotient.QuoRem(value, modulus, remainder)
+	}
+	if quotient.BitLen() > nbQuotientLimbs*nbBits || remainder.BitLen() > nbRemainderLimbs*nbBits {
+		return errors.New("result doesn't fit in the given number of limbs")
+	}
+	for i, l := range decompose(quotient, nbBits, nbQuotientLimbs) {
+		outputs[i].Set(l)
+	}
+	for i, l := range decompose(remainder, nbBits, nbRemainderLimbs) {
+		outputs[nbQuotientLimbs+i].Set(l)
+	}
+	return nil
+}
+
+// SubHint computes the limbs of b - a - c, for a small constant c, which must
+// be non-negative.
+//
+// The inputs are w, c, the number of limbs of a, the limbs of a and the limbs
+// of b. The number of outputs is the number of limbs of b.
+func SubHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 3 || len(inputs) < 3+int(inputs[2].Uint64()) {
+		return errors.New("missing inputs")
+	}
+	nbBits := int(inputs[0].Uint64())
+	nbLimbs := int(inputs[2].Uint64())
+	a := recompose(inputs[3:3+nbLimbs], nbBits)
+	b := recompose(inputs[3+nbLimbs:], nbBits)
+
+	b.Sub(b, a).Sub(b, inputs[1])
+	if b.Sign() < 0 {
+		return errors.New("negative difference")
+	}
+	if b.BitLen() > len(outputs)*nbBits {
+		return errors.New("result doesn't fit in the given number of limbs")
+	}
+	for i, l := range decompose(b, nbBits, len(outputs)) {
+		outputs[i].Set(l)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bigint
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Params describes the representation of the integers allocated in the
+// witness: NbLimbs limbs of BitsPerLimb bits.
+type Params struct {
+	BitsPerLimb int
+	NbLimbs     int
+}
+
+// Parameters of the integers of common RSA moduli sizes, with 64 bits limbs
+var (
+	RSA2048 = Params{64, 32}
+	RSA3072 = Params{64, 48}
+	RSA4096 = Params{64, 64}
+)
+
+// Placeholder returns an integer with unassigned limbs, to be used in a circuit
+// definition.
+func (p Params) Placeholder() Int {
+	return Int{Limbs: make([]frontend.Variable, p.NbLimbs)}
+}
+
+// ValueOf returns an integer whose limbs are assigned to the decomposition of
+// v, to be used in a circuit assignment. It panics if v is negative or doesn't
+// fit in NbLimbs·BitsPerLimb bits.
+func (p Params) ValueOf(v *big.Int) Int {
+	if v.Sign() < 0 || v.BitLen() > p.NbLimbs*p.BitsPerLimb {
+		panic(fmt.Sprintf("%s doesn't fit in %d limbs of %d bits", v, p.NbLimbs, p.BitsPerLimb))
+	}
+	limbs := decompose(v, p.BitsPerLimb, p.NbLimbs)
+	res := Int{Limbs: make([]frontend.Variable, p.NbLimbs)}
+	for i := range limbs {
+		res.Limbs[i] = limbs[i]
+	}
+	return res
+}
+
+// decompose returns the nbLimbs limbs of nbBits bits of n, in little endian order.
+func decompose(n *big.Int, nbBits, nbLimbs int) []*big.Int {
+	res := make([]*big.Int, nbLimbs)
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(nbBits)), big.NewInt(1))
+	tmp := new(big.Int).Set(n)
+	for i := 0; i < nbLimbs; i++ {
+		res[i] = new(big.Int).And(tmp, mask)
+		tmp.Rsh(tmp, uint(nbBits))
+	}
+	return res
+}
+
+// recompose returns ∑ limbs[i]·2^{nbBits·i}. The limbs may be larger than
+// nbBits bits.
+func recompose(limbs []*big.Int, nbBits int) *big.Int {
+	res := new(big.Int)
+	for i := len(limbs) - 1; i >= 0; i-- {
+		res.Lsh(res, uint(nbBits))
+		res.Add(res, limbs[i])
+	}
+	return res
+}
@@ -0,0 +1,53 @@
+package bits
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// AssertBitsLessOrEqual checks that the integer with the given little endian
+// bits is smaller than or equal to the constant bound. The bits must be
+// boolean constrained by the caller.
+//
+// ToBinary alone does not ensure that a decomposition is canonical (the bits of
+// v+r also decompose v); checking the bits against r-1 does.
+func AssertBitsLessOrEqual(api frontend.API, bits []frontend.Variable, bound *big.Int) {
+	if bound.Sign() == -1 {
+		panic("AssertBitsLessOrEqual: bound must be positive")
+	}
+
+	// p[i] == 1 → bits[j] == bound[j] for all j ⩾ i
+	p := make([]frontend.Variable, len(bits)+1)
+	p[len(bits)] = 1
+	for i := len(bits) - 1; i >= 0; i-- {
+		if bound.Bit(i) == 0 {
+			p[i] = p[i+1]
+		} else {
+			p[i] = api.Mul(p[i+1], bits[i])
+		}
+	}
+
+	// where the bound has a 0, the bit must be 0 if the bits above are equal
+	for i := len(bits) - 1; i >= 0; i-- {
+		if bound.Bit(i) == 0 {
+			api.AssertIsEqual(api.Mul(p[i+1], bits[i]), 0)
+		}
+	}
+}
+
+// ToCanonicalBinary returns the little endian bits of v, checked to be smaller
+// than the modulus
+func ToCanonicalBinary(api frontend.API, v frontend.Variable) []frontend.Variable {
+	modulus := api.Compiler().Curve().Info().Fr.Modulus()
+	b := api.ToBinary(v)
+	AssertBitsLessOrEqual(api, b, new(big.Int).Sub(modulus, big.NewInt(1)))
+	return b
+}
+
+// IsLexicographicallyLargest returns 1 if x > (q-1)/2, 0 otherwise, q being the
+// modulus. For x in [0, q), 2x mod q is 2x (even) if x ⩽ (q-1)/2 and 2x-q (odd)
+// otherwise.
+func IsLexicographicallyLargest(api frontend.API, x frontend.Variable) frontend.Variable {
+	return ToCanonicalBinary(api, api.Add(x, x))[0]
+}
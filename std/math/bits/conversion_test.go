@@ -1,8 +1,10 @@
 package bits_test
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/math/bits"
 	"github.com/consensys/gnark/test"
@@ -57,3 +59,51 @@ func TestToTernary(t *testing.T) {
 	assert := test.NewAssert(t)
 	assert.ProverSucceeded(&toTernaryCircuit{}, &toTernaryCircuit{A: 5, T0: 2, T1: 1, T2: 0})
 }
+
+type assertBitsLessOrEqualCircuit struct {
+	A frontend.Variable
+}
+
+func (c *assertBitsLessOrEqualCircuit) Define(api frontend.API) error {
+	b := api.ToBinary(c.A, 8)
+	bits.AssertBitsLessOrEqual(api, b, big.NewInt(0b10110))
+	return nil
+}
+
+func TestAssertBitsLessOrEqual(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, a := range []int{0, 0b10101, 0b10110} {
+		assert.ProverSucceeded(&assertBitsLessOrEqualCircuit{}, &assertBitsLessOrEqualCircuit{A: a})
+	}
+	for _, a := range []int{0b10111, 0b11000, 0b100000, 0b10000000} {
+		assert.ProverFailed(&assertBitsLessOrEqualCircuit{}, &assertBitsLessOrEqualCircuit{A: a})
+	}
+}
+
+type isLexicographicallyLargestCircuit struct {
+	A, Largest frontend.Variable
+}
+
+func (c *isLexicographicallyLargestCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(bits.IsLexicographicallyLargest(api, c.A), c.Largest)
+	return nil
+}
+
+func TestIsLexicographicallyLargest(t *testing.T) {
+	assert := test.NewAssert(t)
+	q := ecc.BN254.Info().Fr.Modulus()
+	half := new(big.Int).Rsh(q, 1)
+	for _, tc := range []struct {
+		a       *big.Int
+		largest int
+	}{
+		{big.NewInt(0), 0},
+		{big.NewInt(1), 0},
+		{half, 0},
+		{new(big.Int).Add(half, big.NewInt(1)), 1},
+		{new(big.Int).Sub(q, big.NewInt(1)), 1},
+	} {
+		assert.ProverSucceeded(&isLexicographicallyLargestCircuit{}, &isLexicographicallyLargestCircuit{A: tc.a, Largest: tc.largest}, test.WithCurves(ecc.BN254))
+		assert.ProverFailed(&isLexicographicallyLargestCircuit{}, &isLexicographicallyLargestCircuit{A: tc.a, Largest: 1 - tc.largest}, test.WithCurves(ecc.BN254))
+	}
+}
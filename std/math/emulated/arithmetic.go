@@ -0,0 +1,319 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Add returns a + b.
+func (f *Field) Add(a, b *Element) *Element {
+	f.enforceWidth(a)
+	f.enforceWidth(b)
+	overflow := maxUint(a.overflow, b.overflow) + 1
+	if overflow > f.maxOverflow {
+		a, b = f.Reduce(a), f.Reduce(b)
+		overflow = 1
+	}
+	res := &Element{Limbs: make([]frontend.Variable, f.params.NbLimbs), overflow: overflow, internal: true}
+	for i := range res.Limbs {
+		res.Limbs[i] = f.api.Add(a.Limbs[i], b.Limbs[i])
+	}
+	return res
+}
+
+// Sub returns a - b.
+func (f *Field) Sub(a, b *Element) *Element {
+	f.enforceWidth(a)
+	f.enforceWidth(b)
+
+	// to keep the limbs positive, we add to a a multiple of p whose limbs are
+	// larger than the limbs of b.
+	overflow := maxUint(a.overflow, b.overflow+1) + 1
+	if overflow > f.maxOverflow {
+		a, b = f.Reduce(a), f.Reduce(b)
+		overflow = 2
+	}
+	padding := f.subPadding(b.overflow)
+	res := &Element{Limbs: make([]frontend.Variable, f.params.NbLimbs), overflow: overflow, internal: true}
+	for i := range res.Limbs {
+		res.Limbs[i] = f.api.Sub(f.api.Add(a.Limbs[i], padding[i]), b.Limbs[i])
+	}
+	return res
+}
+
+// Neg returns -a.
+func (f *Field) Neg(a *Element) *Element {
+	return f.Sub(f.Zero(), a)
+}
+
+// Mul returns a·b.
+func (f *Field) Mul(a, b *Element) *Element {
+	f.enforceWidth(a)
+	f.enforceWidth(b)
+	if a.overflow > f.maxOverflow {
+		a = f.Reduce(a)
+	}
+	if b.overflow > f.maxOverflow {
+		b = f.Reduce(b)
+	}
+
+	// a·b = q·p + r
+	nbQuotientLimbs := f.quotientLimbs(f.bitLen(a) + f.bitLen(b))
+	q, r := f.computeQuoRem(nbQuotientLimbs, f.params.NbLimbs, a, b)
+
+	w := f.params.BitsPerLimb
+	nbBits := 2*w + int(a.overflow+b.overflow) + bits.Len(uint(f.params.NbLimbs))
+	if rightBits := 2*w + bits.Len(uint(minInt(nbQuotientLimbs, f.params.NbLimbs))) + 1; rightBits > nbBits {
+		nbBits = rightBits
+	}
+	right := f.mulLimbs(q, f.modulusLimbs())
+	for i := range r.Limbs {
+		right[i] = f.api.Add(right[i], r.Limbs[i])
+	}
+	f.checkZero(f.mulLimbs(a.Limbs, b.Limbs), right, nbBits)
+
+	return r
+}
+
+// MulConst returns c·a, for a small non-negative constant c. The limbs are
+// multiplied by c, which doesn't add constraints but increases their overflow.
+func (f *Field) MulConst(a *Element, c *big.Int) *Element {
+	if c.Sign() < 0 {
+		panic("negative constant")
+	}
+	f.enforceWidth(a)
+	overflow := a.overflow + uint(c.BitLen())
+	if overflow > f.maxOverflow {
+		a = f.Reduce(a)
+		overflow = uint(c.BitLen())
+		if overflow > f.maxOverflow {
+			panic("constant too large")
+		}
+	}
+	res := &Element{Limbs: make([]frontend.Variable, f.params.NbLimbs), overflow: overflow, internal: true}
+	for i := range res.Limbs {
+		res.Limbs[i] = f.api.Mul(a.Limbs[i], c)
+	}
+	return res
+}
+
+// Square returns a².
+func (f *Field) Square(a *Element) *Element {
+	return f.Mul(a, a)
+}
+
+// Exp returns aᵉ, for a constant non-negative exponent e.
+func (f *Field) Exp(a *Element, e *big.Int) *Element {
+	res := f.One()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = f.Square(res)
+		if e.Bit(i) == 1 {
+			res = f.Mul(res, a)
+		}
+	}
+	return res
+}
+
+// Div returns a/b. The circuit can't be satisfied if b ≡ 0.
+func (f *Field) Div(a, b *Element) *Element {
+	f.enforceWidth(a)
+	f.enforceWidth(b)
+	inputs := []frontend.Variable{f.params.BitsPerLimb, f.params.NbLimbs}
+	inputs = append(inputs, f.modulusLimbs()...)
+	inputs = append(inputs, a.Limbs...)
+	inputs = append(inputs, b.Limbs...)
+	limbs, err := f.api.Compiler().NewHint(DivHint, f.params.NbLimbs, inputs...)
+	if err != nil {
+		panic(err)
+	}
+	res := &Element{Limbs: limbs}
+	f.enforceWidth(res)
+
+	f.AssertIsEqual(f.Mul(res, b), a)
+	return res
+}
+
+// Inverse returns 1/a. The circuit can't be satisfied if a ≡ 0.
+func (f *Field) Inverse(a *Element) *Element {
+	return f.Div(f.One(), a)
+}
+
+// Reduce returns an element equal to a modulo p, whose limbs don't overflow.
+func (f *Field) Reduce(a *Element) *Element {
+	f.enforceWidth(a)
+	if a.overflow == 0 {
+		return a
+	}
+	return f.reduce(a)
+}
+
+// reduce returns a mod p, computed by a hint and checked in the circuit.
+func (f *Field) reduce(a *Element) *Element {
+	nbQuotientLimbs := f.quotientLimbs(f.bitLen(a))
+	q, r := f.computeQuoRem(nbQuotientLimbs, f.params.NbLimbs, a)
+
+	w := f.params.BitsPerLimb
+	nbBits := w + int(a.overflow)
+	if rightBits := 2*w + bits.Len(uint(minInt(nbQuotientLimbs, f.params.NbLimbs))) + 1; rightBits > nbBits {
+		nbBits = rightBits
+	}
+	right := f.mulLimbs(q, f.modulusLimbs())
+	for i := range r.Limbs {
+		right[i] = f.api.Add(right[i], r.Limbs[i])
+	}
+	f.checkZero(a.Limbs, right, nbBits)
+
+	return r
+}
+
+// computeQuoRem returns the (range checked) limbs of the quotient and the
+// remainder of the euclidean division of ∏ elements by p.
+func (f *Field) computeQuoRem(nbQuotientLimbs, nbRemainderLimbs int, elements ...*Element) ([]frontend.Variable, *Element) {
+	w := f.params.BitsPerLimb
+	inputs := []frontend.Variable{w, nbQuotientLimbs, nbRemainderLimbs, f.params.NbLimbs}
+	inputs = append(inputs, f.modulusLimbs()...)
+	inputs = append(inputs, len(elements))
+	for _, e := range elements {
+		inputs = append(inputs, len(e.Limbs))
+		inputs = append(inputs, e.Limbs...)
+	}
+	res, err := f.api.Compiler().NewHint(QuoRemHint, nbQuotientLimbs+nbRemainderLimbs, inputs...)
+	if err != nil {
+		panic(err)
+	}
+	for i := range res {
+		f.api.ToBinary(res[i], w)
+	}
+	return res[:nbQuotientLimbs], &Element{Limbs: res[nbQuotientLimbs:], internal: true}
+}
+
+// quotientLimbs returns the number of limbs of the quotient by p of a value of
+// nbBits bits
+func (f *Field) quotientLimbs(nbBits int) int {
+	w := f.params.BitsPerLimb
+	nbQuotientBits := nbBits - f.params.Modulus.BitLen() + 1
+	if nbQuotientBits < 1 {
+		nbQuotientBits = 1
+	}
+	return (nbQuotientBits + w - 1) / w
+}
+
+// mulLimbs returns the coefficients of the product of the polynomials of
+// coefficients x and y
+func (f *Field) mulLimbs(x, y []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(x)+len(y)-1)
+	for i := range res {
+		res[i] = 0
+	}
+	for i := range x {
+		for j := range y {
+			res[i+j] = f.api.Add(res[i+j], f.api.Mul(x[i], y[j]))
+		}
+	}
+	return res
+}
+
+// checkZero asserts that the polynomials of coefficients left and right
+// evaluate to the same integer at 2^w, where w is the number of bits per limb.
+// All the coefficients must be positive and smaller than 2^nbBits.
+//
+// With dᵢ = leftᵢ - rightᵢ, the carries cᵢ (hinted and range checked) must
+// verify dᵢ + cᵢ₋₁ = cᵢ·2^w, and the last carry must be 0. As |dᵢ| < 2^nbBits,
+// the carries are bounded by 2^{nbBits-w+1}.
+func (f *Field) checkZero(left, right []frontend.Variable, nbBits int) {
+	w := f.params.BitsPerLimb
+	m := len(left)
+	if len(right) > m {
+		m = len(right)
+	}
+	coefficient := func(c []frontend.Variable, i int) frontend.Variable {
+		if i < len(c) {
+			return c[i]
+		}
+		return 0
+	}
+	if m == 1 {
+		f.api.AssertIsEqual(left[0], right[0])
+		return
+	}
+
+	carryBits := nbBits - w + 1
+	inputs := []frontend.Variable{w, carryBits, m}
+	for i := 0; i < m; i++ {
+		inputs = append(inputs, coefficient(left, i))
+	}
+	for i := 0; i < m; i++ {
+		inputs = append(inputs, coefficient(right, i))
+	}
+	carries, err := f.api.Compiler().NewHint(CarryHint, m-1, inputs...)
+	if err != nil {
+		panic(err)
+	}
+
+	// the hint returns the carries shifted by 2^carryBits
+	offset := new(big.Int).Lsh(big.NewInt(1), uint(carryBits))
+	base := new(big.Int).Lsh(big.NewInt(1), uint(w))
+	var previous frontend.Variable = 0
+	for i := 0; i < m; i++ {
+		d := f.api.Add(f.api.Sub(coefficient(left, i), coefficient(right, i)), previous)
+		if i == m-1 {
+			f.api.AssertIsEqual(d, 0)
+			break
+		}
+		f.api.ToBinary(carries[i], carryBits+1)
+		carry := f.api.Sub(carries[i], offset)
+		f.api.AssertIsEqual(d, f.api.Mul(carry, base))
+		previous = carry
+	}
+}
+
+// subPadding returns the limbs of a multiple of p, whose limbs are all larger
+// than 2^{w+overflow}.
+func (f *Field) subPadding(overflow uint) []*big.Int {
+	w := f.params.BitsPerLimb
+	limb := new(big.Int).Lsh(big.NewInt(1), uint(w)+overflow)
+	padding := make([]*big.Int, f.params.NbLimbs)
+	for i := range padding {
+		padding[i] = new(big.Int).Set(limb)
+	}
+
+	// complete the padding with (-padding mod p)
+	n := recompose(padding, w)
+	n.Neg(n).Mod(n, f.params.Modulus)
+	for i, l := range decompose(n, w, f.params.NbLimbs) {
+		padding[i].Add(padding[i], l)
+	}
+	return padding
+}
+
+func maxUint(a, b uint) uint {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
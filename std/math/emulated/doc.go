@@ -0,0 +1,35 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package emulated implements arithmetic modulo a prime which is not the
+// modulus of the native field of the circuit (non-native arithmetic).
+//
+// An emulated element x is represented by limbs x₀, …, x_{k-1} of w bits in
+// little endian order, such that x = ∑ xᵢ·2^{w·i}. The additions and
+// subtractions are performed limb-wise and let the limbs grow ("overflow")
+// past w bits; the elements are reduced automatically when their limbs would
+// not fit in the native field anymore.
+//
+// A multiplication a·b = c mod p is computed out-of-circuit by a hint, which
+// returns the quotient q and the remainder c. The circuit then asserts that
+// the integers a·b and q·p + c are equal, by checking that the difference of
+// the limb-wise polynomial products evaluates to zero at 2^w, with hinted and
+// range checked carries.
+//
+// The results of the operations are not necessarily in canonical form (i.e.
+// they may be larger than the modulus, while being smaller than 2^{w·k}); use
+// Field.ToBits to obtain a canonical representation.
+package emulated
@@ -0,0 +1,245 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Element is an element of an emulated field.
+//
+// The limbs of an element obtained from a Field are range checked. The limbs of
+// an element allocated in the circuit witness (see Params.Placeholder and
+// Params.ValueOf) are range checked the first time it is used by a Field.
+type Element struct {
+	Limbs []frontend.Variable
+
+	// overflow is the number of bits the limbs may exceed Params.BitsPerLimb
+	overflow uint
+	// internal is true if the width of the limbs is enforced
+	internal bool
+}
+
+// Field performs the arithmetic of an emulated field in a circuit.
+type Field struct {
+	api    frontend.API
+	params Params
+
+	// maxOverflow is the maximal overflow of the limbs of the operands of a
+	// multiplication, such that the coefficients of the limb-wise product fit
+	// in the native field.
+	maxOverflow uint
+
+	// checked records the witness elements whose limbs were range checked, by
+	// the address of their first limb, which is shared by the copies of an
+	// element. We don't mark the elements themselves, as the circuit structure
+	// may be compiled several times.
+	checked map[*frontend.Variable]struct{}
+}
+
+// NewField returns a Field emulating the field described by params in the
+// circuit of api.
+func NewField(api frontend.API, params Params) (*Field, error) {
+	if params.Modulus == nil || params.Modulus.Sign() <= 0 {
+		return nil, errors.New("invalid modulus")
+	}
+	if params.NbLimbs <= 0 || params.BitsPerLimb <= 0 {
+		return nil, errors.New("invalid number of limbs")
+	}
+	if params.NbLimbs*params.BitsPerLimb < params.Modulus.BitLen() {
+		return nil, fmt.Errorf("%d limbs of %d bits can't represent a %d bits modulus", params.NbLimbs, params.BitsPerLimb, params.Modulus.BitLen())
+	}
+
+	// the coefficients of a limb-wise product are smaller than 2^(2(w+overflow)+log(2k)),
+	// and checkZero needs 3 more bits.
+	nativeBits := api.Compiler().Curve().Info().Fr.Bits - 1
+	headroom := nativeBits - 3 - 2*params.BitsPerLimb - bits.Len(uint(2*params.NbLimbs))
+	if headroom < 8 {
+		return nil, fmt.Errorf("limbs of %d bits are too large for the native field", params.BitsPerLimb)
+	}
+
+	return &Field{
+		api:         api,
+		params:      params,
+		maxOverflow: uint(headroom / 2),
+		checked:     make(map[*frontend.Variable]struct{}),
+	}, nil
+}
+
+// Modulus returns the modulus of the emulated field.
+func (f *Field) Modulus() *big.Int {
+	return new(big.Int).Set(f.params.Modulus)
+}
+
+// Constant returns the element of value v mod p, where v can be of any type
+// supported by frontend.Variable assignments.
+func (f *Field) Constant(v interface{}) *Element {
+	res := f.params.ValueOf(v)
+	res.internal = true
+	return &res
+}
+
+// Zero returns the element 0.
+func (f *Field) Zero() *Element {
+	return f.Constant(0)
+}
+
+// One returns the element 1.
+func (f *Field) One() *Element {
+	return f.Constant(1)
+}
+
+// FromBits returns the element of little endian bits. The bits must be
+// constrained to be boolean and there must be at most NbLimbs·BitsPerLimb of
+// them; the result is not reduced.
+func (f *Field) FromBits(bs ...frontend.Variable) *Element {
+	w := f.params.BitsPerLimb
+	if len(bs) > f.params.NbLimbs*w {
+		panic(fmt.Sprintf("%d bits don't fit in %d limbs of %d bits", len(bs), f.params.NbLimbs, w))
+	}
+	res := &Element{Limbs: make([]frontend.Variable, f.params.NbLimbs), internal: true}
+	for i := range res.Limbs {
+		if i*w >= len(bs) {
+			res.Limbs[i] = 0
+			continue
+		}
+		end := (i + 1) * w
+		if end > len(bs) {
+			end = len(bs)
+		}
+		res.Limbs[i] = f.api.FromBinary(bs[i*w : end]...)
+	}
+	return res
+}
+
+// ToBits returns the canonical (i.e. reduced modulo p) little endian bits of a,
+// Modulus().BitLen() of them.
+func (f *Field) ToBits(a *Element) []frontend.Variable {
+	f.enforceWidth(a)
+	r := f.reduce(a)
+	w := f.params.BitsPerLimb
+	nbBits := f.params.Modulus.BitLen()
+	res := make([]frontend.Variable, 0, f.params.NbLimbs*w)
+	for i := range r.Limbs {
+		res = append(res, f.api.ToBinary(r.Limbs[i], w)...)
+	}
+	for i := nbBits; i < len(res); i++ {
+		f.api.AssertIsEqual(res[i], 0)
+	}
+	res = res[:nbBits]
+	assertBitsLess(f.api, res, f.params.Modulus)
+	return res
+}
+
+// Select returns a if b is 1, c if b is 0. b must be boolean.
+func (f *Field) Select(b frontend.Variable, a, c *Element) *Element {
+	f.enforceWidth(a)
+	f.enforceWidth(c)
+	res := &Element{Limbs: make([]frontend.Variable, f.params.NbLimbs), internal: true}
+	for i := range res.Limbs {
+		res.Limbs[i] = f.api.Select(b, a.Limbs[i], c.Limbs[i])
+	}
+	res.overflow = a.overflow
+	if c.overflow > res.overflow {
+		res.overflow = c.overflow
+	}
+	return res
+}
+
+// IsZero returns 1 if a ≡ 0 mod p, 0 otherwise.
+func (f *Field) IsZero(a *Element) frontend.Variable {
+	// the bits are all zero iff their sum is zero
+	r := f.ToBits(a)
+	return f.api.IsZero(f.api.Add(0, 0, r...))
+}
+
+// AssertIsEqual fails if a ≢ b mod p.
+func (f *Field) AssertIsEqual(a, b *Element) {
+	// a - b is a multiple of p: check a - b = k·p for a hinted k
+	diff := f.Sub(a, b)
+	nbQuotientLimbs := f.quotientLimbs(f.bitLen(diff))
+	k, _ := f.computeQuoRem(nbQuotientLimbs, 0, diff)
+
+	w := f.params.BitsPerLimb
+	nbBits := w + int(diff.overflow)
+	if rightBits := 2*w + bits.Len(uint(minInt(nbQuotientLimbs, f.params.NbLimbs))); rightBits > nbBits {
+		nbBits = rightBits
+	}
+	f.checkZero(diff.Limbs, f.mulLimbs(k, f.modulusLimbs()), nbBits)
+}
+
+// enforceWidth range checks the limbs of an element which doesn't come from a
+// Field operation.
+func (f *Field) enforceWidth(a *Element) {
+	if a.internal {
+		return
+	}
+	if len(a.Limbs) != f.params.NbLimbs {
+		panic(fmt.Sprintf("expected %d limbs, got %d", f.params.NbLimbs, len(a.Limbs)))
+	}
+	if _, ok := f.checked[&a.Limbs[0]]; ok {
+		return
+	}
+	for i := range a.Limbs {
+		f.api.ToBinary(a.Limbs[i], f.params.BitsPerLimb)
+	}
+	f.checked[&a.Limbs[0]] = struct{}{}
+}
+
+// bitLen returns an upper bound on the bit length of the value of a
+func (f *Field) bitLen(a *Element) int {
+	return len(a.Limbs)*f.params.BitsPerLimb + int(a.overflow) + 1
+}
+
+// modulusLimbs returns the limbs of the modulus, as constants
+func (f *Field) modulusLimbs() []frontend.Variable {
+	limbs := decompose(f.params.Modulus, f.params.BitsPerLimb, f.params.NbLimbs)
+	res := make([]frontend.Variable, len(limbs))
+	for i := range limbs {
+		res[i] = limbs[i]
+	}
+	return res
+}
+
+// assertBitsLess asserts that the number of little endian bits bs is strictly
+// smaller than bound. The bits must be constrained to be boolean.
+func assertBitsLess(api frontend.API, bs []frontend.Variable, bound *big.Int) {
+	if bound.BitLen() > len(bs) {
+		return
+	}
+	for i := len(bs) - 1; i >= bound.BitLen(); i-- {
+		api.AssertIsEqual(bs[i], 0)
+	}
+
+	// eq is 1 while the bits processed so far (from the most significant) are
+	// equal to the bits of bound
+	var eq frontend.Variable = 1
+	for i := bound.BitLen() - 1; i >= 0; i-- {
+		if bound.Bit(i) == 1 {
+			eq = api.Mul(eq, bs[i])
+		} else {
+			// once smaller, any bit is allowed, otherwise the bit must be 0
+			api.AssertIsEqual(api.Mul(eq, bs[i]), 0)
+		}
+	}
+	api.AssertIsEqual(eq, 0)
+}
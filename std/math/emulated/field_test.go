@@ -0,0 +1,139 @@
+package emulated
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+)
+
+type arithmeticCircuit struct {
+	params Params
+
+	A, B                   Element
+	Sum, Diff, Prod, Quo   Element
+	Square, Exp, Expr, Neg Element
+	Bits                   []frontend.Variable
+}
+
+func (circuit *arithmeticCircuit) Define(api frontend.API) error {
+	f, err := NewField(api, circuit.params)
+	if err != nil {
+		return err
+	}
+	f.AssertIsEqual(f.Add(&circuit.A, &circuit.B), &circuit.Sum)
+	f.AssertIsEqual(f.Sub(&circuit.A, &circuit.B), &circuit.Diff)
+	f.AssertIsEqual(f.Mul(&circuit.A, &circuit.B), &circuit.Prod)
+	f.AssertIsEqual(f.Div(&circuit.A, &circuit.B), &circuit.Quo)
+	f.AssertIsEqual(f.Square(&circuit.A), &circuit.Square)
+	f.AssertIsEqual(f.Exp(&circuit.A, big.NewInt(1000)), &circuit.Exp)
+	f.AssertIsEqual(f.Neg(&circuit.A), &circuit.Neg)
+	f.AssertIsEqual(f.MulConst(&circuit.A, big.NewInt(9)), f.Mul(&circuit.A, f.Constant(9)))
+
+	// long chains of additions and subtractions overflow the limbs, which
+	// must be reduced before multiplying: (a+b-a+b)·(a+...+a) = 2b·10a
+	expr := f.Sub(f.Add(&circuit.A, &circuit.B), &circuit.A)
+	expr = f.Add(expr, &circuit.B)
+	acc := f.Zero()
+	for i := 0; i < 10; i++ {
+		acc = f.Add(acc, &circuit.A)
+	}
+	for i := 0; i < 40; i++ {
+		acc = f.Sub(f.Add(acc, &circuit.B), &circuit.B)
+	}
+	f.AssertIsEqual(f.Mul(expr, acc), &circuit.Expr)
+
+	bits := f.ToBits(f.Add(&circuit.A, &circuit.B))
+	for i := range bits {
+		api.AssertIsEqual(bits[i], circuit.Bits[i])
+	}
+	api.AssertIsEqual(f.IsZero(f.Sub(&circuit.A, &circuit.A)), 1)
+	api.AssertIsEqual(f.IsZero(&circuit.A), 0)
+	return nil
+}
+
+func newArithmeticAssignment(t *testing.T, params Params) (circuit, assignment *arithmeticCircuit) {
+	p := params.Modulus
+	a, err := rand.Int(rand.Reader, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := rand.Int(rand.Reader, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod := func(n *big.Int) *big.Int { return n.Mod(n, p) }
+	sum := mod(new(big.Int).Add(a, b))
+	quo := new(big.Int).ModInverse(b, p)
+	quo = mod(quo.Mul(quo, a))
+	expr := mod(new(big.Int).Mul(a, b))
+	expr = mod(expr.Mul(expr, big.NewInt(20)))
+
+	circuit = &arithmeticCircuit{params: params, Bits: make([]frontend.Variable, p.BitLen())}
+	for _, e := range []*Element{&circuit.A, &circuit.B, &circuit.Sum, &circuit.Diff, &circuit.Prod,
+		&circuit.Quo, &circuit.Square, &circuit.Exp, &circuit.Expr, &circuit.Neg} {
+		*e = params.Placeholder()
+	}
+	assignment = &arithmeticCircuit{
+		A:      params.ValueOf(a),
+		B:      params.ValueOf(b),
+		Sum:    params.ValueOf(sum),
+		Diff:   params.ValueOf(new(big.Int).Sub(a, b)),
+		Prod:   params.ValueOf(new(big.Int).Mul(a, b)),
+		Quo:    params.ValueOf(quo),
+		Square: params.ValueOf(new(big.Int).Mul(a, a)),
+		Exp:    params.ValueOf(new(big.Int).Exp(a, big.NewInt(1000), p)),
+		Expr:   params.ValueOf(expr),
+		Neg:    params.ValueOf(new(big.Int).Neg(a)),
+		Bits:   make([]frontend.Variable, p.BitLen()),
+	}
+	for i := range assignment.Bits {
+		assignment.Bits[i] = sum.Bit(i)
+	}
+	return
+}
+
+func TestArithmetic(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, tc := range []struct {
+		name   string
+		params Params
+		curve  ecc.ID
+	}{
+		{"bn254_fp/bn254", BN254Fp, ecc.BN254},
+		{"bls12_381_fp/bn254", BLS12381Fp, ecc.BN254},
+		{"bls12_377_fr/bw6_761", BLS12377Fr, ecc.BW6_761},
+		{"secp256k1_fr/bls12_377", Secp256k1Fr, ecc.BLS12_377},
+	} {
+		tc := tc
+		assert.Run(func(assert *test.Assert) {
+			circuit, assignment := newArithmeticAssignment(t, tc.params)
+			assert.SolvingSucceeded(circuit, assignment, test.WithCurves(tc.curve), test.WithBackends(backend.GROTH16))
+
+			// wrong product
+			_, wrong := newArithmeticAssignment(t, tc.params)
+			wrong.A, wrong.B = assignment.A, assignment.B
+			assert.SolvingFailed(circuit, wrong, test.WithCurves(tc.curve), test.WithBackends(backend.GROTH16))
+		}, tc.name)
+	}
+}
+
+func TestNewFieldErrors(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	circuit, _ := newArithmeticAssignment(t, BN254Fp)
+	circuit.params.NbLimbs = 3
+	_, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, circuit)
+	assert.Error(err, "3 limbs of 64 bits can't hold a 254 bits modulus")
+
+	circuit.params = Params{Modulus: BN254Fp.Modulus, NbLimbs: 2, BitsPerLimb: 127}
+	_, err = frontend.Compile(ecc.BN254, r1cs.NewBuilder, circuit)
+	assert.Error(err, "limbs too large for the native field")
+}
@@ -0,0 +1,132 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+)
+
+func init() {
+	hint.Register(QuoRemHint)
+	hint.Register(DivHint)
+	hint.Register(CarryHint)
+}
+
+// QuoRemHint computes the limbs of the quotient and the remainder of the
+// euclidean division of a product of emulated elements by the modulus.
+//
+// The inputs are the number of bits per limb, the number of limbs of the
+// quotient and of the remainder, the number of limbs k of the modulus, the k
+// limbs of the modulus, the number of elements, and for each element its number
+// of limbs followed by its limbs.
+func QuoRemHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 4 || len(inputs) < 5+int(inputs[3].Uint64()) {
+		return errors.New("missing inputs")
+	}
+	nbBits := int(inputs[0].Uint64())
+	nbQuotientLimbs := int(inputs[1].Uint64())
+	nbRemainderLimbs := int(inputs[2].Uint64())
+	nbLimbs := int(inputs[3].Uint64())
+	modulus := recompose(inputs[4:4+nbLimbs], nbBits)
+	if len(outputs) != nbQuotientLimbs+nbRemainderLimbs {
+		return errors.New("invalid number of outputs")
+	}
+
+	product := big.NewInt(1)
+	nbElements := int(inputs[4+nbLimbs].Uint64())
+	inputs = inputs[5+nbLimbs:]
+	for i := 0; i < nbElements; i++ {
+		if len(inputs) == 0 || len(inputs) <= int(inputs[0].Uint64()) {
+			return errors.New("missing limbs")
+		}
+		nbLimbs := int(inputs[0].Uint64())
+		product.Mul(product, recompose(inputs[1:1+nbLimbs], nbBits))
+		inputs = inputs[1+nbLimbs:]
+	}
+
+	var quotient, remainder big.Int
+	quotient.QuoRem(product, modulus, &remainder)
+	if quotient.BitLen() > nbQuotientLimbs*nbBits {
+		return errors.New("quotient doesn't fit in the given number of limbs")
+	}
+	for i, l := range decompose(&quotient, nbBits, nbQuotientLimbs) {
+		outputs[i].Set(l)
+	}
+	for i, l := range decompose(&remainder, nbBits, nbRemainderLimbs) {
+		outputs[nbQuotientLimbs+i].Set(l)
+	}
+	return nil
+}
+
+// DivHint computes the limbs of a/b mod p.
+//
+// The inputs are the number of bits per limb, the number of limbs k, and the k
+// limbs of the modulus, a and b.
+func DivHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 2 {
+		return errors.New("missing inputs")
+	}
+	nbBits := int(inputs[0].Uint64())
+	nbLimbs := int(inputs[1].Uint64())
+	if len(inputs) != 2+3*nbLimbs || len(outputs) != nbLimbs {
+		return errors.New("invalid number of inputs or outputs")
+	}
+	modulus := recompose(inputs[2:2+nbLimbs], nbBits)
+	a := recompose(inputs[2+nbLimbs:2+2*nbLimbs], nbBits)
+	b := recompose(inputs[2+2*nbLimbs:], nbBits)
+	b.Mod(b, modulus)
+	if b.ModInverse(b, modulus) == nil {
+		return errors.New("division by zero")
+	}
+	a.Mul(a, b).Mod(a, modulus)
+	for i, l := range decompose(a, nbBits, nbLimbs) {
+		outputs[i].Set(l)
+	}
+	return nil
+}
+
+// CarryHint computes the carries of the subtraction of two polynomials
+// evaluated at 2^w (see Field.checkZero). The carries are shifted by
+// 2^carryBits to be positive.
+//
+// The inputs are w, carryBits, the number of coefficients m and the m
+// coefficients of each polynomial.
+func CarryHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 3 {
+		return errors.New("missing inputs")
+	}
+	nbBits := uint(inputs[0].Uint64())
+	carryBits := uint(inputs[1].Uint64())
+	m := int(inputs[2].Uint64())
+	if len(inputs) != 3+2*m || len(outputs) != m-1 {
+		return errors.New("invalid number of inputs or outputs")
+	}
+	left, right := inputs[3:3+m], inputs[3+m:]
+
+	offset := new(big.Int).Lsh(big.NewInt(1), carryBits)
+	carry := new(big.Int)
+	for i := 0; i < m-1; i++ {
+		carry.Add(carry, left[i]).Sub(carry, right[i])
+		carry.Rsh(carry, nbBits)
+		outputs[i].Add(carry, offset)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// Params describes an emulated field: its modulus and the representation of
+// its elements as NbLimbs limbs of BitsPerLimb bits.
+type Params struct {
+	Modulus     *big.Int
+	NbLimbs     int
+	BitsPerLimb int
+}
+
+// Parameters of the scalar and base fields of common curves, with 64 bits limbs
+var (
+	BN254Fp     = Params{ecc.BN254.Info().Fp.Modulus(), 4, 64}
+	BN254Fr     = Params{ecc.BN254.Info().Fr.Modulus(), 4, 64}
+	BLS12377Fr  = Params{ecc.BLS12_377.Info().Fr.Modulus(), 4, 64}
+	BLS12381Fp  = Params{ecc.BLS12_381.Info().Fp.Modulus(), 6, 64}
+	BLS12381Fr  = Params{ecc.BLS12_381.Info().Fr.Modulus(), 4, 64}
+	Secp256k1Fp = Params{newInt("115792089237316195423570985008687907853269984665640564039457584007908834671663"), 4, 64}
+	Secp256k1Fr = Params{newInt("115792089237316195423570985008687907852837564279074904382605163141518161494337"), 4, 64}
+)
+
+func newInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid integer " + s)
+	}
+	return n
+}
+
+// Placeholder returns an element with unassigned limbs, to be used in a circuit
+// definition.
+func (p Params) Placeholder() Element {
+	return Element{Limbs: make([]frontend.Variable, p.NbLimbs)}
+}
+
+// ValueOf returns an element whose limbs are assigned to the decomposition of
+// v, to be used in a circuit assignment. v can be of any type supported by
+// frontend.Variable assignments (big.Int, string, uint64, fr.Element, ...) and
+// is reduced modulo p.Modulus.
+func (p Params) ValueOf(v interface{}) Element {
+	n := utils.FromInterface(v)
+	n.Mod(&n, p.Modulus)
+	limbs := decompose(&n, p.BitsPerLimb, p.NbLimbs)
+	res := Element{Limbs: make([]frontend.Variable, p.NbLimbs)}
+	for i := range limbs {
+		res.Limbs[i] = limbs[i]
+	}
+	return res
+}
+
+// decompose returns the nbLimbs limbs of nbBits bits of n, in little endian order.
+func decompose(n *big.Int, nbBits, nbLimbs int) []*big.Int {
+	res := make([]*big.Int, nbLimbs)
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(nbBits)), big.NewInt(1))
+	tmp := new(big.Int).Set(n)
+	for i := 0; i < nbLimbs; i++ {
+		res[i] = new(big.Int).And(tmp, mask)
+		tmp.Rsh(tmp, uint(nbBits))
+	}
+	return res
+}
+
+// recompose returns ∑ limbs[i]·2^{nbBits·i}. The limbs may be larger than
+// nbBits bits.
+func recompose(limbs []*big.Int, nbBits int) *big.Int {
+	res := new(big.Int)
+	for i := len(limbs) - 1; i >= 0; i-- {
+		res.Lsh(res, uint(nbBits))
+		res.Add(res, limbs[i])
+	}
+	return res
+}
@@ -0,0 +1,211 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fixedpoint implements signed fixed-point arithmetic in a circuit, for
+// instance to prove the inference of a quantized neural network.
+//
+// A number x of the format Params{IntegerBits: i, FractionBits: f} is
+// represented by the integer v = x·2^f, stored in a single variable (negative
+// integers being stored as their opposite modulo the native field), with
+// -2^(i+f) ⩽ v < 2^(i+f). Every operation asserts that its result is in this
+// range, so that an overflow makes the circuit unsatisfiable instead of
+// wrapping around: the circuit computes exactly as the native reference
+// functions (Params.NativeAdd, ...), which return an error in that case.
+//
+// The product of two numbers is rescaled by 2^-f, rounding toward -∞: the
+// quotient and the remainder of the division by 2^f are computed by a hint and
+// range checked.
+//
+// The numbers allocated in the witness are range checked each time they are
+// used by an operation; Arithmetic.Check returns a checked copy of a number
+// which is used several times.
+package fixedpoint
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+)
+
+func init() {
+	hint.Register(rescaleHint)
+}
+
+// Num is a fixed-point number in a circuit.
+type Num struct {
+	Val frontend.Variable
+
+	// internal is true if Val is the result of an operation, hence in range
+	internal bool
+}
+
+// Arithmetic performs the fixed-point arithmetic of a format in the circuit of
+// api.
+type Arithmetic struct {
+	api    frontend.API
+	params Params
+}
+
+// NewArithmetic returns an Arithmetic for the format described by params in the
+// circuit of api.
+func NewArithmetic(api frontend.API, params Params) (*Arithmetic, error) {
+	if err := params.check(); err != nil {
+		return nil, err
+	}
+
+	// the products of numbers must not wrap around the native modulus, see Mul
+	if nativeBits := api.Compiler().Curve().Info().Fr.Bits; 2*params.nbBits()+3 > nativeBits {
+		return nil, fmt.Errorf("%d bits fixed-point numbers are too large for the native field", params.nbBits()+1)
+	}
+	return &Arithmetic{api: api, params: params}, nil
+}
+
+// Check range checks x if needed and returns a copy of x which is not checked
+// again by the operations.
+func (f *Arithmetic) Check(x Num) Num {
+	f.check(x)
+	return Num{Val: x.Val, internal: true}
+}
+
+// Add returns x + y
+func (f *Arithmetic) Add(x, y Num) Num {
+	f.check(x, y)
+	return f.result(f.api.Add(x.Val, y.Val))
+}
+
+// Sub returns x - y
+func (f *Arithmetic) Sub(x, y Num) Num {
+	f.check(x, y)
+	return f.result(f.api.Sub(x.Val, y.Val))
+}
+
+// Neg returns -x
+func (f *Arithmetic) Neg(x Num) Num {
+	f.check(x)
+	return f.result(f.api.Neg(x.Val))
+}
+
+// Mul returns x·y, rounded toward -∞ to a multiple of 2^-FractionBits.
+//
+// The hint returns q and r such that x·y = q·2^FractionBits + r, r being
+// checked to be in [0, 2^FractionBits) and q to be in range. Both sides of the
+// equality are smaller than 2^(2(IntegerBits+FractionBits)+1) in absolute
+// value, hence the equality holds over the integers, and q is the rounded
+// product.
+func (f *Arithmetic) Mul(x, y Num) Num {
+	f.check(x, y)
+	api := f.api
+	z := api.Mul(x.Val, y.Val)
+	if f.params.FractionBits == 0 {
+		return f.result(z)
+	}
+
+	res, err := api.Compiler().NewHint(rescaleHint, 2, z, f.params.FractionBits)
+	if err != nil {
+		panic(err)
+	}
+	q, r := res[0], res[1]
+	api.ToBinary(r, f.params.FractionBits)
+	scale := new(big.Int).Lsh(big.NewInt(1), uint(f.params.FractionBits))
+	api.AssertIsEqual(z, api.Add(api.Mul(q, scale), r))
+	return f.result(q)
+}
+
+// ReLU returns max(x, 0)
+func (f *Arithmetic) ReLU(x Num) Num {
+	// the bit IntegerBits+FractionBits of the shifted value is the sign of x
+	isPositive := f.rangeCheck(x.Val)[f.params.nbBits()]
+	return Num{Val: f.api.Mul(isPositive, x.Val), internal: true}
+}
+
+// IsLess returns 1 if x < y, 0 otherwise
+func (f *Arithmetic) IsLess(x, y Num) frontend.Variable {
+	f.check(x, y)
+
+	// y - x - 1 + 2^(n+1) is in [0, 2^(n+2)) and its bit n+1 is set iff x < y,
+	// n being IntegerBits+FractionBits
+	n := f.params.nbBits()
+	shift := new(big.Int).Lsh(big.NewInt(1), uint(n+1))
+	d := f.api.Sub(f.api.Add(y.Val, shift), x.Val, 1)
+	return f.api.ToBinary(d, n+2)[n+1]
+}
+
+// AssertIsLessOrEqual fails if x > y
+func (f *Arithmetic) AssertIsLessOrEqual(x, y Num) {
+	f.check(x, y)
+
+	// y - x is in (-2^(n+1), 2^(n+1)), and fits in n+1 bits iff x ⩽ y
+	f.api.ToBinary(f.api.Sub(y.Val, x.Val), f.params.nbBits()+1)
+}
+
+// AssertIsEqual fails if x ≠ y
+func (f *Arithmetic) AssertIsEqual(x, y Num) {
+	f.api.AssertIsEqual(x.Val, y.Val)
+}
+
+// Select returns x if b is true, y otherwise. b must be boolean.
+func (f *Arithmetic) Select(b frontend.Variable, x, y Num) Num {
+	f.check(x, y)
+	return Num{Val: f.api.Select(b, x.Val, y.Val), internal: true}
+}
+
+// check range checks the numbers which are not results of operations
+func (f *Arithmetic) check(nums ...Num) {
+	for _, x := range nums {
+		if !x.internal {
+			f.rangeCheck(x.Val)
+		}
+	}
+}
+
+// result range checks v and returns it as a number
+func (f *Arithmetic) result(v frontend.Variable) Num {
+	f.rangeCheck(v)
+	return Num{Val: v, internal: true}
+}
+
+// rangeCheck asserts that -2^n ⩽ v < 2^n, n being IntegerBits+FractionBits, and
+// returns the n+1 bits of v + 2^n
+func (f *Arithmetic) rangeCheck(v frontend.Variable) []frontend.Variable {
+	n := f.params.nbBits()
+	return f.api.ToBinary(f.api.Add(v, f.params.bound()), n+1)
+}
+
+// rescaleHint returns the quotient and the remainder of the division of
+// inputs[0], as a signed integer, by 2^inputs[1], the quotient being rounded
+// toward -∞
+func rescaleHint(curve ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	if len(inputs) != 2 || len(results) != 2 {
+		return errors.New("expected 2 inputs and 2 results")
+	}
+	modulus := curve.Info().Fr.Modulus()
+
+	// inputs[0] represents a negative integer if it is larger than p/2
+	z := new(big.Int).Set(inputs[0])
+	if z.Cmp(new(big.Int).Rsh(modulus, 1)) > 0 {
+		z.Sub(z, modulus)
+	}
+	s := uint(inputs[1].Uint64())
+
+	q := new(big.Int).Rsh(z, s)
+	results[1].Sub(z, new(big.Int).Lsh(q, s))
+	results[0].Mod(q, modulus)
+	return nil
+}
@@ -0,0 +1,143 @@
+package fixedpoint
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+var q16 = Params{IntegerBits: 15, FractionBits: 16}
+
+type fixedPointCircuit struct {
+	X, Y                 Num
+	Sum, Diff, Prod, Neg Num
+	ReLU                 Num
+	IsLess               frontend.Variable
+}
+
+func (c *fixedPointCircuit) Define(api frontend.API) error {
+	f, err := NewArithmetic(api, q16)
+	if err != nil {
+		return err
+	}
+	x, y := f.Check(c.X), f.Check(c.Y)
+
+	f.AssertIsEqual(f.Add(x, y), c.Sum)
+	f.AssertIsEqual(f.Sub(x, y), c.Diff)
+	f.AssertIsEqual(f.Mul(x, y), c.Prod)
+	f.AssertIsEqual(f.Neg(x), c.Neg)
+	relu := f.ReLU(x)
+	f.AssertIsEqual(relu, c.ReLU)
+	api.AssertIsEqual(f.IsLess(x, y), c.IsLess)
+	f.AssertIsLessOrEqual(x, relu)
+	f.AssertIsEqual(f.Select(f.IsLess(x, y), x, y), f.Sub(x, f.ReLU(f.Sub(x, y))))
+	return nil
+}
+
+// fixedPointWitness returns the assignment of the circuit for x and y, computed
+// by the native functions; the results which overflow are set to 0
+func fixedPointWitness(t *testing.T, x, y float64) *fixedPointCircuit {
+	vx, err := q16.FromFloat(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vy, err := q16.FromFloat(y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	num := func(v *big.Int, err error) Num {
+		if err != nil {
+			return Num{Val: 0}
+		}
+		return Num{Val: v}
+	}
+
+	w := fixedPointCircuit{
+		X:    Num{Val: vx},
+		Y:    Num{Val: vy},
+		Sum:  num(q16.NativeAdd(vx, vy)),
+		Diff: num(q16.NativeSub(vx, vy)),
+		Prod: num(q16.NativeMul(vx, vy)),
+		Neg:  num(q16.NativeNeg(vx)),
+		ReLU: num(q16.NativeReLU(vx)),
+	}
+	if vx.Cmp(vy) < 0 {
+		w.IsLess = 1
+	} else {
+		w.IsLess = 0
+	}
+	return &w
+}
+
+func TestFixedPoint(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, v := range [][2]float64{
+		{1.5, -2.25},
+		{-0.3, 0.7},
+		{-0.3, -0.7},
+		{181.01, -181.02},
+		{0, 32767.99},
+		{-32767, 0.5},
+	} {
+		assert.SolvingSucceeded(&fixedPointCircuit{}, fixedPointWitness(t, v[0], v[1]), test.WithCurves(ecc.BN254))
+	}
+
+	// overflows of the sum, the difference, the product and the opposite
+	for _, v := range [][2]float64{
+		{20000, 20000},
+		{-20000, 20000},
+		{200, -200},
+		{-32768, 0},
+	} {
+		assert.SolvingFailed(&fixedPointCircuit{}, fixedPointWitness(t, v[0], v[1]), test.WithCurves(ecc.BN254))
+	}
+
+	// wrong rounding of the product
+	w := fixedPointWitness(t, -0.3, 0.7)
+	w.Prod.Val = new(big.Int).Add(w.Prod.Val.(*big.Int), big.NewInt(1))
+	assert.SolvingFailed(&fixedPointCircuit{}, w, test.WithCurves(ecc.BN254))
+
+	// input out of range
+	w = fixedPointWitness(t, 1, 2)
+	w.X.Val = q16.bound()
+	assert.SolvingFailed(&fixedPointCircuit{}, w, test.WithCurves(ecc.BN254))
+}
+
+func TestNative(t *testing.T) {
+	for _, f := range []float64{0, 1, -1, 0.5, -0.3, 1234.5678, -32768} {
+		v, err := q16.FromFloat(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(q16.ToFloat(v)-f) > math.Ldexp(1, -17) {
+			t.Fatalf("%g is represented as %g", f, q16.ToFloat(v))
+		}
+	}
+	for _, f := range []float64{32768, -32768.5, 1e308, -math.MaxFloat64, math.NaN(), math.Inf(1)} {
+		if _, err := q16.FromFloat(f); err == nil {
+			t.Fatalf("expected an error for %g", f)
+		}
+	}
+
+	// the scaling of an in range value overflows a float64
+	large := Params{IntegerBits: 1000, FractionBits: 100}
+	if _, err := large.FromFloat(math.Ldexp(1, 999)); err == nil {
+		t.Fatal("expected an error for 2^999 with 100 fraction bits")
+	}
+
+	// -0.5 · 2^-16 is rounded to -2^-16
+	x, _ := q16.FromFloat(-0.5)
+	y, _ := q16.FromFloat(math.Ldexp(1, -16))
+	z, err := q16.NativeMul(x, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if z.Cmp(big.NewInt(-1)) != 0 {
+		t.Fatalf("expected -1, got %s", z)
+	}
+}
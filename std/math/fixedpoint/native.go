@@ -0,0 +1,66 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fixedpoint
+
+import (
+	"math/big"
+)
+
+// The native functions below compute on the integers representing the numbers
+// (see Params.FromFloat) exactly as the circuit does, and return an error when
+// the circuit would not be satisfiable because of an overflow.
+
+// NativeAdd returns x + y
+func (p Params) NativeAdd(x, y *big.Int) (*big.Int, error) {
+	return p.result(new(big.Int).Add(x, y))
+}
+
+// NativeSub returns x - y
+func (p Params) NativeSub(x, y *big.Int) (*big.Int, error) {
+	return p.result(new(big.Int).Sub(x, y))
+}
+
+// NativeNeg returns -x
+func (p Params) NativeNeg(x *big.Int) (*big.Int, error) {
+	return p.result(new(big.Int).Neg(x))
+}
+
+// NativeMul returns x·y, rounded toward -∞ to a multiple of 2^-FractionBits
+func (p Params) NativeMul(x, y *big.Int) (*big.Int, error) {
+	z := new(big.Int).Mul(x, y)
+	// Rsh is an arithmetic shift, which rounds toward -∞
+	return p.result(z.Rsh(z, uint(p.FractionBits)))
+}
+
+// NativeReLU returns max(x, 0)
+func (p Params) NativeReLU(x *big.Int) (*big.Int, error) {
+	if x.Sign() < 0 {
+		return p.result(new(big.Int))
+	}
+	return p.result(new(big.Int).Set(x))
+}
+
+// result returns v if the format is valid and v is in its range
+func (p Params) result(v *big.Int) (*big.Int, error) {
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	if err := p.inRange(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fixedpoint
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Params describes a signed fixed-point format: a number is represented by the
+// integer v = x·2^FractionBits, with -2^(IntegerBits+FractionBits) ⩽ v <
+// 2^(IntegerBits+FractionBits). The format has IntegerBits+FractionBits bits
+// plus a sign bit.
+type Params struct {
+	IntegerBits  int
+	FractionBits int
+}
+
+// nbBits returns the number of bits of the absolute values of the format,
+// IntegerBits+FractionBits
+func (p Params) nbBits() int {
+	return p.IntegerBits + p.FractionBits
+}
+
+// check returns an error if p is not a valid format
+func (p Params) check() error {
+	if p.IntegerBits < 0 || p.FractionBits < 0 || p.nbBits() == 0 {
+		return fmt.Errorf("invalid fixed-point format with %d integer bits and %d fraction bits", p.IntegerBits, p.FractionBits)
+	}
+	return nil
+}
+
+// bound returns 2^(IntegerBits+FractionBits), the smallest positive integer
+// out of the range of the format
+func (p Params) bound() *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(p.nbBits()))
+}
+
+// inRange returns an error if the integer v is out of the range of the format
+func (p Params) inRange(v *big.Int) error {
+	bound := p.bound()
+	if v.Cmp(bound) >= 0 || v.Cmp(new(big.Int).Neg(bound)) < 0 {
+		return fmt.Errorf("overflow: %g doesn't fit in %d integer bits", p.ToFloat(v), p.IntegerBits)
+	}
+	return nil
+}
+
+// FromFloat returns the integer representing f, that is f·2^FractionBits
+// rounded to the nearest integer (half away from zero).
+func (p Params) FromFloat(f float64) (*big.Int, error) {
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, errors.New("can't represent NaN or infinite values")
+	}
+	// checked before the scaling, which overflows to ±Inf for large f
+	if math.Abs(f) > math.Ldexp(1, p.IntegerBits) {
+		return nil, fmt.Errorf("overflow: %g doesn't fit in %d integer bits", f, p.IntegerBits)
+	}
+	scaled := math.Round(math.Ldexp(f, p.FractionBits))
+	if math.IsInf(scaled, 0) {
+		return nil, fmt.Errorf("overflow: %g·2^%d doesn't fit in a float64", f, p.FractionBits)
+	}
+	v, _ := big.NewFloat(scaled).Int(nil)
+	if err := p.inRange(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ToFloat returns the number represented by the integer v, rounded to the
+// nearest float64.
+func (p Params) ToFloat(v *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(v).Float64()
+	return math.Ldexp(f, -p.FractionBits)
+}
+
+// ValueOf returns the number representing f (see FromFloat), to be used in a
+// circuit assignment.
+func (p Params) ValueOf(f float64) (Num, error) {
+	v, err := p.FromFloat(f)
+	if err != nil {
+		return Num{}, err
+	}
+	return Num{Val: v}, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uints
+
+import "github.com/consensys/gnark/frontend"
+
+// U32 is an unsigned integer of 32 bits (to be used in gnark circuit)
+type U32 struct {
+	Val frontend.Variable
+
+	dec decomposition
+}
+
+// NewU32 returns the U32 of value v, to be assigned to a witness or used as a
+// constant in a circuit
+func NewU32(v uint32) U32 {
+	return U32{Val: v}
+}
+
+// word returns the core of x
+func (x *U32) word() word {
+	return word{n: 32, val: &x.Val, dec: &x.dec}
+}
+
+// ToBinary returns the 32 little endian bits of x, and checks that x has 32
+// bits. The decomposition is kept in x for the following operations.
+func (x *U32) ToBinary(api frontend.API) []frontend.Variable {
+	return x.word().toBinary(api)
+}
+
+// And sets z to x ∧ y and returns z
+func (z *U32) And(api frontend.API, x, y U32) *U32 {
+	z.word().and(api, x.word(), y.word())
+	return z
+}
+
+// Or sets z to x ∨ y and returns z
+func (z *U32) Or(api frontend.API, x, y U32) *U32 {
+	z.word().or(api, x.word(), y.word())
+	return z
+}
+
+// Xor sets z to x ⊕ y and returns z
+func (z *U32) Xor(api frontend.API, x, y U32) *U32 {
+	z.word().xor(api, x.word(), y.word())
+	return z
+}
+
+// Not sets z to ¬x and returns z
+func (z *U32) Not(api frontend.API, x U32) *U32 {
+	z.word().not(api, x.word())
+	return z
+}
+
+// Lsh sets z to x << s and returns z
+func (z *U32) Lsh(api frontend.API, x U32, s uint) *U32 {
+	z.word().lsh(api, x.word(), s)
+	return z
+}
+
+// Rsh sets z to x >> s and returns z
+func (z *U32) Rsh(api frontend.API, x U32, s uint) *U32 {
+	z.word().rsh(api, x.word(), s)
+	return z
+}
+
+// RotateLeft sets z to x rotated left by s bits and returns z. To rotate x
+// right by s bits, call RotateLeft(api, x, -s) (as bits.RotateLeft32).
+func (z *U32) RotateLeft(api frontend.API, x U32, s int) *U32 {
+	z.word().rotateLeft(api, x.word(), s)
+	return z
+}
+
+// Add sets z to the sum of the operands modulo 2^32 and returns z
+func (z *U32) Add(api frontend.API, operands ...U32) *U32 {
+	words := make([]word, len(operands))
+	for i := range operands {
+		words[i] = operands[i].word()
+	}
+	z.word().add(api, words)
+	return z
+}
+
+// Mul sets z to x·y modulo 2^32 and returns z
+func (z *U32) Mul(api frontend.API, x, y U32) *U32 {
+	z.word().mul(api, x.word(), y.word())
+	return z
+}
+
+// IsLess returns 1 if x < y, 0 otherwise
+func (x *U32) IsLess(api frontend.API, y U32) frontend.Variable {
+	return x.word().isLess(api, y.word())
+}
+
+// AssertIsLessOrEqual fails if x > y
+func (x *U32) AssertIsLessOrEqual(api frontend.API, y U32) {
+	x.word().assertIsLessOrEqual(api, y.word())
+}
+
+// AssertIsEqual fails if x ≠ y
+func (x *U32) AssertIsEqual(api frontend.API, y U32) {
+	api.AssertIsEqual(x.Val, y.Val)
+}
+
+// ToBytes returns the 4 little endian bytes of x (as binary.LittleEndian)
+func (x *U32) ToBytes(api frontend.API) []U8 {
+	return x.word().toBytes(api)
+}
+
+// FromBytes sets z to the value of the 4 little endian bytes b and returns z
+func (z *U32) FromBytes(api frontend.API, b []U8) *U32 {
+	z.word().fromBytes(api, b)
+	return z
+}
@@ -0,0 +1,268 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package uints provides ZKP-circuit types for the unsigned integers of 8, 32
+// and 64 bits (U8, U32, U64), with the bitwise operations, the arithmetic
+// modulo 2ⁿ and the comparisons of the Go unsigned integers.
+//
+// An integer is assigned from a native Go integer (NewU32, ...), its value
+// being stored in the Val field. The operations work on the little endian
+// bits of the values, as math/big with z.Op(api, x, y): an integer given as
+// input of the circuit is decomposed (and range checked) by api.ToBinary when
+// it is used, while the results of the operations keep their decomposition, so
+// that a chain of operations decomposes each intermediate result once. Calling
+// ToBinary on an input used several times keeps its decomposition.
+//
+// As for the Go integers, the shifts and rotations are by constant amounts
+// and the arithmetic wraps around.
+package uints
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// decomposition holds the little endian bits of the value of an integer, if
+// already decomposed with api (a circuit is compiled once per backend)
+type decomposition struct {
+	bits []frontend.Variable
+	api  frontend.API
+}
+
+// word is the n bits core of U8, U32 and U64: the methods of the types operate
+// on the words of their value and decomposition
+type word struct {
+	n   int
+	val *frontend.Variable
+	dec *decomposition
+}
+
+// toBinary returns the n little endian bits of x, decomposed once per api
+func (x word) toBinary(api frontend.API) []frontend.Variable {
+	if x.dec.bits == nil || x.dec.api != api {
+		x.dec.bits = api.ToBinary(*x.val, x.n)
+		x.dec.api = api
+	}
+	return x.dec.bits
+}
+
+// setBits sets z to the value of the little endian bits b
+func (z word) setBits(api frontend.API, b []frontend.Variable) {
+	z.dec.bits = b
+	z.dec.api = api
+	*z.val = fromBits(api, b)
+}
+
+func (z word) and(api frontend.API, x, y word) {
+	z.setBits(api, and(api, x.toBinary(api), y.toBinary(api)))
+}
+
+func (z word) or(api frontend.API, x, y word) {
+	z.setBits(api, or(api, x.toBinary(api), y.toBinary(api)))
+}
+
+func (z word) xor(api frontend.API, x, y word) {
+	z.setBits(api, xor(api, x.toBinary(api), y.toBinary(api)))
+}
+
+func (z word) not(api frontend.API, x word) {
+	z.setBits(api, not(api, x.toBinary(api)))
+}
+
+func (z word) lsh(api frontend.API, x word, s uint) {
+	z.setBits(api, lsh(x.toBinary(api), int(s)))
+}
+
+func (z word) rsh(api frontend.API, x word, s uint) {
+	z.setBits(api, rsh(x.toBinary(api), int(s)))
+}
+
+func (z word) rotateLeft(api frontend.API, x word, s int) {
+	z.setBits(api, rotateLeft(x.toBinary(api), s))
+}
+
+func (z word) add(api frontend.API, operands []word) {
+	values := make([]frontend.Variable, len(operands))
+	for i := range operands {
+		operands[i].toBinary(api)
+		values[i] = *operands[i].val
+	}
+	z.setBits(api, add(api, z.n, values...))
+}
+
+func (z word) mul(api frontend.API, x, y word) {
+	x.toBinary(api)
+	y.toBinary(api)
+	z.setBits(api, mul(api, z.n, *x.val, *y.val))
+}
+
+func (x word) isLess(api frontend.API, y word) frontend.Variable {
+	x.toBinary(api)
+	y.toBinary(api)
+	return isLess(api, x.n, *x.val, *y.val)
+}
+
+func (x word) assertIsLessOrEqual(api frontend.API, y word) {
+	x.toBinary(api)
+	y.toBinary(api)
+	assertIsLessOrEqual(api, x.n, *x.val, *y.val)
+}
+
+// toBytes returns the n/8 little endian bytes of x
+func (x word) toBytes(api frontend.API) []U8 {
+	return bytesOf(api, x.toBinary(api))
+}
+
+// fromBytes sets z to the value of the n/8 little endian bytes b
+func (z word) fromBytes(api frontend.API, b []U8) {
+	if len(b) != z.n/8 {
+		panic("invalid number of bytes")
+	}
+	z.setBits(api, bitsOfBytes(api, b))
+}
+
+// fromBits returns the value of the little endian bits b, which are already
+// constrained
+func fromBits(api frontend.API, b []frontend.Variable) frontend.Variable {
+	return bits.FromBinary(api, b, bits.WithUnconstrainedInputs())
+}
+
+// and returns x ∧ y = xy, bitwise
+func and(api frontend.API, x, y []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(x))
+	for i := range res {
+		res[i] = api.Mul(x[i], y[i])
+	}
+	return res
+}
+
+// or returns x ∨ y = x + y - xy, bitwise
+func or(api frontend.API, x, y []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(x))
+	for i := range res {
+		res[i] = api.Sub(api.Add(x[i], y[i]), api.Mul(x[i], y[i]))
+	}
+	return res
+}
+
+// xor returns x ⊕ y = x + y - 2xy, bitwise
+func xor(api frontend.API, x, y []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(x))
+	for i := range res {
+		res[i] = api.Sub(api.Add(x[i], y[i]), api.Mul(2, x[i], y[i]))
+	}
+	return res
+}
+
+// not returns ¬x = 1 - x, bitwise
+func not(api frontend.API, x []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(x))
+	for i := range res {
+		res[i] = api.Sub(1, x[i])
+	}
+	return res
+}
+
+// lsh returns x << s, s ⩾ 0
+func lsh(x []frontend.Variable, s int) []frontend.Variable {
+	res := make([]frontend.Variable, len(x))
+	for i := range res {
+		if i >= s {
+			res[i] = x[i-s]
+		} else {
+			res[i] = 0
+		}
+	}
+	return res
+}
+
+// rsh returns x >> s, s ⩾ 0
+func rsh(x []frontend.Variable, s int) []frontend.Variable {
+	res := make([]frontend.Variable, len(x))
+	for i := range res {
+		if i+s < len(x) {
+			res[i] = x[i+s]
+		} else {
+			res[i] = 0
+		}
+	}
+	return res
+}
+
+// rotateLeft returns x rotated left by s bits, right by -s bits if s < 0
+func rotateLeft(x []frontend.Variable, s int) []frontend.Variable {
+	n := len(x)
+	s = ((s % n) + n) % n
+	res := make([]frontend.Variable, n)
+	for i := range res {
+		res[(i+s)%n] = x[i]
+	}
+	return res
+}
+
+// add returns the n bits of the sum of the values modulo 2ⁿ
+func add(api frontend.API, n int, values ...frontend.Variable) []frontend.Variable {
+	var sum frontend.Variable = 0
+	for _, v := range values {
+		sum = api.Add(sum, v)
+	}
+
+	// the sum has at most n + ⌈log₂(len(values))⌉ bits, we keep the n lsb
+	nbBits := n
+	for k := 1; k < len(values); k *= 2 {
+		nbBits++
+	}
+	return api.ToBinary(sum, nbBits)[:n]
+}
+
+// mul returns the n bits of the product of the n bits values x and y modulo 2ⁿ
+func mul(api frontend.API, n int, x, y frontend.Variable) []frontend.Variable {
+	return api.ToBinary(api.Mul(x, y), 2*n)[:n]
+}
+
+// isLess returns 1 if x < y, 0 otherwise, for n bits values x and y: it is the
+// bit n of 2ⁿ + y - x - 1, which is in [0, 2ⁿ⁺¹)
+func isLess(api frontend.API, n int, x, y frontend.Variable) frontend.Variable {
+	twoN := new(big.Int).Lsh(big.NewInt(1), uint(n))
+	d := api.Sub(api.Add(y, twoN), x, 1)
+	return api.ToBinary(d, n+1)[n]
+}
+
+// assertIsLessOrEqual checks that x ⩽ y for n bits values x and y, that is
+// that y - x has n bits
+func assertIsLessOrEqual(api frontend.API, n int, x, y frontend.Variable) {
+	api.ToBinary(api.Sub(y, x), n)
+}
+
+// bytesOf returns the little endian bytes of the bits b
+func bytesOf(api frontend.API, b []frontend.Variable) []U8 {
+	res := make([]U8, len(b)/8)
+	for i := range res {
+		res[i].word().setBits(api, b[8*i:8*i+8])
+	}
+	return res
+}
+
+// bitsOfBytes returns the little endian bits of the little endian bytes b
+func bitsOfBytes(api frontend.API, b []U8) []frontend.Variable {
+	res := make([]frontend.Variable, 0, 8*len(b))
+	for i := range b {
+		res = append(res, b[i].ToBinary(api)...)
+	}
+	return res
+}
@@ -0,0 +1,179 @@
+package uints
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type u32Circuit struct {
+	X, Y                 U32
+	And, Or, Xor, Not    U32
+	Lsh, Rsh, Rotl, Rotr U32
+	Sum, Prod            U32
+	IsLess               frontend.Variable
+	Bytes                [4]U8
+	FromBytes            U32
+	LessOrEqual          U32
+}
+
+func (c *u32Circuit) Define(api frontend.API) error {
+	c.X.ToBinary(api)
+	c.Y.ToBinary(api)
+
+	var z U32
+	z.And(api, c.X, c.Y).AssertIsEqual(api, c.And)
+	z.Or(api, c.X, c.Y).AssertIsEqual(api, c.Or)
+	z.Xor(api, c.X, c.Y).AssertIsEqual(api, c.Xor)
+	z.Not(api, c.X).AssertIsEqual(api, c.Not)
+	z.Lsh(api, c.X, 7).AssertIsEqual(api, c.Lsh)
+	z.Rsh(api, c.X, 7).AssertIsEqual(api, c.Rsh)
+	z.RotateLeft(api, c.X, 7).AssertIsEqual(api, c.Rotl)
+	z.RotateLeft(api, c.X, -7).AssertIsEqual(api, c.Rotr)
+	z.Add(api, c.X, c.Y, c.X).AssertIsEqual(api, c.Sum)
+	z.Mul(api, c.X, c.Y).AssertIsEqual(api, c.Prod)
+	api.AssertIsEqual(c.X.IsLess(api, c.Y), c.IsLess)
+
+	b := c.X.ToBytes(api)
+	for i := range b {
+		b[i].AssertIsEqual(api, c.Bytes[i])
+	}
+	z.FromBytes(api, c.Bytes[:]).AssertIsEqual(api, c.FromBytes)
+
+	c.LessOrEqual.AssertIsLessOrEqual(api, c.X)
+	c.X.AssertIsLessOrEqual(api, c.X)
+	return nil
+}
+
+func u32Witness(x, y uint32) *u32Circuit {
+	w := u32Circuit{
+		X: NewU32(x), Y: NewU32(y),
+		And: NewU32(x & y), Or: NewU32(x | y), Xor: NewU32(x ^ y), Not: NewU32(^x),
+		Lsh: NewU32(x << 7), Rsh: NewU32(x >> 7),
+		Rotl: NewU32(bits.RotateLeft32(x, 7)), Rotr: NewU32(bits.RotateLeft32(x, -7)),
+		Sum: NewU32(x + y + x), Prod: NewU32(x * y),
+		FromBytes:   NewU32(x),
+		LessOrEqual: NewU32(x / 2),
+	}
+	if x < y {
+		w.IsLess = 1
+	} else {
+		w.IsLess = 0
+	}
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], x)
+	for i := range buf {
+		w.Bytes[i] = NewU8(buf[i])
+	}
+	return &w
+}
+
+func TestU32(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, v := range [][2]uint32{
+		{rand.Uint32(), rand.Uint32()}, //#nosec G404 -- test values
+		{0xffffffff, 0xffffffff},
+		{0, 0xffffffff},
+		{0x80000000, 0x7fffffff},
+	} {
+		assert.SolvingSucceeded(&u32Circuit{}, u32Witness(v[0], v[1]), test.WithCurves(ecc.BN254))
+	}
+
+	// wrong results
+	w := u32Witness(3, 5)
+	w.Sum = NewU32(3 + 5 + 3 + 1)
+	assert.SolvingFailed(&u32Circuit{}, w, test.WithCurves(ecc.BN254))
+
+	w = u32Witness(3, 5)
+	w.IsLess = 0
+	assert.SolvingFailed(&u32Circuit{}, w, test.WithCurves(ecc.BN254))
+
+	w = u32Witness(3, 5)
+	w.LessOrEqual = NewU32(4)
+	assert.SolvingFailed(&u32Circuit{}, w, test.WithCurves(ecc.BN254))
+
+	// input out of range
+	w = u32Witness(3, 5)
+	w.Y.Val = uint64(1) << 32
+	assert.SolvingFailed(&u32Circuit{}, w, test.WithCurves(ecc.BN254))
+}
+
+type u64Circuit struct {
+	X, Y      U64
+	Xor       U64
+	Rotr      U64
+	Sum, Prod U64
+	IsLess    frontend.Variable
+	Bytes     [8]U8
+}
+
+func (c *u64Circuit) Define(api frontend.API) error {
+	var z U64
+	z.Xor(api, c.X, c.Y).AssertIsEqual(api, c.Xor)
+	z.RotateLeft(api, c.X, -13).AssertIsEqual(api, c.Rotr)
+	z.Add(api, c.X, c.Y).AssertIsEqual(api, c.Sum)
+	z.Mul(api, c.X, c.Y).AssertIsEqual(api, c.Prod)
+	api.AssertIsEqual(c.X.IsLess(api, c.Y), c.IsLess)
+	b := c.X.ToBytes(api)
+	for i := range b {
+		b[i].AssertIsEqual(api, c.Bytes[i])
+	}
+	return nil
+}
+
+func TestU64(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	x, y := rand.Uint64(), uint64(0xffffffffffffffff) //#nosec G404 -- test values
+	w := u64Circuit{
+		X: NewU64(x), Y: NewU64(y),
+		Xor:  NewU64(x ^ y),
+		Rotr: NewU64(bits.RotateLeft64(x, -13)),
+		Sum:  NewU64(x + y), Prod: NewU64(x * y),
+		IsLess: 1,
+	}
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], x)
+	for i := range buf {
+		w.Bytes[i] = NewU8(buf[i])
+	}
+	assert.SolvingSucceeded(&u64Circuit{}, &w, test.WithCurves(ecc.BN254))
+
+	w.Prod = NewU64(x * (y - 1))
+	assert.SolvingFailed(&u64Circuit{}, &w, test.WithCurves(ecc.BN254))
+}
+
+type u8Circuit struct {
+	X, Y     U8
+	And, Sum U8
+	Rotl     U8
+}
+
+func (c *u8Circuit) Define(api frontend.API) error {
+	var z U8
+	z.And(api, c.X, c.Y).AssertIsEqual(api, c.And)
+	z.Add(api, c.X, c.Y).AssertIsEqual(api, c.Sum)
+	z.RotateLeft(api, c.X, 3).AssertIsEqual(api, c.Rotl)
+	return nil
+}
+
+func TestU8(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var x, y uint8 = 0xb7, 0xd2
+	w := u8Circuit{
+		X: NewU8(x), Y: NewU8(y),
+		And: NewU8(x & y), Sum: NewU8(x + y),
+		Rotl: NewU8(bits.RotateLeft8(x, 3)),
+	}
+	assert.SolvingSucceeded(&u8Circuit{}, &w, test.WithCurves(ecc.BN254))
+
+	w.X.Val = 0x1b7
+	assert.SolvingFailed(&u8Circuit{}, &w, test.WithCurves(ecc.BN254))
+}
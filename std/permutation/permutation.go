@@ -0,0 +1,101 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package permutation provides ZKP-circuit functions to prove that an array of
+// variables is a permutation of another one (i.e. that they are equal as
+// multisets), for instance to check sorted outputs, shuffles, or the
+// consistency of memory accesses.
+//
+// AssertIsPermutation checks the equality of the grand products ∏(aᵢ - γ) and
+// ∏(bᵢ - γ) at a random challenge γ, derived in the circuit from the two arrays
+// with a std/fiat-shamir transcript. It costs about 2n multiplications plus
+// the hash of the arrays, and is sound up to a probability n/r of error, r
+// being the modulus of the native field.
+//
+// AssertIsPermutationWaksman routes the first array through a Waksman network
+// (whose switches are set by a hint), and checks that the output is the second
+// one. It needs no hash and is perfectly sound, but costs about n·log₂(n)
+// switches.
+package permutation
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	fiatshamir "github.com/consensys/gnark/std/fiat-shamir"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// challengeID is the name of the challenge of the grand products
+const challengeID = "permutation"
+
+// AssertIsPermutation checks that b is a permutation of a, with a grand product
+// argument at a challenge computed with h. h must be a hash on field elements
+// (for example std/hash/mimc), and not a hash.BinaryHasher.
+func AssertIsPermutation(api frontend.API, h hash.Hash, a, b []frontend.Variable) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("arrays of different lengths %d and %d", len(a), len(b))
+	}
+	if len(a) == 0 {
+		return nil
+	}
+	if _, ok := h.(hash.BinaryHasher); ok {
+		return errors.New("the hash function must operate on field elements")
+	}
+
+	// γ = H(name ‖ a ‖ b)
+	transcript := fiatshamir.NewTranscript(api, h, challengeID)
+	if err := transcript.Bind(challengeID, a); err != nil {
+		return err
+	}
+	if err := transcript.Bind(challengeID, b); err != nil {
+		return err
+	}
+	gamma, err := transcript.ComputeChallenge(challengeID)
+	if err != nil {
+		return err
+	}
+
+	// ∏(aᵢ - γ) = ∏(bᵢ - γ)
+	var left, right frontend.Variable = 1, 1
+	for i := range a {
+		left = api.Mul(left, api.Sub(a[i], gamma))
+		right = api.Mul(right, api.Sub(b[i], gamma))
+	}
+	api.AssertIsEqual(left, right)
+	return nil
+}
+
+// AssertIsSorted checks that sorted is input sorted in increasing order, the
+// permutation being checked by AssertIsPermutation with h. The values must be
+// smaller than 2^nbBits: the circuit checks that the first element of sorted
+// and the differences of its consecutive elements fit in nbBits bits.
+func AssertIsSorted(api frontend.API, h hash.Hash, input, sorted []frontend.Variable, nbBits int) error {
+	if len(sorted) > 0 {
+		// the elements of sorted are sorted[0] + ∑ dᵢ < (n+1)·2^nbBits, which
+		// must not wrap around the native modulus
+		if nbBits+bits.Len(uint(len(sorted)+1)) >= api.Compiler().Curve().Info().Fr.Bits {
+			return fmt.Errorf("values of %d bits are too large for the native field", nbBits)
+		}
+		api.ToBinary(sorted[0], nbBits)
+	}
+	for i := 1; i < len(sorted); i++ {
+		api.ToBinary(api.Sub(sorted[i], sorted[i-1]), nbBits)
+	}
+	return AssertIsPermutation(api, h, input, sorted)
+}
@@ -0,0 +1,237 @@
+package permutation
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type permutationCircuit struct {
+	A, B []frontend.Variable
+}
+
+func (c *permutationCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return AssertIsPermutation(api, &h, c.A, c.B)
+}
+
+type sortedCircuit struct {
+	Input, Sorted []frontend.Variable
+}
+
+func (c *sortedCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return AssertIsSorted(api, &h, c.Input, c.Sorted, 16)
+}
+
+type waksmanCircuit struct {
+	A, B []frontend.Variable
+}
+
+func (c *waksmanCircuit) Define(api frontend.API) error {
+	return AssertIsPermutationWaksman(api, c.A, c.B)
+}
+
+func variables(v ...int) []frontend.Variable {
+	res := make([]frontend.Variable, len(v))
+	for i := range v {
+		res[i] = v[i]
+	}
+	return res
+}
+
+// shuffled returns a random permutation of v
+func shuffled(v []int) []int {
+	res := append([]int{}, v...)
+	rand.Shuffle(len(res), func(i, j int) { res[i], res[j] = res[j], res[i] })
+	return res
+}
+
+func TestPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	a := []int{5, 3, 3, 42, 0, 7}
+	circuit := permutationCircuit{A: make([]frontend.Variable, len(a)), B: make([]frontend.Variable, len(a))}
+	assert.SolvingSucceeded(&circuit, &permutationCircuit{A: variables(a...), B: variables(a...)}, test.WithCurves(ecc.BN254))
+	assert.SolvingSucceeded(&circuit, &permutationCircuit{A: variables(a...), B: variables(3, 42, 0, 7, 3, 5)}, test.WithCurves(ecc.BN254))
+
+	// same set, other multiplicities
+	assert.SolvingFailed(&circuit, &permutationCircuit{A: variables(a...), B: variables(5, 3, 42, 42, 0, 7)}, test.WithCurves(ecc.BN254))
+	assert.SolvingFailed(&circuit, &permutationCircuit{A: variables(a...), B: variables(5, 3, 3, 42, 0, 8)}, test.WithCurves(ecc.BN254))
+
+	assert.Fuzz(&circuit, 10, test.WithCurves(ecc.BN254))
+}
+
+func TestSorted(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	input := []int{5, 3, 65535, 3, 0, 7}
+	circuit := sortedCircuit{Input: make([]frontend.Variable, len(input)), Sorted: make([]frontend.Variable, len(input))}
+	assert.SolvingSucceeded(&circuit, &sortedCircuit{Input: variables(input...), Sorted: variables(0, 3, 3, 5, 7, 65535)}, test.WithCurves(ecc.BN254))
+
+	// not sorted
+	assert.SolvingFailed(&circuit, &sortedCircuit{Input: variables(input...), Sorted: variables(0, 3, 5, 3, 7, 65535)}, test.WithCurves(ecc.BN254))
+
+	// sorted, but not a permutation
+	assert.SolvingFailed(&circuit, &sortedCircuit{Input: variables(input...), Sorted: variables(0, 3, 4, 5, 7, 65535)}, test.WithCurves(ecc.BN254))
+
+	// difference out of range
+	input[2] = 65543
+	assert.SolvingFailed(&circuit, &sortedCircuit{Input: variables(input...), Sorted: variables(0, 3, 3, 5, 7, 65543)}, test.WithCurves(ecc.BN254))
+
+	// wrapping around the modulus
+	minusOne := new(big.Int).Sub(ecc.BN254.Info().Fr.Modulus(), big.NewInt(1))
+	w := sortedCircuit{Input: variables(5, 3, 0, 3, 0, 7), Sorted: variables(0, 0, 3, 3, 5, 7)}
+	w.Input[2], w.Sorted[0] = minusOne, minusOne
+	assert.SolvingFailed(&circuit, &w, test.WithCurves(ecc.BN254))
+
+	assert.Fuzz(&circuit, 10, test.WithCurves(ecc.BN254))
+}
+
+func TestWaksman(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, n := range []int{1, 2, 3, 5, 8, 11} {
+		a := make([]int, n)
+		for i := range a {
+			a[i] = 3*i + 1
+		}
+		a[n-1] = a[0] // duplicate
+
+		circuit := waksmanCircuit{A: make([]frontend.Variable, n), B: make([]frontend.Variable, n)}
+		for i := 0; i < 3; i++ {
+			assert.SolvingSucceeded(&circuit, &waksmanCircuit{A: variables(a...), B: variables(shuffled(a)...)}, test.WithCurves(ecc.BN254))
+		}
+
+		b := shuffled(a)
+		b[0]++
+		assert.SolvingFailed(&circuit, &waksmanCircuit{A: variables(a...), B: variables(b...)}, test.WithCurves(ecc.BN254))
+
+		assert.Fuzz(&circuit, 10, test.WithCurves(ecc.BN254))
+	}
+}
+
+// TestRouting checks the routing of all the permutations of up to 7 elements
+func TestRouting(t *testing.T) {
+	for n := 1; n <= 7; n++ {
+		perm := make([]int, n)
+		for i := range perm {
+			perm[i] = i
+		}
+		for {
+			checkRouting(t, perm)
+			if !nextPermutation(perm) {
+				break
+			}
+		}
+	}
+}
+
+// checkRouting checks that the switches computed by waksmanHint send the input
+// i to the output perm[i]
+func checkRouting(t *testing.T, perm []int) {
+	t.Helper()
+	n := len(perm)
+	inputs := make([]*big.Int, 2*n)
+	for i := range perm {
+		inputs[i] = big.NewInt(int64(i))
+		inputs[n+perm[i]] = big.NewInt(int64(i))
+	}
+	outputs := make([]*big.Int, nbSwitches(n))
+	for i := range outputs {
+		outputs[i] = new(big.Int)
+	}
+	if err := waksmanHint(ecc.BN254, inputs, outputs); err != nil {
+		t.Fatal(perm, err)
+	}
+
+	switches := make([]bool, len(outputs))
+	for i := range outputs {
+		switches[i] = outputs[i].Sign() != 0
+	}
+	x := make([]int, n)
+	for i := range x {
+		x[i] = i
+	}
+	out := evalNetwork(x, &switches)
+	for i := range perm {
+		if out[perm[i]] != i {
+			t.Fatal("wrong routing of", perm)
+		}
+	}
+}
+
+// evalNetwork mirrors route out of the circuit
+func evalNetwork(x []int, switches *[]bool) []int {
+	n := len(x)
+	next := func() bool {
+		s := (*switches)[0]
+		*switches = (*switches)[1:]
+		return s
+	}
+	swap := func(s bool, x, y int) (int, int) {
+		if s {
+			return y, x
+		}
+		return x, y
+	}
+	if n == 1 {
+		return x
+	}
+	if n == 2 {
+		o0, o1 := swap(next(), x[0], x[1])
+		return []int{o0, o1}
+	}
+	upper := make([]int, n/2)
+	lower := make([]int, n-n/2)
+	for i := 0; i < n/2; i++ {
+		upper[i], lower[i] = swap(next(), x[2*i], x[2*i+1])
+	}
+	if n%2 == 1 {
+		lower[n/2] = x[n-1]
+	}
+	upper = evalNetwork(upper, switches)
+	lower = evalNetwork(lower, switches)
+	out := make([]int, n)
+	for k := 0; k < (n+1)/2-1; k++ {
+		out[2*k], out[2*k+1] = swap(next(), upper[k], lower[k])
+	}
+	if n%2 == 0 {
+		out[n-2], out[n-1] = upper[n/2-1], lower[n/2-1]
+	} else {
+		out[n-1] = lower[n/2]
+	}
+	return out
+}
+
+// nextPermutation sets p to the next permutation in lexicographic order, and
+// returns false if p is the last one
+func nextPermutation(p []int) bool {
+	i := len(p) - 2
+	for i >= 0 && p[i] >= p[i+1] {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	j := len(p) - 1
+	for p[j] <= p[i] {
+		j--
+	}
+	p[i], p[j] = p[j], p[i]
+	for l, r := i+1, len(p)-1; l < r; l, r = l+1, r-1 {
+		p[l], p[r] = p[r], p[l]
+	}
+	return true
+}
@@ -0,0 +1,277 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permutation
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// The Waksman network of n inputs (Beauquier and Darrot, "On arbitrary size
+// Waksman networks and their vulnerability", 2002) is built recursively:
+//
+//   - an input layer of ⌊n/2⌋ switches, the switch i sending the inputs 2i and
+//     2i+1 to the inputs i of the upper and lower subnetworks (the last input
+//     going to the lower subnetwork if n is odd);
+//   - an upper subnetwork of ⌊n/2⌋ inputs, and a lower one of ⌈n/2⌉ inputs;
+//   - an output layer of ⌈n/2⌉-1 switches, the switch k sending the outputs k
+//     of the subnetworks to the outputs 2k and 2k+1 (the last outputs coming
+//     directly from the subnetworks).
+//
+// A switch exchanges its two inputs if its bit is set. The switches are
+// ordered as: input layer, upper subnetwork, lower subnetwork, output layer.
+
+func init() {
+	hint.Register(waksmanHint)
+}
+
+// AssertIsPermutationWaksman checks that b is a permutation of a, by routing a
+// through a Waksman network whose output must be b.
+func AssertIsPermutationWaksman(api frontend.API, a, b []frontend.Variable) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("arrays of different lengths %d and %d", len(a), len(b))
+	}
+	n := nbSwitches(len(a))
+	if n == 0 {
+		for i := range a {
+			api.AssertIsEqual(a[i], b[i])
+		}
+		return nil
+	}
+
+	switches, err := api.Compiler().NewHint(waksmanHint, n, append(append([]frontend.Variable{}, a...), b...)...)
+	if err != nil {
+		return err
+	}
+	for i := range switches {
+		api.AssertIsBoolean(switches[i])
+	}
+
+	out := route(api, a, &switches)
+	for i := range out {
+		api.AssertIsEqual(out[i], b[i])
+	}
+	return nil
+}
+
+// nbSwitches returns the number of switches of the network of n inputs
+func nbSwitches(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	if n == 2 {
+		return 1
+	}
+	return n/2 + nbSwitches(n/2) + nbSwitches(n-n/2) + (n+1)/2 - 1
+}
+
+// route returns the outputs of the network for the inputs x, the bits of the
+// switches being consumed from switches
+func route(api frontend.API, x []frontend.Variable, switches *[]frontend.Variable) []frontend.Variable {
+	n := len(x)
+	next := func() frontend.Variable {
+		s := (*switches)[0]
+		*switches = (*switches)[1:]
+		return s
+	}
+	if n == 1 {
+		return x
+	}
+	if n == 2 {
+		o0, o1 := swap(api, next(), x[0], x[1])
+		return []frontend.Variable{o0, o1}
+	}
+
+	upper := make([]frontend.Variable, n/2)
+	lower := make([]frontend.Variable, n-n/2)
+	for i := 0; i < n/2; i++ {
+		upper[i], lower[i] = swap(api, next(), x[2*i], x[2*i+1])
+	}
+	if n%2 == 1 {
+		lower[n/2] = x[n-1]
+	}
+	upper = route(api, upper, switches)
+	lower = route(api, lower, switches)
+
+	out := make([]frontend.Variable, n)
+	for k := 0; k < (n+1)/2-1; k++ {
+		out[2*k], out[2*k+1] = swap(api, next(), upper[k], lower[k])
+	}
+	if n%2 == 0 {
+		out[n-2], out[n-1] = upper[n/2-1], lower[n/2-1]
+	} else {
+		out[n-1] = lower[n/2]
+	}
+	return out
+}
+
+// swap returns (x, y) if s = 0, (y, x) if s = 1
+func swap(api frontend.API, s, x, y frontend.Variable) (frontend.Variable, frontend.Variable) {
+	first := api.Select(s, y, x)
+	return first, api.Sub(api.Add(x, y), first)
+}
+
+// waksmanHint returns the bits of the switches of the network routing the
+// inputs a to b, given as the 2n inputs a ‖ b. It fails if b is not a
+// permutation of a.
+func waksmanHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs)%2 != 0 || len(outputs) != nbSwitches(len(inputs)/2) {
+		return errors.New("invalid number of inputs or outputs")
+	}
+	n := len(inputs) / 2
+	a, b := inputs[:n], inputs[n:]
+
+	// perm[i] is the position in b of a[i]
+	positions := make(map[string][]int, n)
+	for j := range b {
+		positions[b[j].String()] = append(positions[b[j].String()], j)
+	}
+	perm := make([]int, n)
+	for i := range a {
+		p := positions[a[i].String()]
+		if len(p) == 0 {
+			return errors.New("not a permutation")
+		}
+		perm[i], positions[a[i].String()] = p[0], p[1:]
+	}
+
+	switches := make([]bool, 0, len(outputs))
+	if err := routing(perm, &switches); err != nil {
+		return err
+	}
+	for i := range outputs {
+		if switches[i] {
+			outputs[i].SetUint64(1)
+		} else {
+			outputs[i].SetUint64(0)
+		}
+	}
+	return nil
+}
+
+// routing appends to switches the bits of the switches of the network sending
+// the input i to the output perm[i], with the looping algorithm
+func routing(perm []int, switches *[]bool) error {
+	n := len(perm)
+	if n == 1 {
+		return nil
+	}
+	if n == 2 {
+		*switches = append(*switches, perm[0] == 1)
+		return nil
+	}
+	inv := make([]int, n)
+	for i, p := range perm {
+		inv[p] = i
+	}
+
+	// the inputs are colored by the subnetwork they go through (0 for the
+	// upper one, 1 for the lower one). The inputs of a switch go through
+	// different subnetworks, and so do the outputs of a switch (or the last
+	// two outputs if n is even, the last one coming from the lower one).
+	const (
+		none  = -1
+		upper = 0
+		lower = 1
+	)
+	color := make([]int, n)
+	for i := range color {
+		color[i] = none
+	}
+	neighbours := func(i int) []int {
+		var res []int
+		if j := i ^ 1; j < n {
+			res = append(res, j)
+		}
+		if o := perm[i] ^ 1; o < n {
+			res = append(res, inv[o])
+		}
+		return res
+	}
+	colorFrom := func(i, c int) error {
+		if color[i] != none {
+			if color[i] != c {
+				return errors.New("inconsistent routing")
+			}
+			return nil
+		}
+		color[i] = c
+		queue := []int{i}
+		for len(queue) > 0 {
+			i := queue[0]
+			queue = queue[1:]
+			for _, j := range neighbours(i) {
+				if color[j] == none {
+					color[j] = 1 - color[i]
+					queue = append(queue, j)
+				} else if color[j] == color[i] {
+					return errors.New("inconsistent routing")
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := colorFrom(inv[n-1], lower); err != nil {
+		return err
+	}
+	if n%2 == 1 {
+		if err := colorFrom(n-1, lower); err != nil {
+			return err
+		}
+	}
+	for i := range color {
+		if color[i] == none {
+			if err := colorFrom(i, upper); err != nil {
+				return err
+			}
+		}
+	}
+
+	// input layer
+	for i := 0; i < n/2; i++ {
+		*switches = append(*switches, color[2*i] == lower)
+	}
+
+	// subnetworks
+	upperPerm := make([]int, n/2)
+	lowerPerm := make([]int, n-n/2)
+	for i := range perm {
+		if color[i] == upper {
+			upperPerm[i/2] = perm[i] / 2
+		} else {
+			lowerPerm[i/2] = perm[i] / 2
+		}
+	}
+	if err := routing(upperPerm, switches); err != nil {
+		return err
+	}
+	if err := routing(lowerPerm, switches); err != nil {
+		return err
+	}
+
+	// output layer
+	for k := 0; k < (n+1)/2-1; k++ {
+		*switches = append(*switches, color[inv[2*k]] == lower)
+	}
+	return nil
+}
@@ -0,0 +1,390 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plonk_bls12377 provides a ZKP-circuit function to verify BLS12_377 PlonK proofs inside a BW6_761 circuit.
+//
+// The verifier follows gnark's native PlonK verifier: the challenges are derived
+// with a SHA-256 Fiat-Shamir transcript, and the scalar field arithmetic of
+// BLS12_377 is emulated (see std/math/emulated). The points of the proof and of
+// the verifying key must not be the point at infinity.
+package plonk_bls12377
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	plonk_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/plonk"
+	"github.com/consensys/gnark/std/algebra/sw_bls12377"
+	kzg_bls12377 "github.com/consensys/gnark/std/commitments/kzg/bls12377"
+	fiatshamir "github.com/consensys/gnark/std/fiat-shamir"
+	"github.com/consensys/gnark/std/hash/sha256"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// nbClaimedValues is the number of polynomials opened at ζ: the quotient, the
+// linearized polynomial, l, r, o, s1 and s2
+const nbClaimedValues = 7
+
+// BatchOpeningProof represents a KZG opening proof of several polynomials at a single point
+type BatchOpeningProof struct {
+	// H quotient polynomial Sum_i gamma**i*(f - f(z))/(x-z)
+	H sw_bls12377.G1Affine
+
+	// ClaimedValues purported values
+	ClaimedValues [nbClaimedValues]frontend.Variable
+}
+
+// Proof represents a PlonK proof
+type Proof struct {
+	// Commitments to the solution vectors
+	LRO [3]sw_bls12377.G1Affine
+
+	// Commitment to Z, the permutation polynomial
+	Z sw_bls12377.G1Affine
+
+	// Commitments to h1, h2, h3 such that h = h1 + Xh2 + X**2h3 is the quotient polynomial
+	H [3]sw_bls12377.G1Affine
+
+	// Batch opening proof of h1 + zeta*h2 + zeta**2h3, linearizedPolynomial, l, r, o, s1, s2
+	BatchedProof BatchOpeningProof
+
+	// Opening proof of Z at zeta*mu
+	ZShiftedOpening kzg_bls12377.OpeningProof
+}
+
+// VerifyingKey represents a PlonK verifying key
+type VerifyingKey struct {
+	// Size of the evaluation domain. It defines the structure of the verifier
+	// circuit and must be set before compiling it.
+	Size uint64 `gnark:"-"`
+
+	// KZG commitment scheme
+	KZG kzg_bls12377.VerifyingKey
+
+	// CosetShift is the shift of the cosets of the domain encoding the
+	// permutation, an element of 𝔽r
+	CosetShift frontend.Variable
+
+	// S commitments to S1, S2, S3
+	S [3]sw_bls12377.G1Affine
+
+	// Commitments to ql, qr, qm, qo, qk
+	Ql, Qr, Qm, Qo, Qk sw_bls12377.G1Affine
+}
+
+// verifier holds the gadgets used to verify a proof
+type verifier struct {
+	api frontend.API
+	fr  *emulated.Field
+}
+
+// Verify implements the verification function of PlonK, as in the native verifier
+// of gnark. publicInputs are the public inputs of the inner circuit.
+func Verify(api frontend.API, vk VerifyingKey, proof Proof, publicInputs []frontend.Variable) error {
+	if vk.Size == 0 {
+		return errors.New("VerifyingKey.Size must be set before compiling the circuit")
+	}
+	f, err := emulated.NewField(api, emulated.BLS12377Fr)
+	if err != nil {
+		return err
+	}
+	v := verifier{api: api, fr: f}
+	domain := fft.NewDomain(vk.Size)
+
+	// derive the challenges γ, β, α and ζ. γ is bound to the verifying key and the
+	// public inputs, α to Z and ζ to h1, h2, h3.
+	fs := fiatshamir.NewTranscript(api, sha256.New(api), "gamma", "beta", "alpha", "zeta")
+	for _, p := range []sw_bls12377.G1Affine{vk.S[0], vk.S[1], vk.S[2], vk.Ql, vk.Qr, vk.Qm, vk.Qo, vk.Qk} {
+		if err := fs.Bind("gamma", v.rawBytes(p)); err != nil {
+			return err
+		}
+	}
+	wBits := make([][]frontend.Variable, len(publicInputs))
+	for i := range publicInputs {
+		wBits[i] = v.publicInputBits(publicInputs[i])
+		if err := fs.Bind("gamma", bitsToBytes(api, append(wBits[i], 0, 0, 0))); err != nil {
+			return err
+		}
+	}
+	gamma, err := v.deriveChallenge(&fs, "gamma")
+	if err != nil {
+		return err
+	}
+	beta, err := v.deriveChallenge(&fs, "beta")
+	if err != nil {
+		return err
+	}
+	alpha, err := v.deriveChallenge(&fs, "alpha", proof.Z)
+	if err != nil {
+		return err
+	}
+	zeta, err := v.deriveChallenge(&fs, "zeta", proof.H[0], proof.H[1], proof.H[2])
+	if err != nil {
+		return err
+	}
+
+	// evaluation of Z=Xⁿ-1 at ζ
+	one := f.One()
+	zetaPowerN := f.Exp(zeta, new(big.Int).SetUint64(vk.Size))
+	zhZeta := f.Sub(zetaPowerN, one)
+
+	// compute PI = ∑_{i<n} Lᵢ*wᵢ, with Lᵢ(ζ) = ωⁱ/n * (ζⁿ-1)/(ζ-ωⁱ)
+	zhOverN := f.Mul(zhZeta, f.Constant(domain.CardinalityInv))
+	var lagrangeOne *emulated.Element
+	pi := f.Zero()
+	var omegaI fr.Element
+	omegaI.SetOne()
+	for i := range publicInputs {
+		lagrange := f.Div(f.Mul(zhOverN, f.Constant(omegaI)), f.Sub(zeta, f.Constant(omegaI)))
+		if i == 0 {
+			lagrangeOne = lagrange
+		}
+		pi = f.Add(pi, f.Mul(lagrange, f.FromBits(wBits[i]...)))
+		omegaI.Mul(&omegaI, &domain.Generator)
+	}
+	if lagrangeOne == nil {
+		lagrangeOne = f.Div(zhOverN, f.Sub(zeta, one))
+	}
+
+	claimedValues := make([]*emulated.Element, nbClaimedValues)
+	for i := range claimedValues {
+		claimedValues[i] = v.scalar(proof.BatchedProof.ClaimedValues[i])
+	}
+	claimedQuotient, linearizedPolynomialZeta := claimedValues[0], claimedValues[1]
+	l, r, o, s1, s2 := claimedValues[2], claimedValues[3], claimedValues[4], claimedValues[5], claimedValues[6]
+	zu := v.scalar(proof.ZShiftedOpening.ClaimedValue)
+
+	// linearizedpolynomial + pi(ζ) + α*(Z(μζ))*(l(ζ)+β*s1(ζ)+γ)*(r(ζ)+β*s2(ζ)+γ)*(o(ζ)+γ) - α²*L₁(ζ)
+	_s1 := f.Add(f.Add(f.Mul(s1, beta), l), gamma) // (l(ζ)+β*s1(ζ)+γ)
+	_s2 := f.Add(f.Add(f.Mul(s2, beta), r), gamma) // (r(ζ)+β*s2(ζ)+γ)
+	_o := f.Add(o, gamma)                          // (o(ζ)+γ)
+	_s1 = f.Mul(f.Mul(f.Mul(f.Mul(_s1, _s2), _o), alpha), zu)
+	alphaSquareLagrange := f.Mul(f.Mul(lagrangeOne, alpha), alpha) // α²*L₁(ζ)
+
+	expected := f.Sub(f.Add(f.Add(linearizedPolynomialZeta, pi), _s1), alphaSquareLagrange)
+
+	// check that H(ζ) is as claimed: H(ζ)(ζⁿ-1) = expected
+	f.AssertIsEqual(f.Mul(claimedQuotient, zhZeta), expected)
+
+	// compute the folded commitment to H: Comm(h₁) + ζⁿ⁺²*Comm(h₂) + ζ²⁽ⁿ⁺²⁾*Comm(h₃)
+	zetaNPlusTwo := v.toScalar(f.Mul(zetaPowerN, f.Square(zeta)))
+	var foldedH sw_bls12377.G1Affine
+	foldedH.ScalarMul(api, proof.H[2], zetaNPlusTwo)
+	foldedH.AddAssign(api, proof.H[1])
+	foldedH.ScalarMul(api, foldedH, zetaNPlusTwo)
+	foldedH.AddAssign(api, proof.H[0])
+
+	// Compute the commitment to the linearized polynomial
+	// linearizedPolynomialDigest =
+	// 		l(ζ)*ql+r(ζ)*qr+r(ζ)l(ζ)*qm+o(ζ)*qo+qk +
+	// 		α*( Z(μζ)(l(ζ)+β*s₁(ζ)+γ)*(r(ζ)+β*s₂(ζ)+γ)*s₃(X)-Z(X)(l(ζ)+β*id_1(ζ)+γ)*(r(ζ)+β*id_2(ζ)+γ)*(o(ζ)+β*id_3(ζ)+γ) ) +
+	// 		α²*L₁(ζ)*Z
+	rl := f.Mul(l, r)
+
+	u := f.Mul(zu, beta)
+	vv := f.Add(f.Add(f.Mul(beta, s1), l), gamma)
+	w := f.Add(f.Add(f.Mul(beta, s2), r), gamma)
+	_s1 = f.Mul(f.Mul(f.Mul(u, vv), w), alpha) // α*Z(μζ)(l(ζ)+β*s₁(ζ)+γ)*(r(ζ)+β*s₂(ζ)+γ)*β
+
+	cosetShift := v.scalar(vk.CosetShift)
+	betaZeta := f.Mul(beta, zeta)
+	u = f.Add(f.Add(betaZeta, l), gamma)                              // (l(ζ)+β*ζ+γ)
+	vv = f.Add(f.Add(f.Mul(betaZeta, cosetShift), r), gamma)          // (r(ζ)+β*μ*ζ+γ)
+	w = f.Add(f.Add(f.Mul(betaZeta, f.Square(cosetShift)), o), gamma) // (o(ζ)+β*μ²*ζ+γ)
+	_s2 = f.Neg(f.Mul(f.Mul(u, vv), w))                               // -(l(ζ)+β*ζ+γ)*(r(ζ)+β*u*ζ+γ)*(o(ζ)+β*u²*ζ+γ)
+
+	// note since third part =  α²*L₁(ζ)*Z
+	_s2 = f.Add(f.Mul(_s2, alpha), alphaSquareLagrange) // -α*(l(ζ)+β*ζ+γ)*(r(ζ)+β*u*ζ+γ)*(o(ζ)+β*u²*ζ+γ) + α²*L₁(ζ)
+
+	linearizedPolynomialDigest := vk.Qk
+	for i, p := range []sw_bls12377.G1Affine{vk.Ql, vk.Qr, vk.Qm, vk.Qo, vk.S[2], proof.Z} {
+		s := []*emulated.Element{l, r, rl, o, _s1, _s2}[i]
+		var tmp sw_bls12377.G1Affine
+		tmp.ScalarMul(api, p, v.toScalar(s))
+		linearizedPolynomialDigest.AddAssign(api, tmp)
+	}
+
+	// fold the batched opening proof at ζ, with a challenge bound to ζ and the
+	// digests, as in kzg.FoldProof
+	digests := []sw_bls12377.G1Affine{
+		foldedH,
+		linearizedPolynomialDigest,
+		proof.LRO[0],
+		proof.LRO[1],
+		proof.LRO[2],
+		vk.S[0],
+		vk.S[1],
+	}
+	zetaBits := f.ToBits(zeta)
+	fsFold := fiatshamir.NewTranscript(api, sha256.New(api), "gamma")
+	if err := fsFold.Bind("gamma", bitsToBytes(api, append(zetaBits, 0, 0, 0))); err != nil {
+		return err
+	}
+	for _, d := range digests {
+		if err := fsFold.Bind("gamma", v.rawBytes(d)); err != nil {
+			return err
+		}
+	}
+	foldingChallenge, err := v.deriveChallenge(&fsFold, "gamma")
+	if err != nil {
+		return err
+	}
+	foldedDigest := digests[0]
+	foldedEvaluation := claimedValues[0]
+	gammaI := one
+	for i := 1; i < len(digests); i++ {
+		gammaI = f.Mul(gammaI, foldingChallenge)
+		var tmp sw_bls12377.G1Affine
+		tmp.ScalarMul(api, digests[i], v.toScalar(gammaI))
+		foldedDigest.AddAssign(api, tmp)
+		foldedEvaluation = f.Add(foldedEvaluation, f.Mul(claimedValues[i], gammaI))
+	}
+
+	// batch verify the openings of the folded digest at ζ and of Z at μζ
+	shiftedZeta := f.Mul(zeta, f.Constant(domain.Generator))
+	return kzg_bls12377.BatchVerify(api, vk.KZG,
+		[]kzg_bls12377.Commitment{{Digest: foldedDigest}, {Digest: proof.Z}},
+		[]kzg_bls12377.OpeningProof{
+			{H: proof.BatchedProof.H, ClaimedValue: v.toScalar(foldedEvaluation)},
+			proof.ZShiftedOpening,
+		},
+		[]frontend.Variable{v.toScalar(zeta), v.toScalar(shiftedZeta)},
+	)
+}
+
+// deriveChallenge binds the uncompressed points to the challenge and computes it
+func (v *verifier) deriveChallenge(fs *fiatshamir.Transcript, challenge string, points ...sw_bls12377.G1Affine) (*emulated.Element, error) {
+	for _, p := range points {
+		if err := fs.Bind(challenge, v.rawBytes(p)); err != nil {
+			return nil, err
+		}
+	}
+	c, err := fs.ComputeChallenge(challenge)
+	if err != nil {
+		return nil, err
+	}
+	return v.fr.FromBits(v.api.ToBinary(c, 8*sha256.Size)...), nil
+}
+
+// publicInputBits returns the fr.Bits little endian bits of a public input of
+// the inner circuit, checked to be smaller than r as the native public inputs
+// are: the bits of w+r would also decompose w, but be hashed differently.
+// bits.ToCanonicalBinary checks against the native modulus, larger than r.
+func (v *verifier) publicInputBits(w frontend.Variable) []frontend.Variable {
+	b := v.api.ToBinary(w, fr.Bits)
+	bits.AssertBitsLessOrEqual(v.api, b, new(big.Int).Sub(fr.Modulus(), big.NewInt(1)))
+	return b
+}
+
+// scalar returns the emulated element of a variable in 𝔽r
+func (v *verifier) scalar(x frontend.Variable) *emulated.Element {
+	return v.fr.FromBits(v.api.ToBinary(x, fr.Bits)...)
+}
+
+// toScalar returns the variable of the canonical value of an element of 𝔽r, to
+// be used in a scalar multiplication
+func (v *verifier) toScalar(e *emulated.Element) frontend.Variable {
+	return v.api.FromBinary(v.fr.ToBits(e)...)
+}
+
+// rawBytes returns the bytes of the uncompressed encoding of p, as in
+// bls12377.G1Affine.RawBytes
+func (v *verifier) rawBytes(p sw_bls12377.G1Affine) []frontend.Variable {
+	api := v.api
+	xBits := v.coordinateBits(p.X)
+
+	// the 3 most significant bits are 0b000, or 0b010 for the point at infinity
+	xBits[8*fp.Bytes-2] = api.And(api.IsZero(p.X), api.IsZero(p.Y))
+	return append(bitsToBytes(api, xBits), bitsToBytes(api, v.coordinateBits(p.Y))...)
+}
+
+// coordinateBits returns the 8*fp.Bytes little endian bits of a coordinate
+func (v *verifier) coordinateBits(x frontend.Variable) []frontend.Variable {
+	res := v.api.ToBinary(x, fp.Bits)
+	for len(res) < 8*fp.Bytes {
+		res = append(res, 0)
+	}
+	return res
+}
+
+// bitsToBytes returns the big endian bytes of little endian bits
+func bitsToBytes(api frontend.API, bits []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(bits)/8)
+	for i := range res {
+		res[len(res)-1-i] = api.FromBinary(bits[8*i : 8*(i+1)]...)
+	}
+	return res
+}
+
+// Assign values to the "in-circuit" Proof from a "out-of-circuit" Proof
+func (proof *Proof) Assign(_oproof plonk.Proof) {
+	oproof, ok := _oproof.(*plonk_bls12377.Proof)
+	if !ok {
+		panic("expected *plonk_bls12377.Proof, got " + reflect.TypeOf(_oproof).String())
+	}
+	if len(oproof.BatchedProof.ClaimedValues) != nbClaimedValues {
+		panic("unexpected number of claimed values")
+	}
+	for i := range proof.LRO {
+		proof.LRO[i].Assign(&oproof.LRO[i])
+	}
+	proof.Z.Assign(&oproof.Z)
+	for i := range proof.H {
+		proof.H[i].Assign(&oproof.H[i])
+	}
+	proof.BatchedProof.H.Assign(&oproof.BatchedProof.H)
+	for i := range proof.BatchedProof.ClaimedValues {
+		proof.BatchedProof.ClaimedValues[i] = toBigInt(&oproof.BatchedProof.ClaimedValues[i])
+	}
+	proof.ZShiftedOpening.Assign(&oproof.ZShiftedOpening)
+}
+
+// Assign values to the "in-circuit" VerifyingKey from a "out-of-circuit" VerifyingKey
+func (vk *VerifyingKey) Assign(_ovk plonk.VerifyingKey) {
+	ovk, ok := _ovk.(*plonk_bls12377.VerifyingKey)
+	if !ok {
+		panic("expected *plonk_bls12377.VerifyingKey, got " + reflect.TypeOf(_ovk).String())
+	}
+	if ovk.KZGSRS == nil {
+		panic("the KZG SRS of the verifying key is not initialized")
+	}
+	vk.Size = ovk.Size
+	vk.KZG.Assign(ovk.KZGSRS)
+	vk.CosetShift = toBigInt(&ovk.CosetShift)
+	for i := range vk.S {
+		vk.S[i].Assign(&ovk.S[i])
+	}
+	for _, p := range []struct {
+		dst *sw_bls12377.G1Affine
+		src *bls12377.G1Affine
+	}{{&vk.Ql, &ovk.Ql}, {&vk.Qr, &ovk.Qr}, {&vk.Qm, &ovk.Qm}, {&vk.Qo, &ovk.Qo}, {&vk.Qk, &ovk.Qk}} {
+		p.dst.Assign(p.src)
+	}
+}
+
+func toBigInt(e *fr.Element) *big.Int {
+	var res big.Int
+	e.ToBigIntRegular(&res)
+	return &res
+}
@@ -0,0 +1,178 @@
+package plonk_bls12377
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	plonk_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/plonk"
+	"github.com/consensys/gnark/test"
+)
+
+// innerCircuit proves the knowledge of X such that X³ + X + 5 = Y + Z
+type innerCircuit struct {
+	X    frontend.Variable
+	Y, Z frontend.Variable `gnark:",public"`
+}
+
+func (circuit *innerCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(circuit.X, circuit.X, circuit.X)
+	api.AssertIsEqual(api.Add(x3, circuit.X, 5), api.Add(circuit.Y, circuit.Z))
+	return nil
+}
+
+// generateInnerProof returns a BLS12_377 PlonK proof of innerCircuit, its
+// verifying key and the assignment of the public inputs
+func generateInnerProof(t *testing.T) (plonk.VerifyingKey, plonk.Proof, innerCircuit) {
+	ccs, err := frontend.Compile(ecc.BLS12_377, scs.NewBuilder, &innerCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srs, err := test.NewKZGSRS(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := plonk.Setup(ccs, srs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assignment := innerCircuit{X: 3, Y: 30, Z: 5}
+	witness, err := frontend.NewWitness(&assignment, ecc.BLS12_377)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicWitness, err := frontend.NewWitness(&assignment, ecc.BLS12_377, frontend.PublicOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := plonk.Prove(ccs, pk, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// before returning verifies that the proof passes on bls12377
+	if err := plonk.Verify(proof, vk, publicWitness); err != nil {
+		t.Fatal(err)
+	}
+	return vk, proof, assignment
+}
+
+type verifierCircuit struct {
+	InnerProof Proof
+	InnerVk    VerifyingKey
+	Y, Z       frontend.Variable `gnark:",public"`
+}
+
+func (circuit *verifierCircuit) Define(api frontend.API) error {
+	return Verify(api, circuit.InnerVk, circuit.InnerProof, []frontend.Variable{circuit.Y, circuit.Z})
+}
+
+func TestVerifier(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping PlonK verifier test in short mode")
+	}
+	innerVk, innerProof, innerAssignment := generateInnerProof(t)
+
+	var circuit verifierCircuit
+	circuit.InnerVk.Assign(innerVk)
+
+	var witness verifierCircuit
+	witness.InnerProof.Assign(innerProof)
+	witness.InnerVk.Assign(innerVk)
+	witness.Y, witness.Z = innerAssignment.Y, innerAssignment.Z
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+
+	// wrong public input
+	witness.Z = 6
+	assert.SolvingFailed(&circuit, &witness, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+}
+
+// TestVerifierTamperedProof checks that the verifier rejects altered proofs.
+// It only runs the test engine, the constraint system being checked against
+// it by TestVerifier.
+func TestVerifierTamperedProof(t *testing.T) {
+	innerVk, innerProof, innerAssignment := generateInnerProof(t)
+	innerPublic, err := frontend.NewWitness(&innerAssignment, ecc.BLS12_377, frontend.PublicOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, g1, _ := bls12377.Generators()
+
+	var circuit verifierCircuit
+	circuit.InnerVk.Assign(innerVk)
+
+	for name, tamper := range map[string]func(p *plonk_bls12377.Proof){
+		"claimed value": func(p *plonk_bls12377.Proof) {
+			one := fr.One()
+			p.BatchedProof.ClaimedValues[2].Add(&p.BatchedProof.ClaimedValues[2], &one)
+		},
+		"swapped commitments": func(p *plonk_bls12377.Proof) {
+			p.LRO[0], p.LRO[1] = p.LRO[1], p.LRO[0]
+		},
+		"batched opening proof": func(p *plonk_bls12377.Proof) {
+			p.BatchedProof.H.Add(&p.BatchedProof.H, &g1)
+		},
+		"shifted opening proof": func(p *plonk_bls12377.Proof) {
+			p.ZShiftedOpening.H.Add(&p.ZShiftedOpening.H, &g1)
+		},
+	} {
+		proof := *innerProof.(*plonk_bls12377.Proof)
+		proof.BatchedProof.ClaimedValues = append([]fr.Element{}, proof.BatchedProof.ClaimedValues...)
+		tamper(&proof)
+		if plonk.Verify(&proof, innerVk, innerPublic) == nil {
+			t.Fatalf("%s: the native verifier accepts the tampered proof", name)
+		}
+
+		var witness verifierCircuit
+		witness.InnerProof.Assign(&proof)
+		witness.InnerVk.Assign(innerVk)
+		witness.Y, witness.Z = innerAssignment.Y, innerAssignment.Z
+		if test.IsSolved(&circuit, &witness, ecc.BW6_761, backend.UNKNOWN) == nil {
+			t.Fatalf("%s: the verifier circuit accepts the tampered proof", name)
+		}
+	}
+
+	// the coset shift of the verifying key is used by the verifier
+	var witness verifierCircuit
+	witness.InnerProof.Assign(innerProof)
+	witness.InnerVk.Assign(innerVk)
+	witness.Y, witness.Z = innerAssignment.Y, innerAssignment.Z
+	if err := test.IsSolved(&circuit, &witness, ecc.BW6_761, backend.UNKNOWN); err != nil {
+		t.Fatal(err)
+	}
+	witness.InnerVk.CosetShift = 5
+	if test.IsSolved(&circuit, &witness, ecc.BW6_761, backend.UNKNOWN) == nil {
+		t.Fatal("the verifier circuit accepts a wrong coset shift")
+	}
+}
+
+type publicInputCircuit struct {
+	W frontend.Variable
+}
+
+func (circuit *publicInputCircuit) Define(api frontend.API) error {
+	v := verifier{api: api}
+	v.publicInputBits(circuit.W)
+	return nil
+}
+
+func TestPublicInputBits(t *testing.T) {
+	assert := test.NewAssert(t)
+	r := fr.Modulus()
+	for _, w := range []*big.Int{big.NewInt(0), new(big.Int).Sub(r, big.NewInt(1))} {
+		assert.SolvingSucceeded(&publicInputCircuit{}, &publicInputCircuit{W: w}, test.WithCurves(ecc.BW6_761))
+	}
+	// w+r decomposes w in 𝔽r, and fits in fr.Bits bits
+	for _, w := range []*big.Int{r, new(big.Int).Add(r, big.NewInt(5))} {
+		assert.SolvingFailed(&publicInputCircuit{}, &publicInputCircuit{W: w}, test.WithCurves(ecc.BW6_761))
+	}
+}
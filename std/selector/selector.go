@@ -0,0 +1,233 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package selector provides ZKP-circuit functions to access arrays of
+// variables at a variable index: multiplexers (Mux, Map), a demultiplexer
+// (Decoder) and the partition of an array at a variable index (Partition,
+// Slice).
+//
+// The functions are built on one-hot vectors (a single 1 at the selected
+// index), computed according to the backend of the circuit. In R1CS the linear
+// combinations are free, and the one-hot vector of an index is given by a hint
+// and checked with linear constraints, selections being inner products with
+// it. In SparseR1CS every addition costs a constraint, and the one-hot vectors
+// are computed from the bits of the index instead, which requires fewer
+// additions. Mux selects its output with a tree of api.Select on the bits of
+// the index, which is cheaper than an inner product on both backends.
+package selector
+
+import (
+	"math/big"
+	mbits "math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+func init() {
+	hint.Register(indicatorHint)
+}
+
+// Mux is an n to 1 multiplexer: it returns inputs[sel]. The circuit can't be
+// satisfied if sel is not in [0, len(inputs)).
+func Mux(api frontend.API, sel frontend.Variable, inputs ...frontend.Variable) frontend.Variable {
+	if len(inputs) == 0 {
+		panic("no inputs")
+	}
+	return binaryMux(api, indexBits(api, sel, len(inputs)), inputs)
+}
+
+// Map is a key-value associative array: it returns values[i] such that
+// keys[i] == key. The circuit can't be satisfied if no key is equal to key. If
+// several keys are equal to key, the result is a linear combination of the
+// corresponding values (chosen by the prover), hence the keys should be
+// distinct.
+func Map(api frontend.API, key frontend.Variable, keys, values []frontend.Variable) frontend.Variable {
+	if len(keys) != len(values) {
+		panic("keys and values must have the same length")
+	}
+	if len(keys) == 0 {
+		panic("no keys")
+	}
+	return dotProduct(api, values, keyDecoder(api, key, keys))
+}
+
+// Decoder returns the one-hot vector of n elements whose element sel is 1, all
+// the others being 0. The circuit can't be satisfied if sel is not in [0, n).
+func Decoder(api frontend.API, n int, sel frontend.Variable) []frontend.Variable {
+	if n <= 0 {
+		panic("invalid length")
+	}
+	if isSparse(api) {
+		return binaryDecoder(api, indexBits(api, sel, n), n)
+	}
+
+	keys := make([]frontend.Variable, n)
+	for i := range keys {
+		keys[i] = i
+	}
+	res := indicators(api, sel, keys)
+
+	// the elements are boolean and sum to 1, hence the vector is one-hot, and
+	// ∑ i·resᵢ = sel gives the position of its 1
+	for i := range res {
+		api.AssertIsBoolean(res[i])
+	}
+	api.AssertIsEqual(api.Add(0, 0, res...), 1)
+	api.AssertIsEqual(dotProduct(api, keys, res), sel)
+	return res
+}
+
+// Partition returns the elements of input on the right side of pivot (the
+// elements at indices i ⩾ pivot) if rightSide is true, on its left side
+// (indices i < pivot) otherwise, the other elements being set to 0. The
+// circuit can't be satisfied if pivot is not in [0, len(input)].
+func Partition(api frontend.API, pivot frontend.Variable, rightSide bool, input []frontend.Variable) []frontend.Variable {
+	mask := stepMask(api, len(input), pivot)
+	res := make([]frontend.Variable, len(input))
+	for i := range res {
+		if rightSide {
+			res[i] = api.Mul(mask[i], input[i])
+		} else {
+			res[i] = api.Sub(input[i], api.Mul(mask[i], input[i]))
+		}
+	}
+	return res
+}
+
+// Slice returns the elements of input at indices start ⩽ i < end, the other
+// elements being set to 0 (all of them if start ⩾ end). The circuit can't be
+// satisfied if start or end is not in [0, len(input)].
+func Slice(api frontend.API, start, end frontend.Variable, input []frontend.Variable) []frontend.Variable {
+	return Partition(api, end, false, Partition(api, start, true, input))
+}
+
+// stepMask returns the n elements mask[i] = 1 if i ⩾ pivot, 0 otherwise, as
+// the partial sums of the one-hot vector of pivot in [0, n]
+func stepMask(api frontend.API, n int, pivot frontend.Variable) []frontend.Variable {
+	oneHot := Decoder(api, n+1, pivot)
+	res := make([]frontend.Variable, n)
+	var sum frontend.Variable = 0
+	for i := range res {
+		sum = api.Add(sum, oneHot[i])
+		res[i] = sum
+	}
+	return res
+}
+
+// keyDecoder returns the vector whose element i is 1 if keys[i] == key, 0
+// otherwise, in which a single element is 1 if the keys are distinct
+func keyDecoder(api frontend.API, key frontend.Variable, keys []frontend.Variable) []frontend.Variable {
+	res := indicators(api, key, keys)
+
+	// the elements are 0 where keys[i] ≠ key, and sum to 1
+	for i := range res {
+		api.AssertIsEqual(api.Mul(res[i], api.Sub(keys[i], key)), 0)
+	}
+	api.AssertIsEqual(api.Add(0, 0, res...), 1)
+	return res
+}
+
+// indicators returns the (unconstrained) values [keys[i] == key]
+func indicators(api frontend.API, key frontend.Variable, keys []frontend.Variable) []frontend.Variable {
+	res, err := api.Compiler().NewHint(indicatorHint, len(keys), append([]frontend.Variable{key}, keys...)...)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// indexBits returns the ⌈log₂(n)⌉ bits of sel, checking that sel < n
+func indexBits(api frontend.API, sel frontend.Variable, n int) []frontend.Variable {
+	nbBits := mbits.Len(uint(n - 1))
+	if nbBits == 0 {
+		api.AssertIsEqual(sel, 0)
+		return nil
+	}
+	res := api.ToBinary(sel, nbBits)
+	if !isPowerOfTwo(n) {
+		bits.AssertBitsLessOrEqual(api, res, big.NewInt(int64(n-1)))
+	}
+	return res
+}
+
+// binaryMux returns inputs[sel], sel being given by its little endian bits,
+// with a tree of selections
+func binaryMux(api frontend.API, selBits, inputs []frontend.Variable) frontend.Variable {
+	for _, b := range selBits {
+		// pad the layer to an even number of elements, the padding being never
+		// selected
+		if len(inputs)%2 != 0 {
+			inputs = append(inputs, inputs[len(inputs)-1])
+		}
+		next := make([]frontend.Variable, len(inputs)/2)
+		for i := range next {
+			next[i] = api.Select(b, inputs[2*i+1], inputs[2*i])
+		}
+		inputs = next
+	}
+	return inputs[0]
+}
+
+// binaryDecoder returns the one-hot vector of n elements of the index given by
+// its little endian bits, as the products of the bits or their complements
+func binaryDecoder(api frontend.API, selBits []frontend.Variable, n int) []frontend.Variable {
+	res := []frontend.Variable{1}
+	for k := len(selBits) - 1; k >= 0; k-- {
+		// res is the one-hot vector of the number given by the bits after k,
+		// res[j] splitting into next[2j] (bit k unset) and next[2j+1] (set)
+		next := make([]frontend.Variable, 0, 2*len(res))
+		for _, v := range res {
+			set := api.Mul(v, selBits[k])
+			next = append(next, api.Sub(v, set), set)
+		}
+		res = next
+	}
+	return res[:n]
+}
+
+// dotProduct returns ∑ x[i]·y[i]
+func dotProduct(api frontend.API, x, y []frontend.Variable) frontend.Variable {
+	var res frontend.Variable = 0
+	for i := range x {
+		res = api.Add(res, api.Mul(x[i], y[i]))
+	}
+	return res
+}
+
+// isSparse returns true if the circuit is compiled for a SparseR1CS backend
+func isSparse(api frontend.API) bool {
+	return api.Compiler().Backend() == backend.PLONK
+}
+
+func isPowerOfTwo(n int) bool {
+	return n&(n-1) == 0
+}
+
+// indicatorHint returns outputs[i] = 1 if inputs[0] == inputs[1+i], 0 otherwise
+func indicatorHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	for i := range outputs {
+		if inputs[0].Cmp(inputs[1+i]) == 0 {
+			outputs[i].SetUint64(1)
+		} else {
+			outputs[i].SetUint64(0)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type muxCircuit struct {
+	Sel    frontend.Variable
+	Inputs []frontend.Variable
+	Out    frontend.Variable
+}
+
+func (c *muxCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(Mux(api, c.Sel, c.Inputs...), c.Out)
+	return nil
+}
+
+func values(n, offset int) []frontend.Variable {
+	res := make([]frontend.Variable, n)
+	for i := range res {
+		res[i] = 3*i + offset
+	}
+	return res
+}
+
+func TestMux(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, n := range []int{1, 2, 5, 8, 11} {
+		circuit := muxCircuit{Inputs: make([]frontend.Variable, n)}
+		for sel := 0; sel < n; sel++ {
+			assert.SolvingSucceeded(&circuit, &muxCircuit{Sel: sel, Inputs: values(n, 7), Out: 3*sel + 7}, test.WithCurves(ecc.BN254))
+		}
+		assert.SolvingFailed(&circuit, &muxCircuit{Sel: 0, Inputs: values(n, 7), Out: 8}, test.WithCurves(ecc.BN254))
+
+		// index out of range, the output being the one of the padding
+		assert.SolvingFailed(&circuit, &muxCircuit{Sel: n, Inputs: values(n, 7), Out: 3*(n-1) + 7}, test.WithCurves(ecc.BN254))
+	}
+}
+
+type mapCircuit struct {
+	Key          frontend.Variable
+	Keys, Values []frontend.Variable
+	Out          frontend.Variable
+}
+
+func (c *mapCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(Map(api, c.Key, c.Keys, c.Values), c.Out)
+	return nil
+}
+
+func TestMap(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	circuit := mapCircuit{Keys: make([]frontend.Variable, 4), Values: make([]frontend.Variable, 4)}
+	keys := []frontend.Variable{100, 7, 42, 9}
+	vals := []frontend.Variable{1, 2, 3, 4}
+	assert.SolvingSucceeded(&circuit, &mapCircuit{Key: 42, Keys: keys, Values: vals, Out: 3}, test.WithCurves(ecc.BN254))
+	assert.SolvingSucceeded(&circuit, &mapCircuit{Key: 100, Keys: keys, Values: vals, Out: 1}, test.WithCurves(ecc.BN254))
+	assert.SolvingFailed(&circuit, &mapCircuit{Key: 42, Keys: keys, Values: vals, Out: 2}, test.WithCurves(ecc.BN254))
+
+	// missing key
+	assert.SolvingFailed(&circuit, &mapCircuit{Key: 8, Keys: keys, Values: vals, Out: 0}, test.WithCurves(ecc.BN254))
+}
+
+type decoderCircuit struct {
+	Sel frontend.Variable
+	Out []frontend.Variable
+}
+
+func (c *decoderCircuit) Define(api frontend.API) error {
+	d := Decoder(api, len(c.Out), c.Sel)
+	for i := range d {
+		api.AssertIsEqual(d[i], c.Out[i])
+	}
+	return nil
+}
+
+func oneHot(n, sel int) []frontend.Variable {
+	res := make([]frontend.Variable, n)
+	for i := range res {
+		res[i] = 0
+	}
+	if sel < n {
+		res[sel] = 1
+	}
+	return res
+}
+
+func TestDecoder(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, n := range []int{1, 4, 6} {
+		circuit := decoderCircuit{Out: make([]frontend.Variable, n)}
+		for sel := 0; sel < n; sel++ {
+			assert.SolvingSucceeded(&circuit, &decoderCircuit{Sel: sel, Out: oneHot(n, sel)}, test.WithCurves(ecc.BN254))
+		}
+		assert.SolvingFailed(&circuit, &decoderCircuit{Sel: n, Out: oneHot(n, n)}, test.WithCurves(ecc.BN254))
+		if n > 1 {
+			assert.SolvingFailed(&circuit, &decoderCircuit{Sel: 0, Out: oneHot(n, n-1)}, test.WithCurves(ecc.BN254))
+		}
+	}
+}
+
+type sliceCircuit struct {
+	Start, End  frontend.Variable
+	Input       []frontend.Variable
+	Left, Right []frontend.Variable
+	Slice       []frontend.Variable
+}
+
+func (c *sliceCircuit) Define(api frontend.API) error {
+	assertSliceEqual(api, Partition(api, c.Start, false, c.Input), c.Left)
+	assertSliceEqual(api, Partition(api, c.Start, true, c.Input), c.Right)
+	assertSliceEqual(api, Slice(api, c.Start, c.End, c.Input), c.Slice)
+	return nil
+}
+
+func assertSliceEqual(api frontend.API, x, y []frontend.Variable) {
+	for i := range x {
+		api.AssertIsEqual(x[i], y[i])
+	}
+}
+
+func sliceWitness(n, start, end int) *sliceCircuit {
+	input := values(n, 1)
+	w := sliceCircuit{
+		Start: start, End: end, Input: input,
+		Left:  make([]frontend.Variable, n),
+		Right: make([]frontend.Variable, n),
+		Slice: make([]frontend.Variable, n),
+	}
+	for i := 0; i < n; i++ {
+		w.Left[i], w.Right[i], w.Slice[i] = 0, 0, 0
+		if i < start {
+			w.Left[i] = input[i]
+		} else {
+			w.Right[i] = input[i]
+		}
+		if start <= i && i < end {
+			w.Slice[i] = input[i]
+		}
+	}
+	return &w
+}
+
+func TestSlice(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	const n = 6
+	circuit := sliceCircuit{
+		Input: make([]frontend.Variable, n),
+		Left:  make([]frontend.Variable, n),
+		Right: make([]frontend.Variable, n),
+		Slice: make([]frontend.Variable, n),
+	}
+	for _, v := range [][2]int{{0, 0}, {0, n}, {2, 5}, {3, 3}, {4, 1}, {n, n}} {
+		assert.SolvingSucceeded(&circuit, sliceWitness(n, v[0], v[1]), test.WithCurves(ecc.BN254))
+	}
+
+	w := sliceWitness(n, 2, 5)
+	w.Slice[4] = 0
+	assert.SolvingFailed(&circuit, w, test.WithCurves(ecc.BN254))
+
+	// end out of range
+	assert.SolvingFailed(&circuit, sliceWitness(n, 2, n+1), test.WithCurves(ecc.BN254))
+}
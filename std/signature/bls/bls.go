@@ -0,0 +1,111 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bls provides a ZKP-circuit function to verify BLS signatures on
+// BLS12-377, inside a BW6-761 circuit.
+//
+// Public keys are in G2 and signatures in G1: the signature of the message m
+// under the public key [sk]g₂ is [sk]H(m), where H hashes to G1 as gnark-crypto's
+// bls12377.HashToCurveG1SSWU (see std/hash/hashtocurve). The signatures of
+// several signers on the same message are verified against the sum of their
+// public keys, which requires proofs of possession (see AggregatePublicKeys).
+package bls
+
+import (
+	"errors"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields_bls12377"
+	"github.com/consensys/gnark/std/algebra/sw_bls12377"
+	"github.com/consensys/gnark/std/hash/hashtocurve"
+)
+
+// PublicKey stores a BLS public key (to be used in gnark circuit)
+type PublicKey struct {
+	A sw_bls12377.G2Affine
+}
+
+// Signature stores a BLS signature (to be used in gnark circuit)
+type Signature struct {
+	S sw_bls12377.G1Affine
+}
+
+// Verify verifies the signature sig of msg (bytes) under pubKey, with the
+// domain separation tag dst.
+//
+// The signature and the public key are assumed to be in G1 and G2; the caller
+// must check the subgroups if they are not trusted.
+func Verify(api frontend.API, pubKey PublicKey, sig Signature, msg []frontend.Variable, dst []byte) error {
+	h, err := hashtocurve.HashToG1(api, msg, dst)
+	if err != nil {
+		return err
+	}
+
+	// e(S, -g₂)·e(H(m), A) == 1
+	_, _, _, g2 := bls12377.Generators()
+	g2.Neg(&g2)
+	var g2Neg sw_bls12377.G2Affine
+	g2Neg.Assign(&g2)
+
+	res, err := sw_bls12377.Pair(api, []sw_bls12377.G1Affine{sig.S, h}, []sw_bls12377.G2Affine{g2Neg, pubKey.A})
+	if err != nil {
+		return err
+	}
+	var one sw_bls12377.GT
+	one.SetOne()
+	res.AssertIsEqual(api, one)
+
+	return nil
+}
+
+// AggregatePublicKeys returns the sum of the public keys, against which the sum
+// of their signatures of a same message is verified.
+//
+// The aggregation is only secure if the caller has checked a proof of
+// possession of the secret key of each public key: otherwise a signer can
+// choose its key as [sk]g₂ minus the keys of the others (rogue key attack), and
+// sign alone for all of them.
+//
+// The addition being incomplete, the circuit is not satisfiable if a partial
+// sum is equal or opposite to the next key, for instance if the first two keys
+// are equal.
+func AggregatePublicKeys(api frontend.API, pubKeys []PublicKey) (PublicKey, error) {
+	if len(pubKeys) == 0 {
+		return PublicKey{}, errors.New("no public key to aggregate")
+	}
+	res := pubKeys[0]
+	for i := 1; i < len(pubKeys); i++ {
+		// res.X ≠ pubKeys[i].X, otherwise the slope of the addition is 0/0 and
+		// the prover could choose it
+		var d fields_bls12377.E2
+		d.Sub(api, res.A.X, pubKeys[i].A.X)
+		d.Inverse(api, d)
+
+		res.A.AddAssign(api, pubKeys[i].A)
+	}
+	return res, nil
+}
+
+// Assign sets the in-circuit public key from a native G2 point
+func (p *PublicKey) Assign(pk *bls12377.G2Affine) {
+	p.A.Assign(pk)
+}
+
+// Assign sets the in-circuit signature from a native G1 point
+func (s *Signature) Assign(sig *bls12377.G1Affine) {
+	s.S.Assign(sig)
+}
@@ -0,0 +1,149 @@
+package bls
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+var dst = []byte("BLS_SIG_BLS12377G1_XMD:SHA-256_SSWU_RO_POP_")
+
+const msgLen = 32
+
+func randomMessage(t *testing.T) []byte {
+	msg := make([]byte, msgLen)
+	if _, err := rand.Read(msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func assignMessage(msg []byte) []frontend.Variable {
+	res := make([]frontend.Variable, len(msg))
+	for i := range msg {
+		res[i] = msg[i]
+	}
+	return res
+}
+
+type verifyCircuit struct {
+	PublicKeys []PublicKey
+	Signature  Signature
+	Msg        []frontend.Variable
+}
+
+func (circuit *verifyCircuit) Define(api frontend.API) error {
+	pubKey, err := AggregatePublicKeys(api, circuit.PublicKeys)
+	if err != nil {
+		return err
+	}
+	return Verify(api, pubKey, circuit.Signature, circuit.Msg, dst)
+}
+
+func TestVerify(t *testing.T) {
+	const nbSigners = 3
+
+	_, _, _, g2 := bls12377.Generators()
+	msg := randomMessage(t)
+	h, err := bls12377.HashToCurveG1SSWU(msg, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// native signatures [skᵢ]H(m) under [skᵢ]g₂, and their aggregation
+	var witness verifyCircuit
+	witness.PublicKeys = make([]PublicKey, nbSigners)
+	var aggSig bls12377.G1Jac
+	var aggPk bls12377.G2Jac
+	for i := 0; i < nbSigners; i++ {
+		var sk fr.Element
+		var b big.Int
+		if _, err := sk.SetRandom(); err != nil {
+			t.Fatal(err)
+		}
+		sk.ToBigIntRegular(&b)
+
+		var pk bls12377.G2Affine
+		pk.ScalarMultiplication(&g2, &b)
+		witness.PublicKeys[i].Assign(&pk)
+		var pkJac bls12377.G2Jac
+		pkJac.FromAffine(&pk)
+		aggPk.AddAssign(&pkJac)
+
+		var sig bls12377.G1Affine
+		sig.ScalarMultiplication(&h, &b)
+		var sigJac bls12377.G1Jac
+		sigJac.FromAffine(&sig)
+		aggSig.AddAssign(&sigJac)
+	}
+	var sig bls12377.G1Affine
+	sig.FromJacobian(&aggSig)
+	var pk bls12377.G2Affine
+	pk.FromJacobian(&aggPk)
+
+	// native verification
+	var sigNeg bls12377.G1Affine
+	sigNeg.Neg(&sig)
+	ok, err := bls12377.PairingCheck([]bls12377.G1Affine{sigNeg, h}, []bls12377.G2Affine{g2, pk})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("native verification failed")
+	}
+
+	witness.Signature.Assign(&sig)
+	witness.Msg = assignMessage(msg)
+
+	circuit := verifyCircuit{PublicKeys: make([]PublicKey, nbSigners), Msg: make([]frontend.Variable, msgLen)}
+	if err := test.IsSolved(&circuit, &witness, ecc.BW6_761, backend.UNKNOWN); err != nil {
+		t.Fatal(err)
+	}
+
+	// signature of another message
+	msg[0] ^= 1
+	witness.Msg = assignMessage(msg)
+	if err := test.IsSolved(&circuit, &witness, ecc.BW6_761, backend.UNKNOWN); err == nil {
+		t.Fatal("expected the verification to fail")
+	}
+}
+
+type aggregateCircuit struct {
+	PublicKeys []PublicKey
+}
+
+func (circuit *aggregateCircuit) Define(api frontend.API) error {
+	_, err := AggregatePublicKeys(api, circuit.PublicKeys)
+	return err
+}
+
+func TestAggregatePublicKeysIncomplete(t *testing.T) {
+	_, _, _, g2 := bls12377.Generators()
+	var pk1, pk2, pk1Neg bls12377.G2Affine
+	pk1.ScalarMultiplication(&g2, big.NewInt(3))
+	pk2.ScalarMultiplication(&g2, big.NewInt(5))
+	pk1Neg.Neg(&pk1)
+
+	circuit := aggregateCircuit{PublicKeys: make([]PublicKey, 2)}
+	witness := aggregateCircuit{PublicKeys: make([]PublicKey, 2)}
+	witness.PublicKeys[0].Assign(&pk1)
+	witness.PublicKeys[1].Assign(&pk2)
+	if err := test.IsSolved(&circuit, &witness, ecc.BW6_761, backend.UNKNOWN); err != nil {
+		t.Fatal(err)
+	}
+
+	// equal and opposite keys, where the addition is not defined
+	for _, pk := range []*bls12377.G2Affine{&pk1, &pk1Neg} {
+		witness.PublicKeys[1].Assign(pk)
+		if err := test.IsSolved(&circuit, &witness, ecc.BW6_761, backend.UNKNOWN); err == nil {
+			t.Fatal("expected the aggregation to fail")
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rsa provides a ZKP-circuit function to verify RSA PKCS #1 v1.5
+// signatures (RFC 8017, section 8.2), compatible with crypto/rsa.
+//
+// The modulus and the signature are big integers of std/math/bigint, whose
+// size is fixed at compile time by bigint.Params (for instance
+// bigint.RSA2048); the public exponent is a constant of the circuit. The
+// message is given by its digest, which can be computed in the circuit (for
+// example with std/hash/sha256).
+package rsa
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bigint"
+)
+
+// hashPrefixes are the DER encodings of the DigestInfo of RFC 8017 (section
+// 9.2), without the digest
+var hashPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA1:   {0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e, 0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14},
+	crypto.SHA224: {0x30, 0x2d, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x04, 0x05, 0x00, 0x04, 0x1c},
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+// PublicKey stores an RSA public key (to be used in gnark circuit)
+type PublicKey struct {
+	N bigint.Int
+
+	// E is the public exponent. It is a constant of the circuit, set in the
+	// circuit definition, and not part of the witness.
+	E int `gnark:"-"`
+}
+
+// Signature stores an RSA signature (to be used in gnark circuit)
+type Signature struct {
+	S bigint.Int
+}
+
+// VerifyPKCS1v15 verifies that sig is a valid RSA PKCS #1 v1.5 signature of
+// hashed under pubKey, hashed being the digest of the message with hash, as
+// bytes. The modulus must have NbLimbs·BitsPerLimb/8 bytes, i.e. its most
+// significant byte must be non-zero.
+//
+// An error is returned if the parameters are not supported; the validity of
+// the signature is enforced by the constraints.
+func VerifyPKCS1v15(api frontend.API, params bigint.Params, pubKey PublicKey, hash crypto.Hash, hashed []frontend.Variable, sig Signature) error {
+	prefix, ok := hashPrefixes[hash]
+	if !ok {
+		return fmt.Errorf("unsupported hash function %v", hash)
+	}
+	if len(hashed) != hash.Size() {
+		return fmt.Errorf("expected a digest of %d bytes, got %d", hash.Size(), len(hashed))
+	}
+	if pubKey.E < 2 {
+		return fmt.Errorf("invalid public exponent %d", pubKey.E)
+	}
+	nbBits := params.NbLimbs * params.BitsPerLimb
+	k := nbBits / 8
+	if nbBits%8 != 0 || k < len(prefix)+len(hashed)+11 {
+		return fmt.Errorf("unsupported modulus size of %d bits", nbBits)
+	}
+	if len(pubKey.N.Limbs) != params.NbLimbs || len(sig.S.Limbs) != params.NbLimbs {
+		return fmt.Errorf("expected integers of %d limbs", params.NbLimbs)
+	}
+	f, err := bigint.NewArithmetic(api, params)
+	if err != nil {
+		return err
+	}
+
+	// the modulus has k bytes, and the signature is smaller than the modulus
+	nBits := f.ToBits(&pubKey.N)
+	api.AssertIsDifferent(api.Add(0, 0, nBits[nbBits-8:]...), 0)
+	f.AssertIsLess(&sig.S, &pubKey.N)
+
+	// EM = 0x00 ‖ 0x01 ‖ 0xff…0xff ‖ 0x00 ‖ prefix ‖ hashed, on k bytes
+	em := make([]frontend.Variable, 0, k)
+	em = append(em, 0x00, 0x01)
+	for i := 0; i < k-len(prefix)-len(hashed)-3; i++ {
+		em = append(em, 0xff)
+	}
+	em = append(em, 0x00)
+	for _, b := range prefix {
+		em = append(em, b)
+	}
+	em = append(em, hashed...)
+
+	// little endian bits of EM, the bytes of the digest being range checked
+	emBits := make([]frontend.Variable, 0, nbBits)
+	for i := len(em) - 1; i >= 0; i-- {
+		emBits = append(emBits, api.ToBinary(em[i], 8)...)
+	}
+
+	m := f.ModExp(&sig.S, big.NewInt(int64(pubKey.E)), &pubKey.N)
+	f.AssertIsEqual(m, f.FromBits(emBits...))
+	return nil
+}
+
+// Assign sets the in-circuit public key from a crypto/rsa public key
+func (p *PublicKey) Assign(params bigint.Params, pk *rsa.PublicKey) {
+	p.N = params.ValueOf(pk.N)
+	p.E = pk.E
+}
+
+// Assign sets the in-circuit signature from a signature of crypto/rsa
+func (s *Signature) Assign(params bigint.Params, sig []byte) {
+	s.S = params.ValueOf(new(big.Int).SetBytes(sig))
+}
@@ -0,0 +1,113 @@
+package rsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //#nosec G505 -- SHA-1 is still used by e-passports
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bigint"
+	"github.com/consensys/gnark/test"
+)
+
+type rsaCircuit struct {
+	params bigint.Params
+	hash   crypto.Hash
+
+	PublicKey PublicKey `gnark:",public"`
+	Hashed    []frontend.Variable
+	Signature Signature
+}
+
+func (circuit *rsaCircuit) Define(api frontend.API) error {
+	return VerifyPKCS1v15(api, circuit.params, circuit.PublicKey, circuit.hash, circuit.Hashed, circuit.Signature)
+}
+
+func newCircuit(params bigint.Params, hash crypto.Hash, e int) *rsaCircuit {
+	return &rsaCircuit{
+		params:    params,
+		hash:      hash,
+		PublicKey: PublicKey{N: params.Placeholder(), E: e},
+		Hashed:    make([]frontend.Variable, hash.Size()),
+		Signature: Signature{S: params.Placeholder()},
+	}
+}
+
+func newWitness(params bigint.Params, pk *rsa.PublicKey, hashed, sig []byte) *rsaCircuit {
+	var w rsaCircuit
+	w.PublicKey.Assign(params, pk)
+	w.Signature.Assign(params, sig)
+	w.Hashed = make([]frontend.Variable, len(hashed))
+	for i := range hashed {
+		w.Hashed[i] = hashed[i]
+	}
+	return &w
+}
+
+// checkVerify checks that the circuit is solved iff crypto/rsa accepts the
+// signature
+func checkVerify(t *testing.T, circuit *rsaCircuit, pk *rsa.PublicKey, hashed, sig []byte) {
+	t.Helper()
+	expected := rsa.VerifyPKCS1v15(pk, circuit.hash, hashed, sig) == nil
+	err := test.IsSolved(circuit, newWitness(circuit.params, pk, hashed, sig), ecc.BN254, backend.UNKNOWN)
+	if expected && err != nil {
+		t.Fatal(err)
+	}
+	if !expected && err == nil {
+		t.Fatal("expected the verification to fail")
+	}
+}
+
+func TestVerifyPKCS1v15(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("the quick brown fox jumps over the lazy dog")
+	hashed := sha256.Sum256(msg)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := newCircuit(bigint.RSA2048, crypto.SHA256, key.E)
+
+	checkVerify(t, circuit, &key.PublicKey, hashed[:], sig)
+
+	// other message
+	other := sha256.Sum256(msg[1:])
+	checkVerify(t, circuit, &key.PublicKey, other[:], sig)
+
+	// altered signature
+	sig[len(sig)-1] ^= 1
+	checkVerify(t, circuit, &key.PublicKey, hashed[:], sig)
+	sig[len(sig)-1] ^= 1
+
+	// other key
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkVerify(t, circuit, &otherKey.PublicKey, hashed[:], sig)
+}
+
+func TestVerifyPKCS1v15SHA1(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashed := sha1.Sum([]byte("e-passport")) //#nosec G401 -- see the import
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, hashed[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := newCircuit(bigint.Params{BitsPerLimb: 64, NbLimbs: 16}, crypto.SHA1, key.E)
+
+	checkVerify(t, circuit, &key.PublicKey, hashed[:], sig)
+	hashed[0] ^= 1
+	checkVerify(t, circuit, &key.PublicKey, hashed[:], sig)
+}
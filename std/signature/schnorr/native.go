@@ -0,0 +1,266 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// The native functions below hash field elements the way the in-circuit Verify
+// does: each element is written in big endian on the byte size of the snark
+// field, and the digest is read as a big endian number. This is the convention
+// of the gnark-crypto MiMC implementations (see gnark-crypto/hash), which match
+// std/hash/mimc.
+
+// NativePoint is a point of a twisted Edwards curve, out of circuit
+type NativePoint struct {
+	X, Y *big.Int
+}
+
+// NativePublicKey is a Schnorr public key, out of circuit
+type NativePublicKey struct {
+	ID tedwards.ID
+	A  NativePoint
+}
+
+// NativeSignature is a Schnorr signature, out of circuit
+type NativeSignature struct {
+	E, S *big.Int
+}
+
+// PrivateKey is a Schnorr private key, out of circuit
+type PrivateKey struct {
+	PublicKey NativePublicKey
+	scalar    *big.Int
+}
+
+// GenerateKey returns a new private key on the twisted Edwards curve id, the
+// randomness being read from r
+func GenerateKey(id tedwards.ID, r io.Reader) (*PrivateKey, error) {
+	c, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	x, err := randomScalar(c, r)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{
+		PublicKey: NativePublicKey{ID: id, A: newNativePoint(c.ScalarMul(c.Params().Base, x))},
+		scalar:    x,
+	}, nil
+}
+
+// Sign signs msg, the challenge being computed with h and the commitment
+// randomness read from r
+func (sk *PrivateKey) Sign(msg *big.Int, h hash.Hash, r io.Reader) (NativeSignature, error) {
+	c, err := twistededwards.NewNativeCurve(sk.PublicKey.ID)
+	if err != nil {
+		return NativeSignature{}, err
+	}
+	nonce, err := NewNonce(sk.PublicKey.ID, r)
+	if err != nil {
+		return NativeSignature{}, err
+	}
+	e := challenge(c, h, nonce.R, sk.PublicKey.A, msg)
+	return NativeSignature{E: e, S: sk.response(c, nonce, big.NewInt(1), e)}, nil
+}
+
+// Verify returns true if sig is a valid signature of msg under pk, the
+// challenge being computed with h
+func (pk NativePublicKey) Verify(sig NativeSignature, msg *big.Int, h hash.Hash) (bool, error) {
+	c, err := twistededwards.NewNativeCurve(pk.ID)
+	if err != nil {
+		return false, err
+	}
+	if !isOnCurve(c, pk.A) {
+		return false, errors.New("public key is not on the curve")
+	}
+	if sig.E == nil || sig.S == nil || sig.S.Sign() < 0 || sig.S.Cmp(c.Params().Order) >= 0 {
+		return false, nil
+	}
+
+	// R = [S]G-[E]A
+	R := newNativePoint(c.Add(c.ScalarMul(c.Params().Base, sig.S), c.Neg(c.ScalarMul(pk.A.coords(), sig.E))))
+
+	return challenge(c, h, R, pk.A, msg).Cmp(sig.E) == 0, nil
+}
+
+// Nonce is the secret commitment randomness of a signer in a MuSig session
+type Nonce struct {
+	k *big.Int
+
+	// R = [k]G is the commitment shared with the other signers
+	R NativePoint
+}
+
+// NewNonce returns a new random nonce on the twisted Edwards curve id
+func NewNonce(id tedwards.ID, r io.Reader) (*Nonce, error) {
+	c, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	k, err := randomScalar(c, r)
+	if err != nil {
+		return nil, err
+	}
+	return &Nonce{k: k, R: newNativePoint(c.ScalarMul(c.Params().Base, k))}, nil
+}
+
+// AggregatePublicKeys returns the MuSig aggregated key Σ aᵢ·Aᵢ of keys, where
+// the coefficient aᵢ = H(A₀, …, Aₙ₋₁, Aᵢ) is computed with h. The coefficients
+// are returned too, to be given to SignPartial.
+func AggregatePublicKeys(keys []NativePublicKey, h hash.Hash) (NativePublicKey, []*big.Int, error) {
+	if len(keys) == 0 {
+		return NativePublicKey{}, nil, errors.New("no key to aggregate")
+	}
+	id := keys[0].ID
+	c, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return NativePublicKey{}, nil, err
+	}
+	for i := range keys {
+		if keys[i].ID != id {
+			return NativePublicKey{}, nil, errors.New("keys are not on the same curve")
+		}
+		if !isOnCurve(c, keys[i].A) {
+			return NativePublicKey{}, nil, errors.New("public key is not on the curve")
+		}
+	}
+
+	coeffs := make([]*big.Int, len(keys))
+	agg := identity()
+	for i := range keys {
+		h.Reset()
+		for j := range keys {
+			write(c, h, keys[j].A.X, keys[j].A.Y)
+		}
+		write(c, h, keys[i].A.X, keys[i].A.Y)
+		coeffs[i] = new(big.Int).SetBytes(h.Sum(nil))
+		agg = c.Add(agg, c.ScalarMul(keys[i].A.coords(), coeffs[i]))
+	}
+	return NativePublicKey{ID: id, A: newNativePoint(agg)}, coeffs, nil
+}
+
+// AggregateNonces returns the commitment R = Σ Rᵢ of a MuSig session and its
+// challenge E = H(R, aggKey, msg), computed with h
+func AggregateNonces(aggKey NativePublicKey, commitments []NativePoint, msg *big.Int, h hash.Hash) (NativePoint, *big.Int, error) {
+	c, err := twistededwards.NewNativeCurve(aggKey.ID)
+	if err != nil {
+		return NativePoint{}, nil, err
+	}
+	R := identity()
+	for i := range commitments {
+		if !isOnCurve(c, commitments[i]) {
+			return NativePoint{}, nil, errors.New("commitment is not on the curve")
+		}
+		R = c.Add(R, commitments[i].coords())
+	}
+	return newNativePoint(R), challenge(c, h, newNativePoint(R), aggKey.A, msg), nil
+}
+
+// SignPartial returns the share k + e·a·x of the response of a MuSig
+// signature, where a is the coefficient of the key (see AggregatePublicKeys)
+// and e the challenge of the session (see AggregateNonces). A nonce must never
+// be used twice.
+func (sk *PrivateKey) SignPartial(nonce *Nonce, coeff, e *big.Int) (*big.Int, error) {
+	c, err := twistededwards.NewNativeCurve(sk.PublicKey.ID)
+	if err != nil {
+		return nil, err
+	}
+	return sk.response(c, nonce, coeff, e), nil
+}
+
+// AggregateSignatures returns the MuSig signature (e, Σ sᵢ) from the partial
+// responses of all signers
+func AggregateSignatures(id tedwards.ID, e *big.Int, partials []*big.Int) (NativeSignature, error) {
+	c, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return NativeSignature{}, err
+	}
+	s := new(big.Int)
+	for i := range partials {
+		s.Add(s, partials[i])
+	}
+	s.Mod(s, c.Params().Order)
+	return NativeSignature{E: new(big.Int).Set(e), S: s}, nil
+}
+
+// response returns k + e·coeff·x mod the order of the subgroup
+func (sk *PrivateKey) response(c *twistededwards.NativeCurve, nonce *Nonce, coeff, e *big.Int) *big.Int {
+	s := new(big.Int).Mul(e, coeff)
+	s.Mod(s, c.Params().Order).
+		Mul(s, sk.scalar).
+		Add(s, nonce.k).
+		Mod(s, c.Params().Order)
+	return s
+}
+
+// coords returns the coordinates of p, as used by twistededwards.NativeCurve
+func (p NativePoint) coords() [2]*big.Int {
+	return [2]*big.Int{p.X, p.Y}
+}
+
+func newNativePoint(p [2]*big.Int) NativePoint {
+	return NativePoint{X: p[0], Y: p[1]}
+}
+
+func identity() [2]*big.Int {
+	return [2]*big.Int{big.NewInt(0), big.NewInt(1)}
+}
+
+func isOnCurve(c *twistededwards.NativeCurve, p NativePoint) bool {
+	return p.X != nil && p.Y != nil && c.IsOnCurve(p.coords())
+}
+
+// randomScalar returns a scalar in [1, order)
+func randomScalar(c *twistededwards.NativeCurve, r io.Reader) (*big.Int, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	k, err := rand.Int(r, new(big.Int).Sub(c.Params().Order, big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+	return k.Add(k, big.NewInt(1)), nil
+}
+
+// challenge returns H(R.X, R.Y, A.X, A.Y, msg)
+func challenge(c *twistededwards.NativeCurve, h hash.Hash, R, A NativePoint, msg *big.Int) *big.Int {
+	h.Reset()
+	write(c, h, R.X, R.Y, A.X, A.Y, msg)
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// write writes the field elements in h, in big endian on the byte size of the
+// snark field
+func write(c *twistededwards.NativeCurve, h hash.Hash, elements ...*big.Int) {
+	size := (c.Modulus().BitLen() + 7) / 8
+	buf := make([]byte, size)
+	for _, e := range elements {
+		var v big.Int
+		v.Mod(e, c.Modulus()).FillBytes(buf)
+		_, _ = h.Write(buf)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schnorr provides a ZKP-circuit function to verify a Schnorr
+// signature on a twisted Edwards curve, and its native (out of circuit)
+// counterpart.
+//
+// A signature of the message m under the public key A = [x]G is the pair
+// (E, S) where E = H(R.X, R.Y, A.X, A.Y, m) for a random commitment R = [k]G
+// and S = k + E·x mod the order of G. The hash function H is chosen by the
+// caller, so that a MuSig aggregated public key (see AggregatePublicKeys) is
+// verified like any other key.
+package schnorr
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// PublicKey stores a Schnorr public key (to be used in gnark circuit)
+type PublicKey struct {
+	A twistededwards.Point
+}
+
+// Signature stores a Schnorr signature (to be used in gnark circuit)
+// E is the challenge and S the response. E is an output of the hash function,
+// and Verify checks that S is smaller than the order of the subgroup, such that
+// a signature has a single encoding.
+type Signature struct {
+	E, S frontend.Variable
+}
+
+// Verify verifies a Schnorr signature of msg under pubKey, the challenge being
+// computed with hash
+func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pubKey PublicKey, hash hash.Hash) error {
+	api := curve.API()
+	curve.AssertIsOnCurve(pubKey.A)
+
+	// S < order, otherwise (E, S+order) would be another valid signature
+	order := curve.Params().Order
+	sBits := api.ToBinary(sig.S, order.BitLen())
+	bits.AssertBitsLessOrEqual(api, sBits, new(big.Int).Sub(order, big.NewInt(1)))
+
+	base := twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+
+	// R = [S]G-[E]A
+	R := curve.DoubleBaseScalarMul(base, curve.Neg(pubKey.A), sig.S, sig.E)
+
+	// E == H(R, A, M)
+	hash.Reset()
+	hash.Write(R.X, R.Y, pubKey.A.X, pubKey.A.Y, msg)
+	api.AssertIsEqual(hash.Sum(), sig.E)
+
+	return nil
+}
+
+// Assign sets the in-circuit public key from a native public key
+func (p *PublicKey) Assign(pk NativePublicKey) {
+	p.A.X = pk.A.X
+	p.A.Y = pk.A.Y
+}
+
+// Assign sets the in-circuit signature from a native signature
+func (s *Signature) Assign(sig NativeSignature) {
+	s.E = sig.E
+	s.S = sig.S
+}
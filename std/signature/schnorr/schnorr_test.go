@@ -0,0 +1,171 @@
+package schnorr
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type schnorrCircuit struct {
+	curveID   tedwards.ID
+	PublicKey PublicKey         `gnark:",public"`
+	Signature Signature         `gnark:",public"`
+	Message   frontend.Variable `gnark:",public"`
+}
+
+func (circuit *schnorrCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+
+	mimc, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+
+	return Verify(curve, circuit.Signature, circuit.Message, circuit.PublicKey, &mimc)
+}
+
+type testData struct {
+	name  string
+	hash  hash.Hash
+	curve tedwards.ID
+}
+
+var confs = []testData{
+	{"BN254", hash.MIMC_BN254, tedwards.BN254},
+	{"BLS12_381", hash.MIMC_BLS12_381, tedwards.BLS12_381},
+	{"BLS12_381_BANDERSNATCH", hash.MIMC_BLS12_381, tedwards.BLS12_381_BANDERSNATCH},
+	{"BLS12_377", hash.MIMC_BLS12_377, tedwards.BLS12_377},
+	{"BW6_761", hash.MIMC_BW6_761, tedwards.BW6_761},
+	{"BLS24_315", hash.MIMC_BLS24_315, tedwards.BLS24_315},
+	{"BW6_633", hash.MIMC_BW6_633, tedwards.BW6_633},
+}
+
+func TestSchnorr(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	seed := time.Now().Unix()
+	t.Logf("setting seed in rand %d", seed)
+	randomness := rand.New(rand.NewSource(seed))
+
+	// the circuits must have distinct addresses, as the compiled circuits are
+	// cached by address and several twisted Edwards curves share a snark curve
+	circuits := make([]schnorrCircuit, len(confs))
+
+	for i, conf := range confs {
+		conf, circuit := conf, &circuits[i]
+		circuit.curveID = conf.curve
+		assert.Run(func(assert *test.Assert) {
+			snarkCurve, err := twistededwards.GetSnarkCurve(conf.curve)
+			assert.NoError(err)
+
+			privKey, err := GenerateKey(conf.curve, randomness)
+			assert.NoError(err, "generating schnorr key pair")
+
+			var msg big.Int
+			msg.Rand(randomness, snarkCurve.Info().Fr.Modulus())
+
+			signature, err := privKey.Sign(&msg, conf.hash.New(), randomness)
+			assert.NoError(err, "signing message")
+
+			checkSig, err := privKey.PublicKey.Verify(signature, &msg, conf.hash.New())
+			assert.NoError(err, "verifying signature")
+			assert.True(checkSig, "signature verification failed")
+
+			// verification with the correct message
+			var witness schnorrCircuit
+			witness.Message = &msg
+			witness.PublicKey.Assign(privKey.PublicKey)
+			witness.Signature.Assign(signature)
+			assert.SolvingSucceeded(circuit, &witness, test.WithCurves(snarkCurve))
+
+			// verification with an incorrect message
+			witness.Message = new(big.Int).Add(&msg, big.NewInt(1))
+			assert.SolvingFailed(circuit, &witness, test.WithCurves(snarkCurve))
+
+			// the response is not reduced: [S+order]G = [S]G
+			params, err := twistededwards.GetCurveParams(conf.curve)
+			assert.NoError(err)
+			witness.Message = &msg
+			witness.Signature.S = new(big.Int).Add(signature.S, params.Order)
+			assert.SolvingFailed(circuit, &witness, test.WithCurves(snarkCurve))
+		}, conf.name)
+	}
+}
+
+func TestMuSig(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	seed := time.Now().Unix()
+	t.Logf("setting seed in rand %d", seed)
+	randomness := rand.New(rand.NewSource(seed))
+
+	const nbSigners = 3
+
+	// the circuits must have distinct addresses, as the compiled circuits are
+	// cached by address and several twisted Edwards curves share a snark curve
+	circuits := make([]schnorrCircuit, len(confs))
+
+	for i, conf := range confs {
+		conf, circuit := conf, &circuits[i]
+		circuit.curveID = conf.curve
+		assert.Run(func(assert *test.Assert) {
+			snarkCurve, err := twistededwards.GetSnarkCurve(conf.curve)
+			assert.NoError(err)
+
+			privKeys := make([]*PrivateKey, nbSigners)
+			pubKeys := make([]NativePublicKey, nbSigners)
+			nonces := make([]*Nonce, nbSigners)
+			commitments := make([]NativePoint, nbSigners)
+			for i := 0; i < nbSigners; i++ {
+				privKeys[i], err = GenerateKey(conf.curve, randomness)
+				assert.NoError(err)
+				pubKeys[i] = privKeys[i].PublicKey
+				nonces[i], err = NewNonce(conf.curve, randomness)
+				assert.NoError(err)
+				commitments[i] = nonces[i].R
+			}
+
+			var msg big.Int
+			msg.Rand(randomness, snarkCurve.Info().Fr.Modulus())
+
+			aggKey, coeffs, err := AggregatePublicKeys(pubKeys, conf.hash.New())
+			assert.NoError(err)
+			_, e, err := AggregateNonces(aggKey, commitments, &msg, conf.hash.New())
+			assert.NoError(err)
+			partials := make([]*big.Int, nbSigners)
+			for i := 0; i < nbSigners; i++ {
+				partials[i], err = privKeys[i].SignPartial(nonces[i], coeffs[i], e)
+				assert.NoError(err)
+			}
+			signature, err := AggregateSignatures(conf.curve, e, partials)
+			assert.NoError(err)
+
+			checkSig, err := aggKey.Verify(signature, &msg, conf.hash.New())
+			assert.NoError(err)
+			assert.True(checkSig, "aggregated signature verification failed")
+
+			var witness schnorrCircuit
+			witness.Message = &msg
+			witness.PublicKey.Assign(aggKey)
+			witness.Signature.Assign(signature)
+			assert.SolvingSucceeded(circuit, &witness, test.WithCurves(snarkCurve))
+
+			// a partial signature is not a valid signature under the aggregated key
+			signature, err = AggregateSignatures(conf.curve, e, partials[1:])
+			assert.NoError(err)
+			witness.Signature.Assign(signature)
+			assert.SolvingFailed(circuit, &witness, test.WithCurves(snarkCurve))
+		}, conf.name)
+	}
+}
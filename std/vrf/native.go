@@ -0,0 +1,182 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vrf
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash/hashtocurve"
+)
+
+// The native functions below hash field elements the way the in-circuit Verify
+// does: each element is written in big endian on the byte size of the snark
+// field, and the digest is read as a big endian number. This is the convention
+// of the gnark-crypto MiMC implementations (see gnark-crypto/hash), which match
+// std/hash/mimc.
+
+// NativePublicKey is a VRF public key, out of circuit
+type NativePublicKey struct {
+	ID tedwards.ID
+	Y  [2]*big.Int
+}
+
+// PrivateKey is a VRF private key, out of circuit
+type PrivateKey struct {
+	PublicKey NativePublicKey
+	scalar    *big.Int
+}
+
+// NativeProof is a VRF proof, out of circuit
+type NativeProof struct {
+	Gamma [2]*big.Int
+	C, S  *big.Int
+}
+
+// GenerateKey returns a new private key on the twisted Edwards curve id, the
+// randomness being read from r
+func GenerateKey(id tedwards.ID, r io.Reader) (*PrivateKey, error) {
+	curve, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// x in [1, order)
+	max := new(big.Int).Sub(curve.Params().Order, big.NewInt(1))
+	x, err := rand.Int(r, max)
+	if err != nil {
+		return nil, err
+	}
+	x.Add(x, big.NewInt(1))
+
+	return &PrivateKey{
+		PublicKey: NativePublicKey{ID: id, Y: curve.ScalarMul(curve.Params().Base, x)},
+		scalar:    x,
+	}, nil
+}
+
+// Prove returns the proof of the input alpha, the challenge being computed
+// with h. The nonce is derived deterministically from the private key and the
+// input (RFC 9381, section 5.4.2.2, with SHA-512).
+func (sk *PrivateKey) Prove(alpha []byte, h hash.Hash) (NativeProof, error) {
+	curve, err := twistededwards.NewNativeCurve(sk.PublicKey.ID)
+	if err != nil {
+		return NativeProof{}, err
+	}
+	order := curve.Params().Order
+
+	H, err := encodeToCurve(curve, sk.PublicKey, alpha)
+	if err != nil {
+		return NativeProof{}, err
+	}
+	gamma := curve.ScalarMul(H, sk.scalar)
+
+	// k = SHA-512(x ‖ H) mod order
+	nonceHash := sha512.New()
+	nonceHash.Write(sk.scalar.FillBytes(make([]byte, (order.BitLen()+7)/8)))
+	nonceHash.Write(curve.Compress(H))
+	k := new(big.Int).SetBytes(nonceHash.Sum(nil))
+	k.Mod(k, order)
+
+	base := curve.Params().Base
+	c := challenge(curve, h, sk.PublicKey.Y, H, gamma, curve.ScalarMul(base, k), curve.ScalarMul(H, k))
+
+	// s = k + c·x mod order
+	s := new(big.Int).Mul(c, sk.scalar)
+	s.Add(s, k).Mod(s, order)
+
+	return NativeProof{Gamma: gamma, C: c, S: s}, nil
+}
+
+// Verify returns true if proof is a valid proof of the input alpha under pk,
+// the challenge being computed with h. The output of the VRF is then given by
+// ProofToHash.
+func (pk *NativePublicKey) Verify(alpha []byte, proof NativeProof, h hash.Hash) (bool, error) {
+	curve, err := twistededwards.NewNativeCurve(pk.ID)
+	if err != nil {
+		return false, err
+	}
+	if !curve.IsOnCurve(pk.Y) {
+		return false, errors.New("public key is not on the curve")
+	}
+	if hY := curve.ScalarMul(pk.Y, curve.Params().Cofactor); hY[0].Sign() == 0 {
+		return false, errors.New("public key is of small order")
+	}
+	if !curve.IsOnCurve(proof.Gamma) || proof.C == nil || proof.S == nil ||
+		proof.S.Sign() < 0 || proof.S.Cmp(curve.Params().Order) >= 0 {
+		return false, nil
+	}
+
+	H, err := encodeToCurve(curve, *pk, alpha)
+	if err != nil {
+		return false, err
+	}
+
+	// U = [s]B-[c]Y, V = [s]H-[c]Γ
+	c := new(big.Int).Mod(proof.C, curve.Modulus())
+	U := curve.Add(curve.ScalarMul(curve.Params().Base, proof.S), curve.Neg(curve.ScalarMul(pk.Y, c)))
+	V := curve.Add(curve.ScalarMul(H, proof.S), curve.Neg(curve.ScalarMul(proof.Gamma, c)))
+
+	return challenge(curve, h, pk.Y, H, proof.Gamma, U, V).Cmp(c) == 0, nil
+}
+
+// ProofToHash returns the output β = Hash(3, [h]Γ) of the VRF given by proof,
+// which is not verified, the hash being computed with h
+func (pk *NativePublicKey) ProofToHash(proof NativeProof, h hash.Hash) (*big.Int, error) {
+	curve, err := twistededwards.NewNativeCurve(pk.ID)
+	if err != nil {
+		return nil, err
+	}
+	gamma := curve.ScalarMul(proof.Gamma, curve.Params().Cofactor)
+	h.Reset()
+	write(curve, h, big.NewInt(outputDomainSeparator), gamma[0], gamma[1])
+	return new(big.Int).SetBytes(h.Sum(nil)), nil
+}
+
+// encodeToCurve returns encode_to_curve(Y ‖ alpha)
+func encodeToCurve(curve *twistededwards.NativeCurve, pk NativePublicKey, alpha []byte) ([2]*big.Int, error) {
+	msg := append(curve.Compress(pk.Y), alpha...)
+	return hashtocurve.NativeEncodeToTwistedEdwards(pk.ID, msg, encodeToCurveDST)
+}
+
+// challenge returns Hash(2, Y, H, Γ, U, V)
+func challenge(curve *twistededwards.NativeCurve, h hash.Hash, points ...[2]*big.Int) *big.Int {
+	h.Reset()
+	write(curve, h, big.NewInt(challengeDomainSeparator))
+	for _, p := range points {
+		write(curve, h, p[0], p[1])
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// write writes the field elements in h, in big endian on the byte size of the
+// snark field
+func write(curve *twistededwards.NativeCurve, h hash.Hash, elements ...*big.Int) {
+	size := (curve.Modulus().BitLen() + 7) / 8
+	buf := make([]byte, size)
+	for _, e := range elements {
+		var v big.Int
+		v.Mod(e, curve.Modulus()).FillBytes(buf)
+		_, _ = h.Write(buf)
+	}
+}
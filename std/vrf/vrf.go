@@ -0,0 +1,139 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vrf provides a ZKP-circuit function to verify an elliptic curve
+// verifiable random function (ECVRF) proof on a twisted Edwards curve, and its
+// native (out of circuit) counterpart.
+//
+// The construction is the one of RFC 9381, with the hash functions adapted to
+// circuits. With the private key x and the public key Y = [x]B, the proof of
+// the input α (bytes) is (Γ, c, s) where
+//
+//	H = encode_to_curve(Y ‖ α)  (std/hash/hashtocurve, Y compressed)
+//	Γ = [x]H
+//	c = Hash(2, Y, H, Γ, [k]B, [k]H)  for a nonce k
+//	s = k + c·x mod the order of B
+//
+// and the output is β = Hash(3, [h]Γ), h being the cofactor. Hash is a hash
+// function on field elements chosen by the caller (for example std/hash/mimc),
+// the points being written as their two coordinates.
+package vrf
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/hashtocurve"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// encodeToCurveDST is the domain separation tag of the hash to curve
+var encodeToCurveDST = []byte("ECVRF_XMD:SHA-256_ELL2_NU_gnark")
+
+// first elements written in the hashes, as the domain separators of RFC 9381
+// (sections 5.2 and 5.4.3)
+const (
+	challengeDomainSeparator = 2
+	outputDomainSeparator    = 3
+)
+
+// PublicKey stores a VRF public key (to be used in gnark circuit)
+type PublicKey struct {
+	Y twistededwards.Point
+}
+
+// Proof stores a VRF proof (to be used in gnark circuit). C is an output of
+// the hash function and S is reduced modulo the order of the subgroup, hence
+// both fit in a variable.
+type Proof struct {
+	Gamma twistededwards.Point
+	C, S  frontend.Variable
+}
+
+// Verify verifies the proof of the input alpha (bytes) under pubKey and
+// returns the output β of the VRF, the challenge and the output being computed
+// with hash. The public key must not be of small order, and S must be smaller
+// than the order of the subgroup.
+func Verify(curve twistededwards.Curve, pubKey PublicKey, alpha []frontend.Variable, proof Proof, hash hash.Hash) (frontend.Variable, error) {
+	api := curve.API()
+	curve.AssertIsOnCurve(pubKey.Y)
+	curve.AssertIsOnCurve(proof.Gamma)
+	api.AssertIsDifferent(clearCofactor(curve, pubKey.Y).X, 0)
+
+	order := curve.Params().Order
+	sBits := api.ToBinary(proof.S, order.BitLen())
+	bits.AssertBitsLessOrEqual(api, sBits, new(big.Int).Sub(order, big.NewInt(1)))
+
+	// H = encode_to_curve(Y ‖ α)
+	msg := append(twistededwards.Compress(curve, pubKey.Y), alpha...)
+	H, err := hashtocurve.EncodeToTwistedEdwards(curve, msg, encodeToCurveDST)
+	if err != nil {
+		return nil, err
+	}
+
+	base := twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+
+	// U = [s]B-[c]Y, V = [s]H-[c]Γ
+	U := curve.DoubleBaseScalarMul(base, curve.Neg(pubKey.Y), proof.S, proof.C)
+	V := curve.DoubleBaseScalarMul(H, curve.Neg(proof.Gamma), proof.S, proof.C)
+
+	// c == Hash(2, Y, H, Γ, U, V)
+	hash.Reset()
+	hash.Write(challengeDomainSeparator)
+	for _, p := range []twistededwards.Point{pubKey.Y, H, proof.Gamma, U, V} {
+		hash.Write(p.X, p.Y)
+	}
+	api.AssertIsEqual(hash.Sum(), proof.C)
+
+	return ProofToHash(curve, proof, hash), nil
+}
+
+// ProofToHash returns the output β = Hash(3, [h]Γ) of the VRF given by proof,
+// which is not verified
+func ProofToHash(curve twistededwards.Curve, proof Proof, hash hash.Hash) frontend.Variable {
+	gamma := clearCofactor(curve, proof.Gamma)
+	hash.Reset()
+	hash.Write(outputDomainSeparator, gamma.X, gamma.Y)
+	return hash.Sum()
+}
+
+// clearCofactor returns [h]p, h being the cofactor of the curve (a power of 2
+// for all the curves of std/algebra/twistededwards)
+func clearCofactor(curve twistededwards.Curve, p twistededwards.Point) twistededwards.Point {
+	for i := 1; i < curve.Params().Cofactor.BitLen(); i++ {
+		p = curve.Double(p)
+	}
+	return p
+}
+
+// Assign sets the in-circuit public key from a native public key
+func (p *PublicKey) Assign(pk NativePublicKey) {
+	p.Y.X = pk.Y[0]
+	p.Y.Y = pk.Y[1]
+}
+
+// Assign sets the in-circuit proof from a native proof
+func (p *Proof) Assign(proof NativeProof) {
+	p.Gamma.X = proof.Gamma[0]
+	p.Gamma.Y = proof.Gamma[1]
+	p.C = proof.C
+	p.S = proof.S
+}
@@ -0,0 +1,201 @@
+package vrf
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const alphaLen = 32
+
+type vrfCircuit struct {
+	curveID   tedwards.ID
+	PublicKey PublicKey
+	Proof     Proof
+	Alpha     []frontend.Variable
+	Beta      frontend.Variable `gnark:",public"`
+}
+
+func (circuit *vrfCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	mimc, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	beta, err := Verify(curve, circuit.PublicKey, circuit.Alpha, circuit.Proof, &mimc)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(beta, circuit.Beta)
+	return nil
+}
+
+var testCases = []struct {
+	name  string
+	hash  hash.Hash
+	curve tedwards.ID
+}{
+	{"BN254", hash.MIMC_BN254, tedwards.BN254},
+	{"BLS12_381", hash.MIMC_BLS12_381, tedwards.BLS12_381},
+	{"BLS12_381_BANDERSNATCH", hash.MIMC_BLS12_381, tedwards.BLS12_381_BANDERSNATCH},
+	{"BLS12_377", hash.MIMC_BLS12_377, tedwards.BLS12_377},
+	{"BW6_761", hash.MIMC_BW6_761, tedwards.BW6_761},
+	{"BLS24_315", hash.MIMC_BLS24_315, tedwards.BLS24_315},
+	{"BW6_633", hash.MIMC_BW6_633, tedwards.BW6_633},
+}
+
+func randomAlpha(t *testing.T) []byte {
+	alpha := make([]byte, alphaLen)
+	if _, err := rand.Read(alpha); err != nil {
+		t.Fatal(err)
+	}
+	return alpha
+}
+
+func assignBytes(buf []byte) []frontend.Variable {
+	res := make([]frontend.Variable, len(buf))
+	for i := range buf {
+		res[i] = buf[i]
+	}
+	return res
+}
+
+func TestNative(t *testing.T) {
+	for _, tc := range testCases {
+		sk, err := GenerateKey(tc.curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk := sk.PublicKey
+		alpha := randomAlpha(t)
+
+		proof, err := sk.Prove(alpha, tc.hash.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok, err := pk.Verify(alpha, proof, tc.hash.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal(tc.name, ": valid proof rejected")
+		}
+
+		// the proof is deterministic
+		proof2, err := sk.Prove(alpha, tc.hash.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proof2.C.Cmp(proof.C) != 0 || proof2.S.Cmp(proof.S) != 0 {
+			t.Fatal(tc.name, ": proof is not deterministic")
+		}
+
+		alpha[0] ^= 1
+		ok, err = pk.Verify(alpha, proof, tc.hash.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatal(tc.name, ": proof of another input accepted")
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	for _, tc := range testCases {
+		snarkCurve, err := twistededwards.GetSnarkCurve(tc.curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sk, err := GenerateKey(tc.curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk := sk.PublicKey
+		alpha := randomAlpha(t)
+		proof, err := sk.Prove(alpha, tc.hash.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		beta, err := pk.ProofToHash(proof, tc.hash.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var witness vrfCircuit
+		witness.PublicKey.Assign(pk)
+		witness.Proof.Assign(proof)
+		witness.Alpha = assignBytes(alpha)
+		witness.Beta = beta
+
+		// the SHA-256 compressions of the hash to curve make the circuit large,
+		// only the solver is run
+		circuit := vrfCircuit{curveID: tc.curve, Alpha: make([]frontend.Variable, alphaLen)}
+		if err := test.IsSolved(&circuit, &witness, snarkCurve, backend.UNKNOWN); err != nil {
+			t.Fatal(tc.name, err)
+		}
+
+		// wrong output
+		witness.Beta = proof.C
+		if err := test.IsSolved(&circuit, &witness, snarkCurve, backend.UNKNOWN); err == nil {
+			t.Fatal(tc.name, ": wrong output accepted")
+		}
+		witness.Beta = beta
+
+		// proof of another input
+		alpha[0] ^= 1
+		witness.Alpha = assignBytes(alpha)
+		if err := test.IsSolved(&circuit, &witness, snarkCurve, backend.UNKNOWN); err == nil {
+			t.Fatal(tc.name, ": proof of another input accepted")
+		}
+	}
+}
+
+func TestVerifyNonCanonicalResponse(t *testing.T) {
+	id := tedwards.BN254
+	sk, err := GenerateKey(id, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alpha := randomAlpha(t)
+	proof, err := sk.Prove(alpha, hash.MIMC_BN254.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	beta, err := sk.PublicKey.ProofToHash(proof, hash.MIMC_BN254.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// s + order gives the same points, but must be rejected
+	params, err := twistededwards.GetCurveParams(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof.S.Add(proof.S, params.Order)
+	if ok, _ := sk.PublicKey.Verify(alpha, proof, hash.MIMC_BN254.New()); ok {
+		t.Fatal("non canonical response accepted")
+	}
+
+	var witness vrfCircuit
+	witness.PublicKey.Assign(sk.PublicKey)
+	witness.Proof.Assign(proof)
+	witness.Alpha = assignBytes(alpha)
+	witness.Beta = beta
+	circuit := vrfCircuit{curveID: id, Alpha: make([]frontend.Variable, alphaLen)}
+	if err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN); err == nil {
+		t.Fatal("non canonical response accepted")
+	}
+}